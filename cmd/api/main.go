@@ -19,16 +19,27 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	_ "github.com/whatup1359/fiber-ecommerce-api/docs"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/exchangerates"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/http/handlers"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/http/middleware"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/http/routes"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/jobs"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/mail"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/messaging"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/payments"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/storage"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/tokens"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/config"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/events"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/services"
 )
 
@@ -45,26 +56,171 @@ func main() {
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db)
 	roleRepo := repositories.NewRoleRepository(db)
+	permissionRepo := repositories.NewPermissionRepository(db)
 
 	categoryRepo := repositories.NewCategoryRepository(db)
 	productRepo := repositories.NewProductRepository(db)
 	cartRepo := repositories.NewCartRepository(db)
 	orderRepo := repositories.NewOrderRepository(db)
+	inventoryRepo := repositories.NewInventoryRepository(db)
 	transactionRepo := repositories.NewTransactionRepository(db)
 	statsRepo := repositories.NewStatsRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	taxRepo := repositories.NewTaxRepository(db)
+	couponRepo := repositories.NewCouponRepository(db)
+	shippingRepo := repositories.NewShippingRepository(db)
+	addressRepo := repositories.NewAddressRepository(db)
+	wishlistRepo := repositories.NewWishlistRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+
+	// Initialize event sinks & outbox dispatcher
+	inProcessSink := messaging.NewInProcessSink()
+	sinks := []events.Sink{inProcessSink, messaging.NewWebhookFanoutSink(webhookRepo)}
+	if cfg.EventLogSink {
+		sinks = append(sinks, messaging.NewLogSink())
+	}
+	if cfg.EventWebhookURL != "" {
+		sinks = append(sinks, messaging.NewWebhookSink(cfg.EventWebhookURL, 0))
+	}
+	dispatcher := messaging.NewDispatcher(outboxRepo, messaging.DispatcherConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		MaxAttempts:  cfg.OutboxMaxAttempts,
+	}, sinks...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	webhookWorker := messaging.NewWebhookWorker(webhookRepo, messaging.WebhookWorkerConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+	})
+	go webhookWorker.Run(ctx)
+
+	reservationSweeper := jobs.NewReservationSweeper(inventoryRepo, jobs.ReservationSweeperConfig{
+		Interval: cfg.ReservationSweepInterval,
+	})
+	go reservationSweeper.Run(ctx)
+
+	// อัตราแลกเปลี่ยนสำหรับตะกร้าและคำสั่งซื้อที่ไม่ใช่สกุลเงินหลัก
+	exchangeRates, err := exchangerates.NewStaticProvider(cfg.ExchangeRatesFile)
+	if err != nil {
+		log.Fatalf("Failed to load exchange rates: %v", err)
+	}
+
+	// ที่เก็บไฟล์อัปโหลด (รูปโปรไฟล์) ให้บริการที่ /uploads
+	fileStorage, err := storage.NewLocalStorage(cfg.UploadDir, cfg.AppURL+"/uploads")
+	if err != nil {
+		log.Fatalf("Failed to prepare upload storage: %v", err)
+	}
+
+	// ที่เก็บ access token ที่ถูกยกเลิกก่อนหมดอายุ ตาม TOKEN_REVOCATION_STORE
+	var tokenRevocations gateways.TokenRevocationStore
+	switch cfg.TokenRevocationStore {
+	case "memory":
+		tokenRevocations = tokens.NewMemoryRevocationStore()
+	default:
+		tokenRevocations = tokens.NewPostgresRevocationStore(db)
+	}
+
+	// กุญแจลงนาม access token (kid ใน header) และกุญแจเก่าที่ยังตรวจสอบได้ระหว่างหมุนกุญแจ
+	tokenIssuer, err := tokens.NewJWTIssuer(tokens.JWTIssuerConfig{
+		SigningKeyFile:       cfg.JWTPrivateKeyFile,
+		VerificationKeyFiles: cfg.JWTVerificationKeyFiles,
+		Issuer:               cfg.JWTIssuer,
+	})
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	if cfg.JWTPrivateKeyFile == "" {
+		log.Printf("Warning: JWT_PRIVATE_KEY_FILE is not set, using an ephemeral Ed25519 key %s (tokens are invalidated on restart)", tokenIssuer.SigningKeyID())
+	}
+
+	// อีเมลแจ้งเตือน: สร้างจากเทมเพลตแล้วส่งผ่านคิวตาม MAIL_DRIVER
+	mailRenderer, err := mail.NewRenderer(cfg.MailFrom, cfg.MailShopName)
+	if err != nil {
+		log.Fatalf("Failed to load mail templates: %v", err)
+	}
+	var mailTransport mail.Transport
+	switch cfg.MailDriver {
+	case "smtp":
+		mailTransport = mail.NewSMTPTransport(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		})
+	case "file":
+		if mailTransport, err = mail.NewFileTransport(cfg.MailFileDir); err != nil {
+			log.Fatalf("Failed to prepare mail directory: %v", err)
+		}
+	case "memory":
+		mailTransport = mail.NewMailbox()
+	default:
+		mailTransport = mail.NewConsoleTransport()
+	}
+	mailQueue := mail.NewQueue(mailRenderer, mailTransport, mail.QueueConfig{
+		Size:        cfg.MailQueueSize,
+		Workers:     cfg.MailWorkers,
+		MaxAttempts: cfg.MailMaxAttempts,
+	})
+	go mailQueue.Run(ctx)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, roleRepo)
-	userService := services.NewUserService(userRepo)
+	notificationService := services.NewNotificationService(mailQueue, userRepo, orderRepo, cfg.MailLinkBaseURL)
+	for _, eventType := range []string{
+		entities.EventOrderCreated,
+		entities.EventOrderShippingStatusChanged,
+		entities.EventPaymentCompleted,
+		entities.EventProductBackInStock,
+	} {
+		inProcessSink.Subscribe(eventType, notificationService.HandleEvent)
+	}
+
+	emailVerification := entities.EmailVerificationSettings{
+		Required: cfg.EmailVerificationRequired,
+		TokenTTL: cfg.EmailVerificationTTL,
+	}
+	tokenSettings := entities.TokenSettings{
+		AccessTokenTTL:  cfg.JWTExpiresIn,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	}
+	authService := services.NewAuthService(userRepo, roleRepo, sessionRepo, tokenRevocations, tokenIssuer, notificationService, emailVerification, tokenSettings)
+	userService := services.NewUserService(userRepo, fileStorage, tokenRevocations, notificationService, int64(cfg.AvatarMaxBytes), cfg.EmailChangeTTL)
 	categoryService := services.NewCategoryService(categoryRepo)
-	productService := services.NewProductService(productRepo)
-	cartService := services.NewCartService(cartRepo)
-	orderService := services.NewOrderService(orderRepo)
-	paymentService := services.NewPaymentService(transactionRepo)
+	productService := services.NewProductService(productRepo, inventoryRepo)
+	taxSettings := entities.TaxSettings{
+		PricesIncludeTax: cfg.TaxPricesIncludeTax,
+		DefaultRegion:    cfg.TaxDefaultRegion,
+	}
+	cartService := services.NewCartService(cartRepo, couponRepo, shippingRepo, exchangeRates, taxSettings, cfg.CartHoldTTL)
+	paymentDeadline := entities.PaymentDeadlineSettings{
+		Timeout:         cfg.OrderPaymentTimeout,
+		DeferredMethods: cfg.DeferredPaymentMethods,
+	}
+	orderService := services.NewOrderService(orderRepo, cartRepo, taxRepo, shippingRepo, addressRepo, userRepo, exchangeRates, taxSettings, emailVerification, paymentDeadline)
+	// mock gateway รับ callback จาก route สาธารณะ จึงลงทะเบียนเฉพาะเมื่อเลือกใช้และไม่ใช่ production
+	var paymentGateways []gateways.PaymentGateway
+	if cfg.PaymentProvider == "mock" && cfg.AppEnv != "production" {
+		paymentGateways = append(paymentGateways, payments.NewMockGateway(cfg.MockPaymentWebhookSecret))
+	}
+	// ทุกการชำระเงินใหม่ใช้ gateway ของ PAYMENT_PROVIDER จึงต้องมีตั้งแต่เริ่มระบบ ไม่ใช่ไปล้มเหลวตอนรับชำระเงิน
+	if !hasPaymentGateway(paymentGateways, cfg.PaymentProvider) {
+		log.Fatalf("No payment gateway registered for PAYMENT_PROVIDER=%q", cfg.PaymentProvider)
+	}
+	paymentService := services.NewPaymentService(transactionRepo, orderRepo, cfg.PaymentProvider, paymentGateways...)
 	statsService := services.NewStatsService(statsRepo)
+	webhookService := services.NewWebhookService(webhookRepo)
+	rbacService := services.NewRBACService(roleRepo, permissionRepo, userRepo, tokenRevocations, cfg.PermissionCacheTTL)
+	taxService := services.NewTaxService(taxRepo)
+	couponService := services.NewCouponService(couponRepo)
+	shippingService := services.NewShippingService(shippingRepo)
+	addressService := services.NewAddressService(addressRepo)
+	wishlistService := services.NewWishlistService(wishlistRepo, productRepo, cartService)
 
 	// Initialize middleware
-	authMW := middleware.NewAuthMiddleware(cfg.JWTSecret)
+	authMW := middleware.NewAuthMiddleware(tokenIssuer, rbacService, tokenRevocations)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, userService)
@@ -77,6 +233,13 @@ func main() {
 	orderHandler := handlers.NewOrderHandler(orderService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
 	statsHandler := handlers.NewStatsHandler(statsService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	rbacHandler := handlers.NewRBACHandler(rbacService)
+	taxHandler := handlers.NewTaxHandler(taxService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	shippingHandler := handlers.NewShippingHandler(shippingService)
+	addressHandler := handlers.NewAddressHandler(addressService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -87,6 +250,9 @@ func main() {
 		},
 	})
 
+	// ไฟล์อัปโหลดจาก LocalStorage
+	app.Static("/uploads", cfg.UploadDir)
+
 	// Initialize routes
 	routes := routes.NewRoutes(
 		authHandler,
@@ -97,6 +263,13 @@ func main() {
 		orderHandler,
 		paymentHandler,
 		statsHandler,
+		webhookHandler,
+		rbacHandler,
+		taxHandler,
+		couponHandler,
+		shippingHandler,
+		addressHandler,
+		wishlistHandler,
 		authMW,
 	)
 	routes.SetupRoutes(app)
@@ -104,4 +277,14 @@ func main() {
 	// Start server
 	log.Printf("Server starting on port %s", cfg.AppPort)
 	log.Fatal(app.Listen(":" + cfg.AppPort))
-}
\ No newline at end of file
+}
+
+// hasPaymentGateway ตรวจว่ามี gateway ที่ลงทะเบียนไว้สำหรับ provider นี้
+func hasPaymentGateway(paymentGateways []gateways.PaymentGateway, provider string) bool {
+	for _, gateway := range paymentGateways {
+		if gateway.Name() == provider {
+			return true
+		}
+	}
+	return false
+}
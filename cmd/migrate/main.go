@@ -10,15 +10,28 @@ import (
 
 func main() {
 	var (
-		up   = flag.Bool("up", false, "Run migrations")
-		down = flag.Bool("down", false, "Rollback migrations (not implemented yet)")
+		up     = flag.Bool("up", false, "Run pending migrations")
+		down   = flag.Bool("down", false, "Rollback migrations (1 step by default)")
+		status = flag.Bool("status", false, "Show migration status")
+		to     = flag.Int64("to", -1, "Migrate up or down to the given version (0 rolls back everything)")
+		steps  = flag.Int("steps", 0, "Number of migrations to apply or roll back")
+		noSeed = flag.Bool("no-seed", false, "Skip seeding after -up")
 	)
 	flag.Parse()
 
-	if !*up && !*down {
+	modes := 0
+	for _, selected := range []bool{*up, *down, *status, *to >= 0} {
+		if selected {
+			modes++
+		}
+	}
+
+	if modes != 1 {
 		log.Println("Usage:")
-		log.Println("  go run cmd/migrate/main.go -up    # Run migrations")
-		log.Println("  go run cmd/migrate/main.go -down  # Rollback migrations")
+		log.Println("  go run cmd/migrate/main.go -up [-steps N]    # Run pending migrations")
+		log.Println("  go run cmd/migrate/main.go -down [-steps N]  # Rollback migrations (default 1 step)")
+		log.Println("  go run cmd/migrate/main.go -to <version>     # Migrate to a specific version")
+		log.Println("  go run cmd/migrate/main.go -status           # Show migration status")
 		os.Exit(1)
 	}
 
@@ -28,27 +41,60 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Setup database connection
-	db := config.SetupDatabase(cfg)
+	// Connect database (ไม่ auto-migrate เพื่อให้ CLI ควบคุมเวอร์ชันเอง)
+	db := config.OpenDatabase(cfg)
 
-	if *up {
-		log.Println("Running database migrations...")
-		err := config.RunMigrationManual(cfg)
+	migrator, err := config.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	switch {
+	case *status:
+		statuses, err := migrator.Status()
 		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				log.Printf("  [x] %06d_%s (applied %s)", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				log.Printf("  [ ] %06d_%s", s.Version, s.Name)
+			}
+		}
+
+	case *up:
+		log.Println("Running database migrations...")
+		if err := migrator.Up(*steps); err != nil {
 			log.Fatalf("Migration failed: %v", err)
 		}
 		log.Println("Migration completed successfully!")
 
-		// Seed database after migration
-		log.Println("Seeding database...")
-		if err := config.SeedDatabase(db, cfg); err != nil {
-			log.Fatalf("Failed to seed database: %v", err)
+		if !*noSeed {
+			// Seed database after migration
+			log.Println("Seeding database...")
+			if err := config.SeedDatabase(db, cfg); err != nil {
+				log.Fatalf("Failed to seed database: %v", err)
+			}
 		}
 		log.Println("🎉 All database operations completed successfully!")
-	}
 
-	if *down {
-		log.Println("Rollback migrations not implemented yet")
-		// TODO: Implement rollback functionality
+	case *down:
+		n := *steps
+		if n <= 0 {
+			n = 1
+		}
+		log.Printf("Rolling back %d migration(s)...", n)
+		if err := migrator.Down(n); err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		log.Println("Rollback completed successfully!")
+
+	case *to >= 0:
+		log.Printf("Migrating to version %d...", *to)
+		if err := migrator.To(*to); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Println("Migration completed successfully!")
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,85 @@
+package exchangerates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// StaticFile รูปแบบไฟล์อัตราแลกเปลี่ยน อัตราทั้งหมดเทียบกับสกุล base
+//
+//	{"base":"THB","rates":{"USD":"0.027500","MYR":"0.130000"}}
+type StaticFile struct {
+	Base  entities.Currency                           `json:"base"`
+	Rates map[entities.Currency]entities.ExchangeRate `json:"rates"`
+}
+
+// StaticProvider อัตราแลกเปลี่ยนคงที่จากไฟล์ JSON โหลดครั้งเดียวตอนเริ่มระบบ
+// อัตราระหว่างสกุลที่ไม่ใช่ base คำนวณแบบ cross rate ผ่านสกุล base
+type StaticProvider struct {
+	base  entities.Currency
+	rates map[entities.Currency]entities.ExchangeRate
+}
+
+// NewStaticProvider โหลดอัตราจาก path หาก path ว่างจะรองรับเฉพาะสกุลเงินหลักของร้าน
+func NewStaticProvider(path string) (*StaticProvider, error) {
+	provider := &StaticProvider{
+		base: entities.DefaultCurrency,
+		rates: map[entities.Currency]entities.ExchangeRate{
+			entities.DefaultCurrency: entities.IdentityRate,
+		},
+	}
+	if path == "" {
+		return provider, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exchange rates file: %w", err)
+	}
+
+	var file StaticFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse exchange rates file: %w", err)
+	}
+
+	base, err := entities.ParseCurrency(string(file.Base))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base currency %q in exchange rates file", file.Base)
+	}
+
+	provider.base = base
+	provider.rates = map[entities.Currency]entities.ExchangeRate{base: entities.IdentityRate}
+	for code, rate := range file.Rates {
+		currency, err := entities.ParseCurrency(string(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency %q in exchange rates file", code)
+		}
+		provider.rates[currency] = rate
+	}
+
+	return provider, nil
+}
+
+func (p *StaticProvider) Rate(ctx context.Context, base, quote entities.Currency) (entities.ExchangeRate, error) {
+	if base == quote {
+		return entities.IdentityRate, nil
+	}
+
+	baseRate, ok := p.rates[base]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", entities.ErrUnsupportedCurrency, base)
+	}
+	quoteRate, ok := p.rates[quote]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", entities.ErrUnsupportedCurrency, quote)
+	}
+
+	return entities.CrossRate(baseRate, quoteRate), nil
+}
+
+var _ gateways.ExchangeRateProvider = (*StaticProvider)(nil)
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// currentActor สร้าง Actor จากข้อมูลที่ AuthRequired ใส่ไว้ใน context
+func currentActor(c *fiber.Ctx) entities.Actor {
+	userID, _ := c.Locals("userID").(uuid.UUID)
+	role, _ := c.Locals("role").(string)
+	permissions, _ := c.Locals("permissions").([]string)
+
+	return entities.Actor{
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
+	}
+}
+
+// currentSessionID session ของ access token ที่ใช้ทำรายการ (uuid.Nil หาก token ไม่มี sid)
+func currentSessionID(c *fiber.Ctx) uuid.UUID {
+	sessionID, _ := c.Locals("sessionID").(uuid.UUID)
+	return sessionID
+}
+
+// currentAccessToken access token ที่ใช้ทำรายการ (jti, session และเวลาหมดอายุ)
+func currentAccessToken(c *fiber.Ctx) entities.AccessToken {
+	token, _ := c.Locals("accessToken").(entities.AccessToken)
+	return token
+}
+
+// accessDenied แปลง error การตรวจสิทธิ์ความเป็นเจ้าของเป็น status 404 หรือ 403 (รวมบัญชีที่ยังไม่ยืนยันอีเมล)
+func accessDenied(err error, notFoundMessage string) (int, entities.ApiResponse, bool) {
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		return fiber.StatusNotFound, entities.ApiResponse{
+			Success: false,
+			Message: notFoundMessage,
+		}, true
+	case errors.Is(err, entities.ErrForbidden), errors.Is(err, entities.ErrEmailNotVerified):
+		return fiber.StatusForbidden, entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, true
+	default:
+		return 0, entities.ApiResponse{}, false
+	}
+}
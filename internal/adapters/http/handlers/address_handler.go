@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type AddressHandler struct {
+	addressService services.AddressService
+}
+
+func NewAddressHandler(addressService services.AddressService) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+	}
+}
+
+// GetAddresses ดูสมุดที่อยู่
+// @Summary ดูสมุดที่อยู่
+// @Description ดูที่อยู่ทั้งหมดของผู้ใช้ปัจจุบัน ที่อยู่เริ่มต้นอยู่ก่อน
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Address}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/addresses [get]
+func (h *AddressHandler) GetAddresses(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	addresses, err := h.addressService.GetAddresses(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลที่อยู่ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลที่อยู่สำเร็จ",
+		Data:    addresses,
+	})
+}
+
+// GetAddress ดูที่อยู่ตาม ID
+// @Summary ดูที่อยู่ตาม ID
+// @Description ดูที่อยู่ในสมุดที่อยู่ของผู้ใช้ปัจจุบัน
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/addresses/{id} [get]
+func (h *AddressHandler) GetAddress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	address, err := h.addressService.GetAddress(c.Context(), userID, id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบที่อยู่",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลที่อยู่สำเร็จ",
+		Data:    address,
+	})
+}
+
+// CreateAddress เพิ่มที่อยู่
+// @Summary เพิ่มที่อยู่
+// @Description เพิ่มที่อยู่ลงสมุดที่อยู่ ที่อยู่แรกเป็นที่อยู่จัดส่งและออกใบเสร็จเริ่มต้นโดยอัตโนมัติ
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateAddressRequest true "ข้อมูลที่อยู่"
+// @Success 201 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/addresses [post]
+func (h *AddressHandler) CreateAddress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.CreateAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	address, err := h.addressService.CreateAddress(c.Context(), userID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถเพิ่มที่อยู่ได้",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "เพิ่มที่อยู่สำเร็จ",
+		Data:    address,
+	})
+}
+
+// UpdateAddress แก้ไขที่อยู่
+// @Summary แก้ไขที่อยู่
+// @Description แก้ไขที่อยู่หรือกำหนดเป็นที่อยู่เริ่มต้น คำสั่งซื้อเดิมเก็บสำเนาที่อยู่ไว้แล้วจึงไม่เปลี่ยนตาม
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param request body entities.UpdateAddressRequest true "ข้อมูลการแก้ไขที่อยู่"
+// @Success 200 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/addresses/{id} [put]
+func (h *AddressHandler) UpdateAddress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	address, err := h.addressService.UpdateAddress(c.Context(), userID, id, &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบที่อยู่"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถแก้ไขที่อยู่ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "แก้ไขที่อยู่สำเร็จ",
+		Data:    address,
+	})
+}
+
+// DeleteAddress ลบที่อยู่
+// @Summary ลบที่อยู่
+// @Description ลบที่อยู่ออกจากสมุดที่อยู่ สำเนาที่อยู่ในคำสั่งซื้อเดิมยังอยู่ครบ
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/addresses/{id} [delete]
+func (h *AddressHandler) DeleteAddress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.addressService.DeleteAddress(c.Context(), userID, id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบที่อยู่"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบที่อยู่ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบที่อยู่สำเร็จ",
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -122,6 +123,7 @@ func (h *AuthHandler) AdminRegister(c *fiber.Ctx) error {
 // @Param request body entities.LoginRequest true "ข้อมูลการเข้าสู่ระบบ"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ErrorResponse
+// @Failure 403 {object} entities.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req entities.LoginRequest
@@ -141,8 +143,15 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.authService.Login(c.Context(), &req)
+	response, err := h.authService.Login(c.Context(), &req, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, entities.ErrEmailNotVerified) {
+			return c.Status(fiber.StatusForbidden).JSON(entities.ErrorResponse{
+				Success: false,
+				Message: "ไม่สามารถเข้าสู่ระบบได้",
+				Error:   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(entities.ErrorResponse{
 			Success: false,
 			Message: "ไม่สามารถเข้าสู่ระบบได้",
@@ -159,13 +168,15 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 // RefreshToken รีเฟรช token
 // @Summary รีเฟรช token
-// @Description รีเฟรช JWT token ด้วย refresh token
+// @Description รีเฟรช JWT token ด้วย refresh token ระบบออก refresh token ใหม่ทุกครั้ง การใช้ refresh token เก่าซ้ำจะยกเลิก session นั้น
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body entities.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 403 {object} entities.ErrorResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	var req entities.RefreshTokenRequest
@@ -185,8 +196,15 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.authService.RefreshToken(c.Context(), &req)
+	response, err := h.authService.RefreshToken(c.Context(), &req, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, entities.ErrEmailNotVerified) {
+			return c.Status(fiber.StatusForbidden).JSON(entities.ErrorResponse{
+				Success: false,
+				Message: "ไม่สามารถรีเฟรช token ได้",
+				Error:   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(entities.ErrorResponse{
 			Success: false,
 			Message: "ไม่สามารถรีเฟรช token ได้",
@@ -203,7 +221,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 
 // Logout ออกจากระบบ
 // @Summary ออกจากระบบ
-// @Description ออกจากระบบและลบ refresh token
+// @Description ออกจากระบบและยกเลิก session ของอุปกรณ์นี้ (access token และ refresh token ของอุปกรณ์นี้ใช้ไม่ได้อีก)
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -212,18 +230,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 // @Failure 401 {object} entities.ErrorResponse
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
-			Success: false,
-			Message: "รูปแบบ user ID ไม่ถูกต้อง",
-			Error:   err.Error(),
-		})
-	}
-
-	if err := h.authService.Logout(c.Context(), id); err != nil {
+	if err := h.authService.Logout(c.Context(), currentAccessToken(c)); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ErrorResponse{
 			Success: false,
 			Message: "ไม่สามารถออกจากระบบได้",
@@ -239,7 +246,7 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 
 // ChangePassword เปลี่ยนรหัสผ่าน
 // @Summary เปลี่ยนรหัสผ่าน
-// @Description เปลี่ยนรหัสผ่านของผู้ใช้
+// @Description เปลี่ยนรหัสผ่านของผู้ใช้ แล้วออกจากระบบทุกอุปกรณ์ (ต้องเข้าสู่ระบบใหม่ด้วยรหัสผ่านใหม่)
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -266,17 +273,9 @@ func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 		})
 	}
 
-	userID := c.Locals("userID").(string)
-	id, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
-			Success: false,
-			Message: "รูปแบบ user ID ไม่ถูกต้อง",
-			Error:   err.Error(),
-		})
-	}
+	actor := currentActor(c)
 
-	if err := h.authService.ChangePassword(c.Context(), id, &req); err != nil {
+	if err := h.authService.ChangePassword(c.Context(), actor.UserID, &req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
 			Success: false,
 			Message: "ไม่สามารถเปลี่ยนรหัสผ่านได้",
@@ -374,6 +373,97 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	})
 }
 
+// VerifyEmail ยืนยันอีเมล
+// @Summary ยืนยันอีเมล
+// @Description ยืนยันอีเมลด้วย token จากอีเมลยืนยัน (token ใช้ได้ครั้งเดียว)
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body entities.VerifyEmailRequest true "token ยืนยันอีเมล"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req entities.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+			Error:   err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ครบถ้วน",
+			Error:   err.Error(),
+		})
+	}
+
+	user, err := h.authService.VerifyEmail(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ไม่สามารถยืนยันอีเมลได้",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ยืนยันอีเมลสำเร็จ",
+		Data:    user,
+	})
+}
+
+// ResendVerification ส่งอีเมลยืนยันอีกครั้ง
+// @Summary ส่งอีเมลยืนยันอีกครั้ง
+// @Description ส่งลิงก์ยืนยันอีเมลใหม่ token เดิมจะใช้ไม่ได้อีก (ขอได้ครั้งละ 1 นาที)
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body entities.ResendVerificationRequest true "อีเมลที่ต้องการยืนยัน"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 429 {object} entities.ErrorResponse
+// @Router /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	var req entities.ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+			Error:   err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ครบถ้วน",
+			Error:   err.Error(),
+		})
+	}
+
+	if err := h.authService.ResendVerification(c.Context(), &req); err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, entities.ErrVerificationRecentlySent) {
+			status = fiber.StatusTooManyRequests
+		}
+		return c.Status(status).JSON(entities.ErrorResponse{
+			Success: false,
+			Message: "ไม่สามารถส่งอีเมลยืนยันได้",
+			Error:   err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "หากอีเมลนี้ยังไม่ได้ยืนยัน ระบบได้ส่งลิงก์ยืนยันไปแล้ว",
+	})
+}
+
 // GetUsers ดูรายการผู้ใช้ทั้งหมด
 // @Summary ดูรายการผู้ใช้ทั้งหมด
 // @Description ดูรายการผู้ใช้ทั้งหมดในระบบ (เฉพาะ Admin)
@@ -529,4 +619,134 @@ func (h *AuthHandler) DeleteUser(c *fiber.Ctx) error {
 		Success: true,
 		Message: "ลบผู้ใช้สำเร็จ",
 	})
-}
\ No newline at end of file
+}
+
+// ListSessions ดูอุปกรณ์ที่เข้าสู่ระบบอยู่
+// @Summary ดูอุปกรณ์ที่เข้าสู่ระบบอยู่
+// @Description ดู session ที่ยังใช้งานได้ของผู้ใช้ปัจจุบัน ใช้งานล่าสุดอยู่ก่อน session ที่ใช้ทำรายการนี้มี current เป็น true
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Session}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/sessions [get]
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	actor := currentActor(c)
+
+	sessions, err := h.authService.ListSessions(c.Context(), actor.UserID, currentSessionID(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูล session ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูล session สำเร็จ",
+		Data:    sessions,
+	})
+}
+
+// RevokeSession ออกจากระบบอุปกรณ์ที่ระบุ
+// @Summary ออกจากระบบอุปกรณ์ที่ระบุ
+// @Description ยกเลิก session ของผู้ใช้ปัจจุบัน refresh token ของอุปกรณ์นั้นใช้ไม่ได้อีก
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	actor := currentActor(c)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.authService.RevokeSession(c.Context(), actor.UserID, id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบ session"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถยกเลิก session ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ยกเลิก session สำเร็จ",
+	})
+}
+
+// RevokeUserSessions ออกจากระบบทุกอุปกรณ์ของผู้ใช้
+// @Summary ออกจากระบบทุกอุปกรณ์ของผู้ใช้
+// @Description ยกเลิกทุก session และ access token ของผู้ใช้ที่ระบุ (สำหรับผู้ดูแล) ผู้ใช้ต้องเข้าสู่ระบบใหม่ทันที
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.RevokeSessionsResponse}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /users/{id}/sessions [delete]
+func (h *AuthHandler) RevokeUserSessions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	revoked, err := h.authService.RevokeAllSessions(c.Context(), id)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบผู้ใช้"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถยกเลิก session ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ออกจากระบบทุกอุปกรณ์สำเร็จ",
+		Data:    entities.RevokeSessionsResponse{Revoked: revoked},
+	})
+}
+
+// @Summary กุญแจสาธารณะสำหรับตรวจสอบ access token
+// @Description JSON Web Key Set (RFC 7517) ของกุญแจที่ลงนามและกุญแจเก่าที่ยังตรวจสอบได้ระหว่างหมุนกุญแจ เลือกกุญแจตาม kid ใน header ของ token (ตอบในรูปแบบ JWKS มาตรฐาน ไม่ห่อด้วย ApiResponse)
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} entities.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	// แคชสั้นๆ เพื่อให้กุญแจใหม่หลังหมุนกุญแจถูกดึงไปใช้ภายในไม่กี่นาที
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.authService.JWKS())
+}
+
+// clientInfo ข้อมูลอุปกรณ์ของคำขอสำหรับบันทึกใน session
+func clientInfo(c *fiber.Ctx) entities.ClientInfo {
+	return entities.ClientInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
+	}
+}
@@ -20,7 +20,7 @@ func NewCartHandler(cartService services.CartService) *CartHandler {
 
 // GetCart ดูตะกร้าสินค้า
 // @Summary ดูตะกร้าสินค้า
-// @Description ดูตะกร้าสินค้าของผู้ใช้ปัจจุบัน
+// @Description ดูตะกร้าสินค้าของผู้ใช้ปัจจุบัน พร้อมส่วนลดอัตโนมัติและส่วนลดจากคูปองแยกตามรายการ (total_price คือยอดหลังหักส่วนลด)
 // @Tags Cart
 // @Accept json
 // @Produce json
@@ -57,6 +57,7 @@ func (h *CartHandler) GetCart(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InsufficientStockError}
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /cart [post]
@@ -79,6 +80,12 @@ func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
 	}
 
 	if err := h.cartService.AddToCart(c.Context(), userID, &req); err != nil {
+		if resp, ok := insufficientStock(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถเพิ่มสินค้าลงตะกร้าได้",
@@ -93,7 +100,7 @@ func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
 
 // UpdateCartItem อัพเดทสินค้าในตะกร้า
 // @Summary อัพเดทสินค้าในตะกร้า
-// @Description อัพเดทจำนวนสินค้าในตะกร้า
+// @Description อัพเดทจำนวนสินค้าในตะกร้า (สินค้าในตะกร้าของผู้อื่นตอบ 404 ยกเว้นบทบาทที่มีสิทธิ์ carts:manage)
 // @Tags Cart
 // @Accept json
 // @Produce json
@@ -102,7 +109,9 @@ func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InsufficientStockError}
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /cart/{itemId} [put]
@@ -130,7 +139,13 @@ func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.cartService.UpdateCartItem(c.Context(), itemID, &req); err != nil {
+	if err := h.cartService.UpdateCartItem(c.Context(), currentActor(c), itemID, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสินค้าในตะกร้า"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if resp, ok := insufficientStock(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถอัพเดทสินค้าในตะกร้าได้",
@@ -145,7 +160,7 @@ func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
 
 // RemoveFromCart ลบสินค้าจากตะกร้า
 // @Summary ลบสินค้าจากตะกร้า
-// @Description ลบสินค้าออกจากตะกร้าสินค้า
+// @Description ลบสินค้าออกจากตะกร้าสินค้า (สินค้าในตะกร้าของผู้อื่นตอบ 404 ยกเว้นบทบาทที่มีสิทธิ์ carts:manage)
 // @Tags Cart
 // @Accept json
 // @Produce json
@@ -153,6 +168,7 @@ func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
@@ -166,7 +182,10 @@ func (h *CartHandler) RemoveFromCart(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.cartService.RemoveFromCart(c.Context(), itemID); err != nil {
+	if err := h.cartService.RemoveFromCart(c.Context(), currentActor(c), itemID); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสินค้าในตะกร้า"); ok {
+			return c.Status(status).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถลบสินค้าจากตะกร้าได้",
@@ -204,4 +223,172 @@ func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
 		Success: true,
 		Message: "ล้างตะกร้าสินค้าสำเร็จ",
 	})
-}
\ No newline at end of file
+}
+
+// SetCurrency เปลี่ยนสกุลเงินของตะกร้า
+// @Summary เปลี่ยนสกุลเงินของตะกร้า
+// @Description เปลี่ยนสกุลเงินของตะกร้าและคิดราคาสินค้าทุกรายการใหม่ (ใช้ราคาที่กำหนดไว้ของสกุลนั้น หรือแปลงจากราคาหลักด้วยอัตราแลกเปลี่ยน)
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body entities.SetCartCurrencyRequest true "สกุลเงิน"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /cart/currency [put]
+func (h *CartHandler) SetCurrency(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.SetCartCurrencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	cart, err := h.cartService.SetCurrency(c.Context(), userID, &req)
+	if err != nil {
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถเปลี่ยนสกุลเงินของตะกร้าได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "เปลี่ยนสกุลเงินของตะกร้าสำเร็จ",
+		Data:    cart,
+	})
+}
+
+// ApplyCoupon ใช้คูปองกับตะกร้า
+// @Summary ใช้คูปองกับตะกร้า
+// @Description กรอกรหัสคูปองให้ตะกร้า (แทนคูปองเดิม) ส่วนลดอัตโนมัติไม่ต้องกรอกรหัส ส่วนลดคิดใหม่อีกครั้งตอนสั่งซื้อ
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body entities.ApplyCouponRequest true "รหัสคูปอง"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /cart/coupon [post]
+func (h *CartHandler) ApplyCoupon(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.ApplyCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	cart, err := h.cartService.ApplyCoupon(c.Context(), userID, &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคูปอง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if resp, ok := couponError(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(resp)
+		}
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถใช้คูปองได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ใช้คูปองสำเร็จ",
+		Data:    cart,
+	})
+}
+
+// RemoveCoupon เอาคูปองออกจากตะกร้า
+// @Summary เอาคูปองออกจากตะกร้า
+// @Description เอาคูปองที่กรอกไว้ออกจากตะกร้า (ส่วนลดอัตโนมัติยังคงใช้อยู่)
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /cart/coupon [delete]
+func (h *CartHandler) RemoveCoupon(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	cart, err := h.cartService.RemoveCoupon(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถเอาคูปองออกได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "เอาคูปองออกสำเร็จ",
+		Data:    cart,
+	})
+}
+
+// GetShippingOptions ดูค่าจัดส่งของตะกร้า
+// @Summary ดูค่าจัดส่งของตะกร้า
+// @Description คิดค่าจัดส่งของทุกวิธีจัดส่งที่เปิดใช้งานจากน้ำหนักสินค้าและยอดหลังหักส่วนลดในสกุลเงินของตะกร้า วิธีที่ไม่รองรับภูมิภาคหรือน้ำหนักนี้จะไม่แสดง ค่าจัดส่งคิดใหม่อีกครั้งตอนสั่งซื้อ
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param region query string false "ภูมิภาคที่จัดส่ง เช่น TH หรือ TH-10 (ไม่ระบุใช้ค่าเริ่มต้นของร้าน)"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ShippingQuote}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /cart/shipping-options [get]
+func (h *CartHandler) GetShippingOptions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	options, err := h.cartService.ShippingOptions(c.Context(), userID, c.Query("region"))
+	if err != nil {
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถคิดค่าจัดส่งได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "คิดค่าจัดส่งสำเร็จ",
+		Data:    options,
+	})
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type CouponHandler struct {
+	couponService services.CouponService
+}
+
+func NewCouponHandler(couponService services.CouponService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+	}
+}
+
+// GetCoupons ดูคูปองทั้งหมด
+// @Summary ดูคูปองทั้งหมด
+// @Description ดูคูปองและส่วนลดอัตโนมัติทั้งหมดพร้อมจำนวนครั้งที่ใช้ไปแล้ว (ต้องมีสิทธิ์ coupons:manage)
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param page query int false "หน้าที่ต้องการ" default(1)
+// @Param limit query int false "จำนวนรายการต่อหน้า" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Coupon,pagination=entities.PaginationResponse}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /coupons [get]
+func (h *CouponHandler) GetCoupons(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	coupons, pagination, err := h.couponService.GetCoupons(c.Context(), page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลคูปองได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "ดึงข้อมูลคูปองสำเร็จ",
+		Data:       coupons,
+		Pagination: pagination,
+	})
+}
+
+// GetCouponByID ดูคูปองตาม ID
+// @Summary ดูคูปองตาม ID
+// @Description ดูรายละเอียดคูปองพร้อมสินค้าและหมวดหมู่ที่ร่วมรายการ (ต้องมีสิทธิ์ coupons:manage)
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param id path string true "Coupon ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Coupon}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /coupons/{id} [get]
+func (h *CouponHandler) GetCouponByID(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	coupon, err := h.couponService.GetCouponByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบคูปอง",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลคูปองสำเร็จ",
+		Data:    coupon,
+	})
+}
+
+// CreateCoupon สร้างคูปอง
+// @Summary สร้างคูปอง
+// @Description สร้างคูปองแบบเปอร์เซ็นต์หรือจำนวนเงินคงที่ พร้อมยอดขั้นต่ำ จำนวนครั้งที่ใช้ได้ ช่วงเวลา และสินค้า/หมวดหมู่ที่ร่วมรายการ จำนวนเงินเป็นสกุลเงินหลักของร้าน automatic=true คือส่วนลดอัตโนมัติที่ไม่ต้องกรอกรหัส (ต้องมีสิทธิ์ coupons:manage)
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateCouponRequest true "ข้อมูลคูปอง"
+// @Success 201 {object} entities.ApiResponse{data=entities.Coupon}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /coupons [post]
+func (h *CouponHandler) CreateCoupon(c *fiber.Ctx) error {
+	var req entities.CreateCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	coupon, err := h.couponService.CreateCoupon(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างคูปองสำเร็จ",
+		Data:    coupon,
+	})
+}
+
+// UpdateCoupon แก้ไขคูปอง
+// @Summary แก้ไขคูปอง
+// @Description แก้ไขเงื่อนไขของคูปอง ฟิลด์ที่ไม่ส่งจะไม่เปลี่ยน รหัสคูปองแก้ไขไม่ได้ (ต้องมีสิทธิ์ coupons:manage)
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param id path string true "Coupon ID"
+// @Param request body entities.UpdateCouponRequest true "ข้อมูลการแก้ไขคูปอง"
+// @Success 200 {object} entities.ApiResponse{data=entities.Coupon}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /coupons/{id} [put]
+func (h *CouponHandler) UpdateCoupon(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	coupon, err := h.couponService.UpdateCoupon(c.Context(), id, &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคูปอง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทคูปองสำเร็จ",
+		Data:    coupon,
+	})
+}
+
+// DeleteCoupon ลบคูปอง
+// @Summary ลบคูปอง
+// @Description ลบคูปองและเอาออกจากตะกร้าที่กรอกไว้ ประวัติการใช้กับคำสั่งซื้อเดิมยังคงอยู่ (ต้องมีสิทธิ์ coupons:manage)
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param id path string true "Coupon ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /coupons/{id} [delete]
+func (h *CouponHandler) DeleteCoupon(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.couponService.DeleteCoupon(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคูปอง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบคูปองได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบคูปองสำเร็จ",
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -22,14 +23,17 @@ func NewOrderHandler(orderService services.OrderService) *OrderHandler {
 
 // CreateOrder สร้างคำสั่งซื้อ
 // @Summary สร้างคำสั่งซื้อ
-// @Description สร้างคำสั่งซื้อใหม่จากตะกร้าสินค้า
+// @Description สร้างคำสั่งซื้อใหม่จากตะกร้าสินค้า shipping_method คือรหัสวิธีจัดส่งจาก GET /cart/shipping-options ค่าจัดส่งถูกตรึงไว้กับคำสั่งซื้อและรวมใน total_price
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Param request body entities.CreateOrderRequest true "ข้อมูลการสร้างคำสั่งซื้อ"
 // @Success 201 {object} entities.ApiResponse{data=entities.Order}
-// @Failure 400 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse "ข้อมูลไม่ถูกต้อง ไม่มีที่อยู่จัดส่ง หรือวิธีจัดส่งใช้ไม่ได้กับภูมิภาค/น้ำหนักนี้"
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse "ยังไม่ได้ยืนยันอีเมล (EMAIL_VERIFICATION_REQUIRED)"
+// @Failure 404 {object} entities.ApiResponse "ไม่พบที่อยู่ในสมุดที่อยู่"
+// @Failure 409 {object} entities.ApiResponse{data=entities.InsufficientStockError} "สต็อกไม่พอ หรือคูปองในตะกร้าใช้ไม่ได้แล้ว"
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /orders [post]
@@ -53,6 +57,21 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 
 	order, err := h.orderService.CreateOrder(c.Context(), userID, &req)
 	if err != nil {
+		if resp, ok := insufficientStock(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if resp, ok := couponError(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		if resp, ok := shippingError(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(resp)
+		}
+		if status, resp, ok := accessDenied(err, "ไม่พบที่อยู่"); ok {
+			return c.Status(status).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถสร้างคำสั่งซื้อได้",
@@ -109,7 +128,7 @@ func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
 
 // GetOrderByID ดูคำสั่งซื้อตาม ID
 // @Summary ดูคำสั่งซื้อตาม ID
-// @Description ดูรายละเอียดคำสั่งซื้อตาม ID
+// @Description ดูรายละเอียดคำสั่งซื้อตาม ID พร้อม timeline ประวัติการเปลี่ยนสถานะ
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -117,6 +136,7 @@ func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse{data=entities.Order}
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
@@ -130,8 +150,11 @@ func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
 		})
 	}
 
-	order, err := h.orderService.GetOrderByID(c.Context(), id)
+	order, err := h.orderService.GetOrderByID(c.Context(), currentActor(c), id)
 	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคำสั่งซื้อ"); ok {
+			return c.Status(status).JSON(resp)
+		}
 		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่พบคำสั่งซื้อ",
@@ -155,7 +178,9 @@ func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InvalidTransitionError}
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /orders/{id}/cancel [put]
@@ -168,7 +193,13 @@ func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.orderService.CancelOrder(c.Context(), id); err != nil {
+	if err := h.orderService.CancelOrder(c.Context(), currentActor(c), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคำสั่งซื้อ"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if resp, ok := transitionConflict(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถยกเลิกคำสั่งซื้อได้",
@@ -235,6 +266,7 @@ func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
 // @Failure 401 {object} entities.ApiResponse
 // @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InvalidTransitionError}
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /orders/admin/{id}/status [put]
@@ -262,7 +294,12 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.orderService.UpdateOrderStatus(c.Context(), id, &req); err != nil {
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	if err := h.orderService.UpdateOrderStatus(c.Context(), id, adminID, &req); err != nil {
+		if resp, ok := transitionConflict(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถอัพเดทสถานะคำสั่งซื้อได้",
@@ -273,4 +310,315 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 		Success: true,
 		Message: "อัพเดทสถานะคำสั่งซื้อสำเร็จ",
 	})
-}
\ No newline at end of file
+}
+
+// UpdatePaymentStatus อัพเดทสถานะการชำระเงินของคำสั่งซื้อ (Admin)
+// @Summary อัพเดทสถานะการชำระเงิน (Admin)
+// @Description อัพเดทสถานะการชำระเงินของคำสั่งซื้อตาม state machine (เฉพาะ Admin)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body entities.UpdatePaymentStatusRequest true "ข้อมูลการอัพเดทสถานะการชำระเงิน"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InvalidTransitionError}
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /orders/admin/{id}/payment-status [put]
+func (h *OrderHandler) UpdatePaymentStatus(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdatePaymentStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	if err := h.orderService.UpdatePaymentStatus(c.Context(), id, adminID, &req); err != nil {
+		if resp, ok := transitionConflict(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัพเดทสถานะการชำระเงินได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทสถานะการชำระเงินสำเร็จ",
+	})
+}
+
+// UpdateShippingStatus อัพเดทสถานะการจัดส่ง (Admin)
+// @Summary อัพเดทสถานะการจัดส่ง (Admin)
+// @Description อัพเดทสถานะการจัดส่ง ขนส่ง และเลขพัสดุของคำสั่งซื้อ เวลาจัดส่ง/ส่งถึงจะถูกบันทึกอัตโนมัติ (เฉพาะ Admin)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body entities.UpdateShippingStatusRequest true "ข้อมูลการอัพเดทสถานะการจัดส่ง"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InvalidTransitionError}
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /orders/admin/{id}/shipping-status [put]
+func (h *OrderHandler) UpdateShippingStatus(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateShippingStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	if err := h.orderService.UpdateShippingStatus(c.Context(), id, adminID, &req); err != nil {
+		if resp, ok := transitionConflict(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัพเดทสถานะการจัดส่งได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทสถานะการจัดส่งสำเร็จ",
+	})
+}
+
+// CreateShipment สร้างพัสดุสำหรับคำสั่งซื้อ (Admin)
+// @Summary สร้างพัสดุ (Admin)
+// @Description แบ่งคำสั่งซื้อเป็นพัสดุสำหรับจัดส่ง ไม่ระบุ items หมายถึงสินค้าที่ยังไม่ได้จัดส่งทั้งหมด (เฉพาะ Admin)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body entities.CreateShipmentRequest true "ข้อมูลพัสดุ"
+// @Success 201 {object} entities.ApiResponse{data=entities.Shipment}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /orders/admin/{id}/shipments [post]
+func (h *OrderHandler) CreateShipment(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.CreateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	shipment, err := h.orderService.CreateShipment(c.Context(), id, adminID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างพัสดุสำเร็จ",
+		Data:    shipment,
+	})
+}
+
+// UpdateShipment อัพเดทพัสดุ (Admin)
+// @Summary อัพเดทพัสดุ (Admin)
+// @Description อัพเดทขนส่ง เลขพัสดุ หรือสถานะของพัสดุ สถานะการจัดส่งของคำสั่งซื้อจะถูกคำนวณจากพัสดุทั้งหมด (เฉพาะ Admin)
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param shipmentId path string true "Shipment ID"
+// @Param request body entities.UpdateShipmentRequest true "ข้อมูลการอัพเดทพัสดุ"
+// @Success 200 {object} entities.ApiResponse{data=entities.Shipment}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InvalidTransitionError}
+// @Security BearerAuth
+// @Router /orders/admin/{id}/shipments/{shipmentId} [put]
+func (h *OrderHandler) UpdateShipment(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	shipmentID, err := uuid.Parse(c.Params("shipmentId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ Shipment ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	shipment, err := h.orderService.UpdateShipment(c.Context(), id, shipmentID, adminID, &req)
+	if err != nil {
+		if resp, ok := transitionConflict(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัพเดทพัสดุได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทพัสดุสำเร็จ",
+		Data:    shipment,
+	})
+}
+
+// transitionConflict แปลง error การเปลี่ยนสถานะที่ไม่อนุญาตเป็น response 409
+func transitionConflict(err error) (entities.ApiResponse, bool) {
+	var transitionErr *entities.InvalidTransitionError
+	if !errors.As(err, &transitionErr) {
+		return entities.ApiResponse{}, false
+	}
+
+	return entities.ApiResponse{
+		Success: false,
+		Message: transitionErr.Error(),
+		Data:    transitionErr,
+	}, true
+}
+
+// insufficientStock แปลง error สต็อกไม่พอเป็น response 409 พร้อมรายการสินค้าที่ไม่พอ
+func insufficientStock(err error) (entities.ApiResponse, bool) {
+	var stockErr *entities.InsufficientStockError
+	if !errors.As(err, &stockErr) {
+		return entities.ApiResponse{}, false
+	}
+
+	return entities.ApiResponse{
+		Success: false,
+		Message: stockErr.Error(),
+		Data:    stockErr,
+	}, true
+}
+
+// currencyError แปลง error เรื่องสกุลเงินเป็น 400 (ไม่รองรับ) หรือ 409 (ตะกร้าเปลี่ยนสกุลเงินระหว่างทำรายการ)
+func currencyError(err error) (int, entities.ApiResponse, bool) {
+	switch {
+	case errors.Is(err, entities.ErrUnsupportedCurrency):
+		return fiber.StatusBadRequest, entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, true
+	case errors.Is(err, entities.ErrCartCurrencyChanged):
+		return fiber.StatusConflict, entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		}, true
+	default:
+		return 0, entities.ApiResponse{}, false
+	}
+}
+
+// couponError แปลง error ที่คูปองใช้ไม่ได้ (เงื่อนไขไม่ครบหรือใช้ครบจำนวนแล้ว) เป็น response พร้อมเหตุผล
+func couponError(err error) (entities.ApiResponse, bool) {
+	if !errors.Is(err, entities.ErrCouponNotApplicable) && !errors.Is(err, entities.ErrCouponUsageLimit) {
+		return entities.ApiResponse{}, false
+	}
+
+	return entities.ApiResponse{
+		Success: false,
+		Message: err.Error(),
+	}, true
+}
+
+// shippingError แปลง error ที่วิธีจัดส่งใช้ไม่ได้ (ไม่มี ปิดใช้งาน หรือไม่รองรับภูมิภาค/น้ำหนักนี้)
+// หรือไม่มีที่อยู่จัดส่ง เป็น response พร้อมเหตุผล
+func shippingError(err error) (entities.ApiResponse, bool) {
+	if !errors.Is(err, entities.ErrShippingMethodUnavailable) && !errors.Is(err, entities.ErrShippingUnavailable) &&
+		!errors.Is(err, entities.ErrShippingAddressRequired) {
+		return entities.ApiResponse{}, false
+	}
+
+	return entities.ApiResponse{
+		Success: false,
+		Message: err.Error(),
+	}, true
+}
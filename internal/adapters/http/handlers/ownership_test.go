@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+// ผู้ใช้สี่แบบของทุกกรณี: เจ้าของข้อมูล ผู้ใช้อื่น admin ที่ไม่ใช่เจ้าของ และพนักงานที่อ่านได้แต่แก้ไขไม่ได้
+var (
+	ownerID = uuid.New()
+	otherID = uuid.New()
+	adminID = uuid.New()
+	staffID = uuid.New()
+)
+
+type accessCase struct {
+	name       string
+	actor      entities.Actor
+	wantStatus int
+}
+
+// accessCases ผลที่คาดหวังเมื่อ service ตอบสำเร็จด้วย successStatus
+func accessCases(successStatus int) []accessCase {
+	return []accessCase{
+		{name: "owner", actor: entities.Actor{UserID: ownerID, Role: entities.RoleUser, Permissions: []string{}}, wantStatus: successStatus},
+		{name: "other user", actor: entities.Actor{UserID: otherID, Role: entities.RoleUser, Permissions: []string{}}, wantStatus: fiber.StatusNotFound},
+		{name: "admin", actor: entities.Actor{UserID: adminID, Role: entities.RoleAdmin, Permissions: []string{}}, wantStatus: successStatus},
+		{name: "read-only staff", actor: entities.Actor{UserID: staffID, Role: "support", Permissions: []string{entities.PermOrdersRead, entities.PermPaymentsRead}}, wantStatus: fiber.StatusForbidden},
+	}
+}
+
+// actorRecorder บันทึก Actor ที่ handler ส่งให้ service และตอบตามตัวผู้ใช้
+// (การตรวจสิทธิ์จริงทดสอบไว้ที่ services) ผู้ใช้อื่นได้ ErrNotFound ส่วนพนักงานได้ ErrForbidden
+type actorRecorder struct {
+	actor  entities.Actor
+	called bool
+}
+
+func (r *actorRecorder) check(actor entities.Actor) error {
+	r.actor = actor
+	r.called = true
+	switch actor.UserID {
+	case otherID:
+		return entities.ErrNotFound
+	case staffID:
+		return entities.ErrForbidden
+	default:
+		return nil
+	}
+}
+
+// serve เรียก handler ผ่าน app.Test โดยใส่ข้อมูลผู้ใช้ลง context แบบเดียวกับ AuthRequired
+func serve(t *testing.T, actor entities.Actor, method, route, target, body string, handler fiber.Handler) int {
+	t.Helper()
+
+	app := fiber.New()
+	app.Add(method, route, func(c *fiber.Ctx) error {
+		c.Locals("userID", actor.UserID)
+		c.Locals("role", actor.Role)
+		c.Locals("permissions", actor.Permissions)
+		return c.Next()
+	}, handler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func checkResponse(t *testing.T, tc accessCase, status int, recorder *actorRecorder) {
+	t.Helper()
+	if status != tc.wantStatus {
+		t.Fatalf("status = %d, want %d", status, tc.wantStatus)
+	}
+	if !recorder.called {
+		t.Fatal("service not called")
+	}
+	if !reflect.DeepEqual(recorder.actor, tc.actor) {
+		t.Fatalf("actor = %+v, want %+v", recorder.actor, tc.actor)
+	}
+}
+
+type fakeOrderService struct {
+	services.OrderService
+	actorRecorder
+}
+
+func (s *fakeOrderService) GetOrderByID(ctx context.Context, actor entities.Actor, id uuid.UUID) (*entities.Order, error) {
+	if err := s.check(actor); err != nil {
+		return nil, err
+	}
+	return &entities.Order{ID: id, UserID: ownerID}, nil
+}
+
+func (s *fakeOrderService) CancelOrder(ctx context.Context, actor entities.Actor, id uuid.UUID) error {
+	return s.check(actor)
+}
+
+func TestOrderHandlerGetOrderByIDOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeOrderService{}
+			handler := NewOrderHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodGet, "/orders/:id", "/orders/"+uuid.NewString(), "", handler.GetOrderByID)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+func TestOrderHandlerCancelOrderOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeOrderService{}
+			handler := NewOrderHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodPut, "/orders/:id/cancel", "/orders/"+uuid.NewString()+"/cancel", "", handler.CancelOrder)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+type fakeCartService struct {
+	services.CartService
+	actorRecorder
+}
+
+func (s *fakeCartService) UpdateCartItem(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error {
+	return s.check(actor)
+}
+
+func (s *fakeCartService) RemoveFromCart(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID) error {
+	return s.check(actor)
+}
+
+func TestCartHandlerUpdateCartItemOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeCartService{}
+			handler := NewCartHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodPut, "/cart/:itemId", "/cart/"+uuid.NewString(), `{"quantity": 2}`, handler.UpdateCartItem)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+func TestCartHandlerRemoveFromCartOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakeCartService{}
+			handler := NewCartHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodDelete, "/cart/:itemId", "/cart/"+uuid.NewString(), "", handler.RemoveFromCart)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+type fakePaymentService struct {
+	services.PaymentService
+	actorRecorder
+}
+
+func (s *fakePaymentService) CreatePayment(ctx context.Context, actor entities.Actor, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
+	if err := s.check(actor); err != nil {
+		return nil, err
+	}
+	return &entities.Transaction{ID: uuid.New(), OrderID: req.OrderID, PaymentMethod: req.PaymentMethod}, nil
+}
+
+func (s *fakePaymentService) VerifyPayment(ctx context.Context, actor entities.Actor, id uuid.UUID, req *entities.VerifyPaymentRequest) (*entities.Transaction, error) {
+	if err := s.check(actor); err != nil {
+		return nil, err
+	}
+	return &entities.Transaction{ID: id, TransactionID: req.TransactionID}, nil
+}
+
+func (s *fakePaymentService) CancelPayment(ctx context.Context, actor entities.Actor, id uuid.UUID) error {
+	return s.check(actor)
+}
+
+func TestPaymentHandlerCreatePaymentOwnership(t *testing.T) {
+	body := `{"order_id": "` + uuid.NewString() + `", "payment_method": "credit_card"}`
+	for _, tc := range accessCases(fiber.StatusCreated) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakePaymentService{}
+			handler := NewPaymentHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodPost, "/payments", "/payments", body, handler.CreatePayment)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+func TestPaymentHandlerVerifyPaymentOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakePaymentService{}
+			handler := NewPaymentHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodPost, "/payments/:id/verify", "/payments/"+uuid.NewString()+"/verify", `{"transaction_id": "txn_1"}`, handler.VerifyPayment)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
+
+func TestPaymentHandlerCancelPaymentOwnership(t *testing.T) {
+	for _, tc := range accessCases(fiber.StatusOK) {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &fakePaymentService{}
+			handler := NewPaymentHandler(service)
+
+			status := serve(t, tc.actor, fiber.MethodPut, "/payments/:id/cancel", "/payments/"+uuid.NewString()+"/cancel", "", handler.CancelPayment)
+			checkResponse(t, tc, status, &service.actorRecorder)
+		})
+	}
+}
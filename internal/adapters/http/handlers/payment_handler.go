@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
 )
@@ -20,7 +24,7 @@ func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
 
 // CreatePayment สร้างการชำระเงิน
 // @Summary สร้างการชำระเงิน
-// @Description สร้างการชำระเงินสำหรับคำสั่งซื้อ
+// @Description สร้างการชำระเงินสำหรับคำสั่งซื้อที่ยังรอชำระ (คำสั่งซื้อที่ยกเลิก หมดเวลาชำระ หรือชำระแล้วตอบ 409)
 // @Tags Payments
 // @Accept json
 // @Produce json
@@ -28,6 +32,9 @@ func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
 // @Success 201 {object} entities.ApiResponse{data=entities.Transaction}
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /payments [post]
@@ -47,8 +54,17 @@ func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
 		})
 	}
 
-	transaction, err := h.paymentService.CreatePayment(c.Context(), &req)
+	transaction, err := h.paymentService.CreatePayment(c.Context(), currentActor(c), &req)
 	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบคำสั่งซื้อ"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if errors.Is(err, entities.ErrOrderNotPayable) {
+			return c.Status(fiber.StatusConflict).JSON(entities.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถสร้างการชำระเงินได้",
@@ -62,17 +78,18 @@ func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
 	})
 }
 
-// VerifyPayment ยืนยันการชำระเงิน
-// @Summary ยืนยันการชำระเงิน
-// @Description ยืนยันการชำระเงิน
+// VerifyPayment ตรวจสอบสถานะการชำระเงิน
+// @Summary ตรวจสอบสถานะการชำระเงิน
+// @Description ตรวจสอบสถานะการชำระเงิน (สถานะจะเปลี่ยนเป็น completed จาก callback ของ payment gateway เท่านั้น)
 // @Tags Payments
 // @Accept json
 // @Produce json
 // @Param id path string true "Transaction ID"
 // @Param request body entities.VerifyPaymentRequest true "ข้อมูลการยืนยันการชำระเงิน"
-// @Success 200 {object} entities.ApiResponse
+// @Success 200 {object} entities.ApiResponse{data=entities.Transaction}
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
@@ -101,8 +118,12 @@ func (h *PaymentHandler) VerifyPayment(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.paymentService.VerifyPayment(c.Context(), id, &req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+	transaction, err := h.paymentService.VerifyPayment(c.Context(), currentActor(c), id, &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบการชำระเงิน"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถยืนยันการชำระเงินได้",
 		})
@@ -110,13 +131,14 @@ func (h *PaymentHandler) VerifyPayment(c *fiber.Ctx) error {
 
 	return c.JSON(entities.ApiResponse{
 		Success: true,
-		Message: "ยืนยันการชำระเงินสำเร็จ",
+		Message: "ตรวจสอบสถานะการชำระเงินสำเร็จ",
+		Data:    transaction,
 	})
 }
 
 // CancelPayment ยกเลิกการชำระเงิน
 // @Summary ยกเลิกการชำระเงิน
-// @Description ยกเลิกการชำระเงิน
+// @Description ยกเลิกการชำระเงินที่ยังรอชำระ (การชำระเงินที่ปิดไปแล้วตอบ 409)
 // @Tags Payments
 // @Accept json
 // @Produce json
@@ -124,7 +146,9 @@ func (h *PaymentHandler) VerifyPayment(c *fiber.Ctx) error {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Security BearerAuth
 // @Router /payments/{id}/cancel [put]
@@ -137,7 +161,16 @@ func (h *PaymentHandler) CancelPayment(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.paymentService.CancelPayment(c.Context(), id); err != nil {
+	if err := h.paymentService.CancelPayment(c.Context(), currentActor(c), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบการชำระเงิน"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if errors.Is(err, entities.ErrPaymentNotPending) {
+			return c.Status(fiber.StatusConflict).JSON(entities.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถยกเลิกการชำระเงินได้",
@@ -148,4 +181,103 @@ func (h *PaymentHandler) CancelPayment(c *fiber.Ctx) error {
 		Success: true,
 		Message: "ยกเลิกการชำระเงินสำเร็จ",
 	})
-}
\ No newline at end of file
+}
+
+// PaymentWebhook รับ callback จาก payment gateway
+// @Summary รับ callback จาก payment gateway
+// @Description รับ callback จาก payment gateway โดยตรวจลายเซ็นก่อนอัพเดทสถานะการชำระเงิน (ไม่ต้องใช้ token)
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "ชื่อ payment provider เช่น mock"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Router /payments/webhook/{provider} [post]
+func (h *PaymentHandler) PaymentWebhook(c *fiber.Ctx) error {
+	headers := http.Header{}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		headers.Add(string(key), string(value))
+	})
+
+	if err := h.paymentService.HandleWebhook(c.Context(), c.Params("provider"), headers, c.Body()); err != nil {
+		switch {
+		case errors.Is(err, gateways.ErrUnknownProvider):
+			return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ไม่รองรับ payment provider นี้",
+			})
+		case errors.Is(err, gateways.ErrInvalidSignature):
+			return c.Status(fiber.StatusUnauthorized).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ลายเซ็นไม่ถูกต้อง",
+			})
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "รับ callback สำเร็จ",
+	})
+}
+
+// CreateRefund คืนเงิน
+// @Summary คืนเงิน
+// @Description คืนเงินเต็มจำนวนหรือบางส่วน พร้อมเลือกคืนสต็อกสินค้าได้ (เฉพาะ Admin) ยอดคืนรวมต้องไม่เกินยอดที่ชำระ
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param request body entities.CreateRefundRequest true "ข้อมูลการคืนเงิน"
+// @Success 201 {object} entities.ApiResponse{data=entities.Refund}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /payments/{id}/refunds [post]
+func (h *PaymentHandler) CreateRefund(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.CreateRefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	adminID := c.Locals("userID").(uuid.UUID)
+
+	refund, err := h.paymentService.RefundPayment(c.Context(), id, adminID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "คืนเงินสำเร็จ",
+		Data:    refund,
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -52,6 +53,12 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 
 	product, err := h.productService.CreateProduct(c.Context(), &req)
 	if err != nil {
+		if errors.Is(err, entities.ErrInvalidPriceList) {
+			return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถสร้างสินค้าได้",
@@ -105,7 +112,7 @@ func (h *ProductHandler) GetProducts(c *fiber.Ctx) error {
 
 // GetProductByID ดูสินค้าตาม ID
 // @Summary ดูสินค้าตาม ID
-// @Description ดูรายละเอียดสินค้าตาม ID
+// @Description ดูรายละเอียดสินค้าตาม ID พร้อมสต็อก on-hand และ sellable (หักการจองของตะกร้าแล้ว)
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -225,11 +232,11 @@ func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 		}
 	}
 
-	if minPrice, err := strconv.ParseFloat(c.Query("min_price"), 64); err == nil && minPrice >= 0 {
+	if minPrice, err := entities.ParseMoney(c.Query("min_price")); err == nil && minPrice >= 0 {
 		req.MinPrice = minPrice
 	}
 
-	if maxPrice, err := strconv.ParseFloat(c.Query("max_price"), 64); err == nil && maxPrice >= 0 {
+	if maxPrice, err := entities.ParseMoney(c.Query("max_price")); err == nil && maxPrice >= 0 {
 		req.MaxPrice = maxPrice
 	}
 
@@ -290,6 +297,12 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	}
 
 	if err := h.productService.UpdateProduct(c.Context(), id, &req); err != nil {
+		if errors.Is(err, entities.ErrInvalidPriceList) {
+			return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 			Success: false,
 			Message: "ไม่สามารถอัพเดทสินค้าได้",
@@ -337,4 +350,4 @@ func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
 		Success: true,
 		Message: "ลบสินค้าสำเร็จ",
 	})
-}
\ No newline at end of file
+}
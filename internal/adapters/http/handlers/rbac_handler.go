@@ -0,0 +1,494 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type RBACHandler struct {
+	rbacService services.RBACService
+}
+
+func NewRBACHandler(rbacService services.RBACService) *RBACHandler {
+	return &RBACHandler{
+		rbacService: rbacService,
+	}
+}
+
+// GetRoles ดูบทบาททั้งหมด
+// @Summary ดูบทบาททั้งหมด
+// @Description ดูบทบาททั้งหมดในระบบ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Role}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles [get]
+func (h *RBACHandler) GetRoles(c *fiber.Ctx) error {
+	roles, err := h.rbacService.GetRoles(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลบทบาทได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลบทบาทสำเร็จ",
+		Data:    roles,
+	})
+}
+
+// GetRoleByID ดูบทบาทตาม ID
+// @Summary ดูบทบาทตาม ID
+// @Description ดูบทบาทพร้อมสิทธิ์ทั้งหมดของบทบาท (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Role}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles/{id} [get]
+func (h *RBACHandler) GetRoleByID(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	role, err := h.rbacService.GetRoleByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบบทบาท",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลบทบาทสำเร็จ",
+		Data:    role,
+	})
+}
+
+// CreateRole สร้างบทบาท
+// @Summary สร้างบทบาท
+// @Description สร้างบทบาทใหม่ เช่น support หรือ warehouse แล้วกำหนดสิทธิ์ภายหลัง (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateRoleRequest true "ข้อมูลบทบาท"
+// @Success 201 {object} entities.ApiResponse{data=entities.Role}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles [post]
+func (h *RBACHandler) CreateRole(c *fiber.Ctx) error {
+	var req entities.CreateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	role, err := h.rbacService.CreateRole(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างบทบาทสำเร็จ",
+		Data:    role,
+	})
+}
+
+// UpdateRole แก้ไขบทบาท
+// @Summary แก้ไขบทบาท
+// @Description แก้ไขชื่อหรือคำอธิบายของบทบาท บทบาท admin และ user เปลี่ยนชื่อไม่ได้ การเปลี่ยนชื่อยกเลิก access token เดิมของผู้ใช้ในบทบาท (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param request body entities.UpdateRoleRequest true "ข้อมูลการแก้ไขบทบาท"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles/{id} [put]
+func (h *RBACHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.rbacService.UpdateRole(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบบทบาท"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทบทบาทสำเร็จ",
+	})
+}
+
+// DeleteRole ลบบทบาท
+// @Summary ลบบทบาท
+// @Description ลบบทบาทที่ไม่มีผู้ใช้แล้ว บทบาท admin และ user ลบไม่ได้ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles/{id} [delete]
+func (h *RBACHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.rbacService.DeleteRole(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบบทบาท"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบบทบาทสำเร็จ",
+	})
+}
+
+// SetRolePermissions กำหนดสิทธิ์ของบทบาท
+// @Summary กำหนดสิทธิ์ของบทบาท
+// @Description แทนที่สิทธิ์ทั้งหมดของบทบาทด้วยรายชื่อสิทธิ์ที่ส่งมา (ต้องมีสิทธิ์ roles:manage)
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param request body entities.SetRolePermissionsRequest true "รายชื่อสิทธิ์ เช่น orders:read"
+// @Success 200 {object} entities.ApiResponse{data=entities.Role}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /roles/{id}/permissions [put]
+func (h *RBACHandler) SetRolePermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.SetRolePermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	role, err := h.rbacService.SetRolePermissions(c.Context(), id, &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบบทบาท"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "กำหนดสิทธิ์ของบทบาทสำเร็จ",
+		Data:    role,
+	})
+}
+
+// GetPermissions ดูสิทธิ์ทั้งหมด
+// @Summary ดูสิทธิ์ทั้งหมด
+// @Description ดูสิทธิ์ทั้งหมดในระบบ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Permission}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /permissions [get]
+func (h *RBACHandler) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := h.rbacService.GetPermissions(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลสิทธิ์ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลสิทธิ์สำเร็จ",
+		Data:    permissions,
+	})
+}
+
+// CreatePermission สร้างสิทธิ์
+// @Summary สร้างสิทธิ์
+// @Description สร้างสิทธิ์ใหม่ในรูปแบบ resource:action (ต้องมีสิทธิ์ roles:manage)
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Param request body entities.CreatePermissionRequest true "ข้อมูลสิทธิ์"
+// @Success 201 {object} entities.ApiResponse{data=entities.Permission}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /permissions [post]
+func (h *RBACHandler) CreatePermission(c *fiber.Ctx) error {
+	var req entities.CreatePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	permission, err := h.rbacService.CreatePermission(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างสิทธิ์สำเร็จ",
+		Data:    permission,
+	})
+}
+
+// UpdatePermission แก้ไขสิทธิ์
+// @Summary แก้ไขสิทธิ์
+// @Description แก้ไขคำอธิบายของสิทธิ์ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "Permission ID"
+// @Param request body entities.UpdatePermissionRequest true "ข้อมูลการแก้ไขสิทธิ์"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /permissions/{id} [put]
+func (h *RBACHandler) UpdatePermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdatePermissionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.rbacService.UpdatePermission(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสิทธิ์"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัพเดทสิทธิ์ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทสิทธิ์สำเร็จ",
+	})
+}
+
+// DeletePermission ลบสิทธิ์
+// @Summary ลบสิทธิ์
+// @Description ลบสิทธิ์และถอดออกจากทุกบทบาท สิทธิ์ที่ระบบใช้งานอยู่ลบไม่ได้ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Permissions
+// @Accept json
+// @Produce json
+// @Param id path string true "Permission ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /permissions/{id} [delete]
+func (h *RBACHandler) DeletePermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.rbacService.DeletePermission(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสิทธิ์"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบสิทธิ์สำเร็จ",
+	})
+}
+
+// AssignUserRole กำหนดบทบาทให้ผู้ใช้
+// @Summary กำหนดบทบาทให้ผู้ใช้
+// @Description เปลี่ยนบทบาทของผู้ใช้ มีผลเมื่อผู้ใช้ได้รับ access token ใหม่ (ต้องมีสิทธิ์ roles:manage)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body entities.AssignRoleRequest true "บทบาทที่ต้องการกำหนด"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /users/{id}/role [put]
+func (h *RBACHandler) AssignUserRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.AssignRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.rbacService.AssignUserRole(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบผู้ใช้"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "กำหนดบทบาทให้ผู้ใช้สำเร็จ",
+	})
+}
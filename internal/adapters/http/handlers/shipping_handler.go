@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type ShippingHandler struct {
+	shippingService services.ShippingService
+}
+
+func NewShippingHandler(shippingService services.ShippingService) *ShippingHandler {
+	return &ShippingHandler{
+		shippingService: shippingService,
+	}
+}
+
+// GetShippingMethods ดูวิธีจัดส่งทั้งหมด
+// @Summary ดูวิธีจัดส่งทั้งหมด
+// @Description ดูวิธีจัดส่งทั้งหมดรวมที่ปิดใช้งาน พร้อมอัตราค่าจัดส่งแยกตามโซน (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ShippingMethod}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/methods [get]
+func (h *ShippingHandler) GetShippingMethods(c *fiber.Ctx) error {
+	methods, err := h.shippingService.GetMethods(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลวิธีจัดส่งได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลวิธีจัดส่งสำเร็จ",
+		Data:    methods,
+	})
+}
+
+// GetShippingMethodByID ดูวิธีจัดส่งตาม ID
+// @Summary ดูวิธีจัดส่งตาม ID
+// @Description ดูวิธีจัดส่งพร้อมอัตราค่าจัดส่งแยกตามโซน (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipping method ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.ShippingMethod}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/methods/{id} [get]
+func (h *ShippingHandler) GetShippingMethodByID(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	method, err := h.shippingService.GetMethodByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบวิธีจัดส่ง",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลวิธีจัดส่งสำเร็จ",
+		Data:    method,
+	})
+}
+
+// CreateShippingMethod สร้างวิธีจัดส่ง
+// @Summary สร้างวิธีจัดส่ง
+// @Description สร้างวิธีจัดส่งใหม่ ลูกค้าเลือกด้วย code ตอนสั่งซื้อ (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateShippingMethodRequest true "ข้อมูลวิธีจัดส่ง"
+// @Success 201 {object} entities.ApiResponse{data=entities.ShippingMethod}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/methods [post]
+func (h *ShippingHandler) CreateShippingMethod(c *fiber.Ctx) error {
+	var req entities.CreateShippingMethodRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	method, err := h.shippingService.CreateMethod(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างวิธีจัดส่งสำเร็จ",
+		Data:    method,
+	})
+}
+
+// UpdateShippingMethod แก้ไขวิธีจัดส่ง
+// @Summary แก้ไขวิธีจัดส่ง
+// @Description แก้ไขชื่อ คำอธิบาย ลำดับ ตัวหารน้ำหนักตามปริมาตร หรือเปิด/ปิดใช้งาน (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipping method ID"
+// @Param request body entities.UpdateShippingMethodRequest true "ข้อมูลการแก้ไขวิธีจัดส่ง"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/methods/{id} [put]
+func (h *ShippingHandler) UpdateShippingMethod(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateShippingMethodRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.shippingService.UpdateMethod(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบวิธีจัดส่ง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทวิธีจัดส่งสำเร็จ",
+	})
+}
+
+// DeleteShippingMethod ลบวิธีจัดส่ง
+// @Summary ลบวิธีจัดส่ง
+// @Description ลบวิธีจัดส่งพร้อมอัตราค่าจัดส่งทั้งหมด คำสั่งซื้อเดิมยังเก็บรหัสและค่าจัดส่งไว้ (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipping method ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/methods/{id} [delete]
+func (h *ShippingHandler) DeleteShippingMethod(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.shippingService.DeleteMethod(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบวิธีจัดส่ง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบวิธีจัดส่งได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบวิธีจัดส่งสำเร็จ",
+	})
+}
+
+// CreateShippingRate สร้างอัตราค่าจัดส่ง
+// @Summary สร้างอัตราค่าจัดส่ง
+// @Description กำหนดอัตราค่าจัดส่งของวิธีจัดส่งในโซน เช่น TH, TH-10 หรือ * สำหรับทุกภูมิภาค แบบคงที่ (flat) หรือตามน้ำหนัก (weight) พร้อมเกณฑ์ส่งฟรี (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateShippingRateRequest true "ข้อมูลอัตราค่าจัดส่ง"
+// @Success 201 {object} entities.ApiResponse{data=entities.ShippingRate}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/rates [post]
+func (h *ShippingHandler) CreateShippingRate(c *fiber.Ctx) error {
+	var req entities.CreateShippingRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	rate, err := h.shippingService.CreateRate(c.Context(), &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบวิธีจัดส่ง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างอัตราค่าจัดส่งสำเร็จ",
+		Data:    rate,
+	})
+}
+
+// UpdateShippingRate แก้ไขอัตราค่าจัดส่ง
+// @Summary แก้ไขอัตราค่าจัดส่ง
+// @Description แก้ไขโซน ประเภท ช่วงน้ำหนัก หรือจำนวนเงิน มีผลกับคำสั่งซื้อใหม่เท่านั้น (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipping rate ID"
+// @Param request body entities.UpdateShippingRateRequest true "ข้อมูลการแก้ไขอัตราค่าจัดส่ง"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/rates/{id} [put]
+func (h *ShippingHandler) UpdateShippingRate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateShippingRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.shippingService.UpdateRate(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบอัตราค่าจัดส่ง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทอัตราค่าจัดส่งสำเร็จ",
+	})
+}
+
+// DeleteShippingRate ลบอัตราค่าจัดส่ง
+// @Summary ลบอัตราค่าจัดส่ง
+// @Description ลบอัตราค่าจัดส่งของโซน (ต้องมีสิทธิ์ shipping:manage)
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Param id path string true "Shipping rate ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /shipping/rates/{id} [delete]
+func (h *ShippingHandler) DeleteShippingRate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.shippingService.DeleteRate(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบอัตราค่าจัดส่ง"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบอัตราค่าจัดส่งได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบอัตราค่าจัดส่งสำเร็จ",
+	})
+}
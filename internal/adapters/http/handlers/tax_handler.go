@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type TaxHandler struct {
+	taxService services.TaxService
+}
+
+func NewTaxHandler(taxService services.TaxService) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+	}
+}
+
+// GetTaxClasses ดูประเภทภาษีทั้งหมด
+// @Summary ดูประเภทภาษีทั้งหมด
+// @Description ดูประเภทภาษีทั้งหมดพร้อมอัตราภาษีแยกตามภูมิภาค (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.TaxClass}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/classes [get]
+func (h *TaxHandler) GetTaxClasses(c *fiber.Ctx) error {
+	classes, err := h.taxService.GetClasses(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูลประเภทภาษีได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลประเภทภาษีสำเร็จ",
+		Data:    classes,
+	})
+}
+
+// GetTaxClassByID ดูประเภทภาษีตาม ID
+// @Summary ดูประเภทภาษีตาม ID
+// @Description ดูประเภทภาษีพร้อมอัตราภาษีแยกตามภูมิภาค (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax class ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.TaxClass}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/classes/{id} [get]
+func (h *TaxHandler) GetTaxClassByID(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	class, err := h.taxService.GetClassByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบประเภทภาษี",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลประเภทภาษีสำเร็จ",
+		Data:    class,
+	})
+}
+
+// CreateTaxClass สร้างประเภทภาษี
+// @Summary สร้างประเภทภาษี
+// @Description สร้างประเภทภาษีใหม่ เช่น reduced หากระบุ is_default จะใช้แทนประเภทเริ่มต้นเดิม (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateTaxClassRequest true "ข้อมูลประเภทภาษี"
+// @Success 201 {object} entities.ApiResponse{data=entities.TaxClass}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/classes [post]
+func (h *TaxHandler) CreateTaxClass(c *fiber.Ctx) error {
+	var req entities.CreateTaxClassRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	class, err := h.taxService.CreateClass(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างประเภทภาษีสำเร็จ",
+		Data:    class,
+	})
+}
+
+// UpdateTaxClass แก้ไขประเภทภาษี
+// @Summary แก้ไขประเภทภาษี
+// @Description แก้ไขชื่อ คำอธิบาย หรือกำหนดเป็นประเภทเริ่มต้น (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax class ID"
+// @Param request body entities.UpdateTaxClassRequest true "ข้อมูลการแก้ไขประเภทภาษี"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/classes/{id} [put]
+func (h *TaxHandler) UpdateTaxClass(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateTaxClassRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.taxService.UpdateClass(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบประเภทภาษี"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทประเภทภาษีสำเร็จ",
+	})
+}
+
+// DeleteTaxClass ลบประเภทภาษี
+// @Summary ลบประเภทภาษี
+// @Description ลบประเภทภาษีพร้อมอัตราภาษีทั้งหมด ประเภทเริ่มต้นหรือประเภทที่ยังผูกกับสินค้า/หมวดหมู่ลบไม่ได้ (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax class ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/classes/{id} [delete]
+func (h *TaxHandler) DeleteTaxClass(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.taxService.DeleteClass(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบประเภทภาษี"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		status := fiber.StatusBadRequest
+		if errors.Is(err, entities.ErrTaxClassInUse) {
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบประเภทภาษีสำเร็จ",
+	})
+}
+
+// CreateTaxRate สร้างอัตราภาษี
+// @Summary สร้างอัตราภาษี
+// @Description กำหนดอัตราภาษีของประเภทภาษีในภูมิภาค เช่น TH, TH-10 หรือ * สำหรับทุกภูมิภาค (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateTaxRateRequest true "ข้อมูลอัตราภาษี เช่น rate 7 คือ 7%"
+// @Success 201 {object} entities.ApiResponse{data=entities.TaxRate}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/rates [post]
+func (h *TaxHandler) CreateTaxRate(c *fiber.Ctx) error {
+	var req entities.CreateTaxRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	rate, err := h.taxService.CreateRate(c.Context(), &req)
+	if err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบประเภทภาษี"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้างอัตราภาษีสำเร็จ",
+		Data:    rate,
+	})
+}
+
+// UpdateTaxRate แก้ไขอัตราภาษี
+// @Summary แก้ไขอัตราภาษี
+// @Description แก้ไขภูมิภาค ชื่อ หรืออัตราภาษี มีผลกับคำสั่งซื้อใหม่เท่านั้น (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Param request body entities.UpdateTaxRateRequest true "ข้อมูลการแก้ไขอัตราภาษี"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/rates/{id} [put]
+func (h *TaxHandler) UpdateTaxRate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateTaxRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.taxService.UpdateRate(c.Context(), id, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบอัตราภาษี"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทอัตราภาษีสำเร็จ",
+	})
+}
+
+// DeleteTaxRate ลบอัตราภาษี
+// @Summary ลบอัตราภาษี
+// @Description ลบอัตราภาษีของภูมิภาค (ต้องมีสิทธิ์ tax:manage)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /tax/rates/{id} [delete]
+func (h *TaxHandler) DeleteTaxRate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.taxService.DeleteRate(c.Context(), id); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบอัตราภาษี"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบอัตราภาษีได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบอัตราภาษีสำเร็จ",
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -181,4 +182,301 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 		Success: true,
 		Message: "ลบผู้ใช้สำเร็จ",
 	})
-}
\ No newline at end of file
+}
+
+// GetMe ดูข้อมูลของตัวเอง
+// @Summary ดูข้อมูลของตัวเอง
+// @Description ดูข้อมูลบัญชีของผู้ใช้ที่เข้าสู่ระบบอยู่
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=entities.User}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me [get]
+func (h *UserHandler) GetMe(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	user, err := h.userService.GetUserByID(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบผู้ใช้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูลผู้ใช้สำเร็จ",
+		Data:    user,
+	})
+}
+
+// UpdateMe แก้ไขข้อมูลของตัวเอง
+// @Summary แก้ไขข้อมูลของตัวเอง
+// @Description แก้ไขชื่อ เบอร์โทร และที่อยู่ของตัวเอง ฟิลด์ที่ไม่ส่งมาจะไม่ถูกเปลี่ยน
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param request body entities.UpdateProfileRequest true "ข้อมูลการแก้ไขโปรไฟล์"
+// @Success 200 {object} entities.ApiResponse{data=entities.User}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me [put]
+func (h *UserHandler) UpdateMe(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := h.userService.UpdateProfile(c.Context(), userID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัพเดทข้อมูลผู้ใช้ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดทข้อมูลผู้ใช้สำเร็จ",
+		Data:    user,
+	})
+}
+
+// UploadAvatar อัปโหลดรูปโปรไฟล์
+// @Summary อัปโหลดรูปโปรไฟล์
+// @Description อัปโหลดรูปโปรไฟล์ (JPEG, PNG หรือ WebP) แทนที่รูปเดิม ชนิดไฟล์ตรวจจากเนื้อหาไฟล์
+// @Tags Me
+// @Accept multipart/form-data
+// @Produce json
+// @Param avatar formData file true "ไฟล์รูปโปรไฟล์"
+// @Success 200 {object} entities.ApiResponse{data=entities.User}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 413 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/avatar [post]
+func (h *UserHandler) UploadAvatar(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "กรุณาแนบไฟล์รูปโปรไฟล์ในฟิลด์ avatar",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอ่านไฟล์ได้",
+		})
+	}
+	defer file.Close()
+
+	user, err := h.userService.UploadAvatar(c.Context(), userID, &entities.AvatarUpload{
+		Size:   fileHeader.Size,
+		Reader: file,
+	})
+	if err != nil {
+		if status, resp, ok := profileError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถอัปโหลดรูปโปรไฟล์ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัปโหลดรูปโปรไฟล์สำเร็จ",
+		Data:    user,
+	})
+}
+
+// RequestEmailChange ขอเปลี่ยนอีเมล
+// @Summary ขอเปลี่ยนอีเมล
+// @Description ส่ง token ยืนยันไปยังอีเมลใหม่ อีเมลเดิมยังใช้เข้าสู่ระบบได้จนกว่าจะยืนยัน
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param request body entities.ChangeEmailRequest true "อีเมลใหม่และรหัสผ่านปัจจุบัน"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/email [post]
+func (h *UserHandler) RequestEmailChange(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.ChangeEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.userService.RequestEmailChange(c.Context(), userID, &req); err != nil {
+		if status, resp, ok := profileError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถขอเปลี่ยนอีเมลได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ส่งลิงก์ยืนยันไปยังอีเมลใหม่แล้ว",
+	})
+}
+
+// ConfirmEmailChange ยืนยันการเปลี่ยนอีเมล
+// @Summary ยืนยันการเปลี่ยนอีเมล
+// @Description ยืนยันอีเมลใหม่ด้วย token หลังเปลี่ยนแล้ว refresh token เดิมจะใช้ไม่ได้
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param request body entities.ConfirmEmailChangeRequest true "token ยืนยันอีเมล"
+// @Success 200 {object} entities.ApiResponse{data=entities.User}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me/email/confirm [post]
+func (h *UserHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.ConfirmEmailChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	user, err := h.userService.ConfirmEmailChange(c.Context(), userID, &req)
+	if err != nil {
+		if status, resp, ok := profileError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถเปลี่ยนอีเมลได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "เปลี่ยนอีเมลสำเร็จ",
+		Data:    user,
+	})
+}
+
+// DeleteMe ลบบัญชีของตัวเอง
+// @Summary ลบบัญชีของตัวเอง
+// @Description ลบข้อมูลส่วนตัว สมุดที่อยู่ ตะกร้าและรายการโปรด แล้วปิดบัญชี ประวัติคำสั่งซื้อเก็บไว้แบบไม่ระบุตัวตน
+// @Tags Me
+// @Accept json
+// @Produce json
+// @Param request body entities.DeleteAccountRequest true "รหัสผ่านปัจจุบัน"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /me [delete]
+func (h *UserHandler) DeleteMe(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.userService.DeleteAccount(c.Context(), userID, &req); err != nil {
+		if status, resp, ok := profileError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบบัญชีได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบบัญชีสำเร็จ",
+	})
+}
+
+// profileError แปลง error ของการจัดการบัญชีตัวเองเป็น status พร้อมเหตุผล
+func profileError(err error) (int, entities.ApiResponse, bool) {
+	var status int
+	switch {
+	case errors.Is(err, entities.ErrIncorrectPassword),
+		errors.Is(err, entities.ErrInvalidEmailChangeToken),
+		errors.Is(err, entities.ErrInvalidAvatar):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, entities.ErrAvatarTooLarge):
+		status = fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, entities.ErrEmailTaken):
+		status = fiber.StatusConflict
+	default:
+		return 0, entities.ApiResponse{}, false
+	}
+
+	return status, entities.ApiResponse{
+		Success: false,
+		Message: err.Error(),
+	}, true
+}
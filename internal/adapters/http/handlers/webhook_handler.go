@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateEndpoint สร้าง webhook endpoint
+// @Summary สร้าง webhook endpoint
+// @Description สมัครรับ event ผ่าน webhook (เฉพาะ Admin) secret สำหรับตรวจลายเซ็นจะแสดงเพียงครั้งเดียว
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body entities.CreateWebhookEndpointRequest true "ข้อมูล webhook endpoint"
+// @Success 201 {object} entities.ApiResponse{data=entities.WebhookEndpoint}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateEndpoint(c *fiber.Ctx) error {
+	var req entities.CreateWebhookEndpointRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "สร้าง webhook endpoint สำเร็จ",
+		Data:    endpoint,
+	})
+}
+
+// GetEndpoints ดู webhook endpoint ทั้งหมด
+// @Summary ดู webhook endpoint ทั้งหมด
+// @Description ดู webhook endpoint ทั้งหมดพร้อม pagination (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param page query int false "หน้าที่ต้องการ" default(1)
+// @Param limit query int false "จำนวนรายการต่อหน้า" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.WebhookEndpoint,pagination=entities.PaginationResponse}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) GetEndpoints(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	endpoints, pagination, err := h.webhookService.GetEndpoints(c.Context(), page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงข้อมูล webhook endpoint ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "ดึงข้อมูล webhook endpoint สำเร็จ",
+		Data:       endpoints,
+		Pagination: pagination,
+	})
+}
+
+// GetEndpointByID ดู webhook endpoint ตาม ID
+// @Summary ดู webhook endpoint ตาม ID
+// @Description ดูรายละเอียด webhook endpoint (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook Endpoint ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.WebhookEndpoint}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetEndpointByID(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	endpoint, err := h.webhookService.GetEndpointByID(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบ webhook endpoint",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงข้อมูล webhook endpoint สำเร็จ",
+		Data:    endpoint,
+	})
+}
+
+// UpdateEndpoint แก้ไข webhook endpoint
+// @Summary แก้ไข webhook endpoint
+// @Description แก้ไข URL, ชนิด event หรือเปิด/ปิดการส่ง (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook Endpoint ID"
+// @Param request body entities.UpdateWebhookEndpointRequest true "ข้อมูลการแก้ไข webhook endpoint"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateEndpoint(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	var req entities.UpdateWebhookEndpointRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.webhookService.UpdateEndpoint(c.Context(), id, &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "อัพเดท webhook endpoint สำเร็จ",
+	})
+}
+
+// DeleteEndpoint ลบ webhook endpoint
+// @Summary ลบ webhook endpoint
+// @Description ลบ webhook endpoint ตาม ID (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook Endpoint ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteEndpoint(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Context(), id); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบ webhook endpoint ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบ webhook endpoint สำเร็จ",
+	})
+}
+
+// GetDeliveries ดูประวัติการส่ง webhook
+// @Summary ดูประวัติการส่ง webhook
+// @Description ดูรายการส่งของ endpoint พร้อมสถานะ HTTP และข้อความตอบกลับ (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook Endpoint ID"
+// @Param page query int false "หน้าที่ต้องการ" default(1)
+// @Param limit query int false "จำนวนรายการต่อหน้า" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.WebhookDelivery,pagination=entities.PaginationResponse}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	deliveries, pagination, err := h.webhookService.GetDeliveries(c.Context(), id, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงประวัติการส่ง webhook ได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "ดึงประวัติการส่ง webhook สำเร็จ",
+		Data:       deliveries,
+		Pagination: pagination,
+	})
+}
+
+// Redeliver ส่ง webhook ซ้ำ
+// @Summary ส่ง webhook ซ้ำ
+// @Description สร้างรายการส่งใหม่จาก delivery เดิม (เฉพาะ Admin)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param deliveryId path string true "Webhook Delivery ID"
+// @Success 202 {object} entities.ApiResponse{data=entities.WebhookDelivery}
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /webhooks/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	deliveryID, err := uuid.Parse(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Context(), deliveryID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่พบรายการส่ง webhook",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "กำหนดการส่ง webhook ซ้ำสำเร็จ",
+		Data:    delivery,
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+type WishlistHandler struct {
+	wishlistService services.WishlistService
+}
+
+func NewWishlistHandler(wishlistService services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{
+		wishlistService: wishlistService,
+	}
+}
+
+// GetWishlist ดูรายการโปรด
+// @Summary ดูรายการโปรด
+// @Description ดูสินค้าในรายการโปรดของผู้ใช้ปัจจุบัน เพิ่มล่าสุดอยู่ก่อน
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.WishlistItem}
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /wishlist [get]
+func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	items, err := h.wishlistService.GetWishlist(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถดึงรายการโปรดได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ดึงรายการโปรดสำเร็จ",
+		Data:    items,
+	})
+}
+
+// AddToWishlist เพิ่มสินค้าในรายการโปรด
+// @Summary เพิ่มสินค้าในรายการโปรด
+// @Description เพิ่มสินค้าในรายการโปรด (เพิ่มซ้ำได้โดยไม่เกิดข้อผิดพลาด) สินค้าที่หมดสต็อกจะแจ้งเตือนเมื่อกลับมามีสต็อก
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param request body entities.AddToWishlistRequest true "สินค้าที่ต้องการเพิ่ม"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /wishlist [post]
+func (h *WishlistHandler) AddToWishlist(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	var req entities.AddToWishlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ข้อมูลไม่ถูกต้อง",
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.wishlistService.AddToWishlist(c.Context(), userID, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสินค้า"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถเพิ่มสินค้าในรายการโปรดได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "เพิ่มสินค้าในรายการโปรดสำเร็จ",
+	})
+}
+
+// RemoveFromWishlist ลบสินค้าออกจากรายการโปรด
+// @Summary ลบสินค้าออกจากรายการโปรด
+// @Description ลบสินค้าออกจากรายการโปรดของผู้ใช้ปัจจุบัน
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /wishlist/{productId} [delete]
+func (h *WishlistHandler) RemoveFromWishlist(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	productID, err := uuid.Parse(c.Params("productId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	if err := h.wishlistService.RemoveFromWishlist(c.Context(), userID, productID); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสินค้าในรายการโปรด"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถลบสินค้าออกจากรายการโปรดได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ลบสินค้าออกจากรายการโปรดสำเร็จ",
+	})
+}
+
+// MoveToCart ย้ายสินค้าจากรายการโปรดลงตะกร้า
+// @Summary ย้ายสินค้าจากรายการโปรดลงตะกร้า
+// @Description เพิ่มสินค้าลงตะกร้า (จำนวนเริ่มต้น 1) แล้วนำออกจากรายการโปรด หากสต็อกไม่พอสินค้ายังอยู่ในรายการโปรด
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param request body entities.MoveToCartRequest false "จำนวนที่ต้องการ"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse{data=entities.InsufficientStockError}
+// @Failure 500 {object} entities.ApiResponse
+// @Security BearerAuth
+// @Router /wishlist/{productId}/move-to-cart [post]
+func (h *WishlistHandler) MoveToCart(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(uuid.UUID)
+
+	productID, err := uuid.Parse(c.Params("productId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "รูปแบบ ID ไม่ถูกต้อง",
+		})
+	}
+
+	// body ไม่บังคับ
+	var req entities.MoveToCartRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ข้อมูลไม่ถูกต้อง",
+			})
+		}
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(entities.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.wishlistService.MoveToCart(c.Context(), userID, productID, &req); err != nil {
+		if status, resp, ok := accessDenied(err, "ไม่พบสินค้าในรายการโปรด"); ok {
+			return c.Status(status).JSON(resp)
+		}
+		if resp, ok := insufficientStock(err); ok {
+			return c.Status(fiber.StatusConflict).JSON(resp)
+		}
+		if status, resp, ok := currencyError(err); ok {
+			return c.Status(status).JSON(resp)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+			Success: false,
+			Message: "ไม่สามารถย้ายสินค้าลงตะกร้าได้",
+		})
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "ย้ายสินค้าลงตะกร้าสำเร็จ",
+	})
+}
@@ -1,22 +1,27 @@
 package middleware
 
 import (
+	"log"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
-	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 )
 
 type AuthMiddleware struct {
-	jwtSecret string
+	issuer      gateways.TokenIssuer
+	rbacService services.RBACService
+	revocations gateways.TokenRevocationStore
 }
 
-func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
+func NewAuthMiddleware(issuer gateways.TokenIssuer, rbacService services.RBACService, revocations gateways.TokenRevocationStore) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: jwtSecret,
+		issuer:      issuer,
+		rbacService: rbacService,
+		revocations: revocations,
 	}
 }
 
@@ -39,38 +44,51 @@ func (m *AuthMiddleware) AuthRequired() fiber.Handler {
 			})
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, &utils.Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// ตรวจสอบลายเซ็นด้วยกุญแจตาม kid และวันหมดอายุ
+		accessToken, err := m.issuer.Verify(tokenString)
+		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(entities.ApiResponse{
 				Success: false,
 				Message: "Token ไม่ถูกต้องหรือหมดอายุ",
 			})
 		}
 
-		claims, ok := token.Claims.(*utils.Claims)
-		if !ok {
+		// ปฏิเสธ token ที่ถูกยกเลิกก่อนหมดอายุ (ออกจากระบบ ยกเลิก session ปิดบัญชี เปลี่ยนบทบาท รีเซ็ตรหัสผ่าน)
+		revoked, err := m.revocations.IsRevoked(c.Context(), *accessToken)
+		if err != nil {
+			log.Printf("Failed to check token revocation for user %s: %v", accessToken.UserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ไม่สามารถตรวจสอบ token ได้",
+			})
+		}
+		if revoked {
 			return c.Status(fiber.StatusUnauthorized).JSON(entities.ApiResponse{
 				Success: false,
-				Message: "Claims ไม่ถูกต้อง",
+				Message: entities.ErrTokenRevoked.Error(),
 			})
 		}
 
-		// แปลง UserID เป็น UUID
-		userID, err := uuid.Parse(claims.UserID)
+		// สิทธิ์ของบทบาท (จาก cache) สำหรับตรวจสิทธิ์เข้าถึงข้อมูลของผู้อื่นใน service
+		permissions, err := m.rbacService.RolePermissions(c.Context(), accessToken.Role)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(entities.ApiResponse{
+			log.Printf("Failed to resolve permissions for role %s: %v", accessToken.Role, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
 				Success: false,
-				Message: "User ID ไม่ถูกต้อง",
+				Message: "ไม่สามารถตรวจสอบสิทธิ์ได้",
 			})
 		}
 
 		// เก็บข้อมูลผู้ใช้ใน context
-		c.Locals("userID", userID)
-		c.Locals("email", claims.Email)
-		c.Locals("role", claims.Role)
+		c.Locals("userID", accessToken.UserID)
+		c.Locals("email", accessToken.Email)
+		c.Locals("role", accessToken.Role)
+		c.Locals("permissions", permissions)
+		// token ที่ออกก่อนมี session จะไม่มี sid
+		if accessToken.SessionID != uuid.Nil {
+			c.Locals("sessionID", accessToken.SessionID)
+		}
+		c.Locals("accessToken", *accessToken)
 
 		return c.Next()
 	}
@@ -113,4 +131,36 @@ func (m *AuthMiddleware) RoleRequired(allowedRoles ...string) fiber.Handler {
 			Message: "ไม่มีสิทธิ์เข้าถึง",
 		})
 	}
-}
\ No newline at end of file
+}
+
+// RequirePermission middleware ตรวจสอบว่าบทบาทของผู้ใช้มีสิทธิ์ที่กำหนด
+// ต้องใช้หลัง AuthRequired เสมอ
+func (m *AuthMiddleware) RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals("role").(string)
+		if !ok {
+			return c.Status(fiber.StatusForbidden).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ไม่พบข้อมูล role",
+			})
+		}
+
+		allowed, err := m.rbacService.HasPermission(c.Context(), role, permission)
+		if err != nil {
+			log.Printf("Failed to resolve permissions for role %s: %v", role, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ไม่สามารถตรวจสอบสิทธิ์ได้",
+			})
+		}
+
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(entities.ApiResponse{
+				Success: false,
+				Message: "ไม่มีสิทธิ์เข้าถึง",
+			})
+		}
+
+		return c.Next()
+	}
+}
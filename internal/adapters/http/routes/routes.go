@@ -8,6 +8,7 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/http/handlers"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/http/middleware"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 )
 
 type Routes struct {
@@ -19,6 +20,13 @@ type Routes struct {
 	orderHandler    *handlers.OrderHandler
 	paymentHandler  *handlers.PaymentHandler
 	statsHandler    *handlers.StatsHandler
+	webhookHandler  *handlers.WebhookHandler
+	rbacHandler     *handlers.RBACHandler
+	taxHandler      *handlers.TaxHandler
+	couponHandler   *handlers.CouponHandler
+	shippingHandler *handlers.ShippingHandler
+	addressHandler  *handlers.AddressHandler
+	wishlistHandler *handlers.WishlistHandler
 	authMW          *middleware.AuthMiddleware
 }
 
@@ -31,6 +39,13 @@ func NewRoutes(
 	orderHandler *handlers.OrderHandler,
 	paymentHandler *handlers.PaymentHandler,
 	statsHandler *handlers.StatsHandler,
+	webhookHandler *handlers.WebhookHandler,
+	rbacHandler *handlers.RBACHandler,
+	taxHandler *handlers.TaxHandler,
+	couponHandler *handlers.CouponHandler,
+	shippingHandler *handlers.ShippingHandler,
+	addressHandler *handlers.AddressHandler,
+	wishlistHandler *handlers.WishlistHandler,
 	authMW *middleware.AuthMiddleware,
 ) *Routes {
 	return &Routes{
@@ -42,6 +57,13 @@ func NewRoutes(
 		orderHandler:    orderHandler,
 		paymentHandler:  paymentHandler,
 		statsHandler:    statsHandler,
+		webhookHandler:  webhookHandler,
+		rbacHandler:     rbacHandler,
+		taxHandler:      taxHandler,
+		couponHandler:   couponHandler,
+		shippingHandler: shippingHandler,
+		addressHandler:  addressHandler,
+		wishlistHandler: wishlistHandler,
 		authMW:          authMW,
 	}
 }
@@ -67,6 +89,9 @@ func (r *Routes) SetupRoutes(app *fiber.App) {
 		})
 	})
 
+	// กุญแจสาธารณะสำหรับตรวจสอบ access token
+	app.Get("/.well-known/jwks.json", r.authHandler.JWKS)
+
 	// API v1 routes
 	api := app.Group("/api/v1")
 
@@ -77,70 +102,164 @@ func (r *Routes) SetupRoutes(app *fiber.App) {
 	auth.Post("/refresh", r.authHandler.RefreshToken)
 	auth.Post("/forgot-password", r.authHandler.ForgotPassword)
 	auth.Post("/reset-password", r.authHandler.ResetPassword)
+	auth.Post("/verify-email", r.authHandler.VerifyEmail)
+	auth.Post("/resend-verification", r.authHandler.ResendVerification)
 
 	// Protected auth routes
 	authProtected := auth.Group("", r.authMW.AuthRequired())
 	authProtected.Post("/logout", r.authHandler.Logout)
 	authProtected.Post("/change-password", r.authHandler.ChangePassword)
 
-	// Admin only auth routes
-	authAdmin := auth.Group("", r.authMW.AuthRequired(), r.authMW.AdminRequired())
-	authAdmin.Post("/admin/register", r.authHandler.AdminRegister)
+	// Admin only auth routes (สร้างผู้ใช้พร้อมเลือกบทบาท จึงต้องมีสิทธิ์จัดการบทบาท)
+	auth.Post("/admin/register", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermRolesManage), r.authHandler.AdminRegister)
+
+	// User routes (ตามสิทธิ์ users:*)
+	users := api.Group("/users", r.authMW.AuthRequired())
+	users.Get("/", r.authMW.RequirePermission(entities.PermUsersRead), r.userHandler.GetUsers)
+	users.Get("/:id", r.authMW.RequirePermission(entities.PermUsersRead), r.userHandler.GetUserByID)
+	users.Put("/:id", r.authMW.RequirePermission(entities.PermUsersWrite), r.userHandler.UpdateUser)
+	users.Put("/:id/role", r.authMW.RequirePermission(entities.PermRolesManage), r.rbacHandler.AssignUserRole)
+	users.Delete("/:id", r.authMW.RequirePermission(entities.PermUsersDelete), r.userHandler.DeleteUser)
+	users.Delete("/:id/sessions", r.authMW.RequirePermission(entities.PermUsersWrite), r.authHandler.RevokeUserSessions)
+
+	// Roles & permissions (roles:manage)
+	roles := api.Group("/roles", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermRolesManage))
+	roles.Get("/", r.rbacHandler.GetRoles)
+	roles.Post("/", r.rbacHandler.CreateRole)
+	roles.Get("/:id", r.rbacHandler.GetRoleByID)
+	roles.Put("/:id", r.rbacHandler.UpdateRole)
+	roles.Delete("/:id", r.rbacHandler.DeleteRole)
+	roles.Put("/:id/permissions", r.rbacHandler.SetRolePermissions)
 
-	// User routes (admin only)
-	users := api.Group("/users", r.authMW.AuthRequired(), r.authMW.AdminRequired())
-	users.Get("/", r.userHandler.GetUsers)
-	users.Get("/:id", r.userHandler.GetUserByID)
-	users.Put("/:id", r.userHandler.UpdateUser)
-	users.Delete("/:id", r.userHandler.DeleteUser)
+	permissions := api.Group("/permissions", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermRolesManage))
+	permissions.Get("/", r.rbacHandler.GetPermissions)
+	permissions.Post("/", r.rbacHandler.CreatePermission)
+	permissions.Put("/:id", r.rbacHandler.UpdatePermission)
+	permissions.Delete("/:id", r.rbacHandler.DeletePermission)
 
-	// Categories (admin only for CUD, public for read)
+	// Categories (categories:write for CUD, public for read)
 	categories := api.Group("/categories")
 	categories.Get("/", r.categoryHandler.GetCategories)
 	categories.Get("/:id", r.categoryHandler.GetCategoryByID)
-	categoriesAdmin := categories.Group("", r.authMW.AuthRequired(), r.authMW.AdminRequired())
+	categoriesAdmin := categories.Group("", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermCategoriesWrite))
 	categoriesAdmin.Post("/", r.categoryHandler.CreateCategory)
 	categoriesAdmin.Put("/:id", r.categoryHandler.UpdateCategory)
 	categoriesAdmin.Delete("/:id", r.categoryHandler.DeleteCategory)
 
-	// Products (admin only for CUD, public for read)
+	// Products (products:write for CUD, public for read)
 	products := api.Group("/products")
 	products.Get("/", r.productHandler.GetProducts)
 	products.Get("/:id", r.productHandler.GetProductByID)
 	products.Get("/category/:categoryId", r.productHandler.GetProductsByCategory)
 	products.Get("/search", r.productHandler.SearchProducts)
-	productsAdmin := products.Group("", r.authMW.AuthRequired(), r.authMW.AdminRequired())
+	productsAdmin := products.Group("", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermProductsWrite))
 	productsAdmin.Post("/", r.productHandler.CreateProduct)
 	productsAdmin.Put("/:id", r.productHandler.UpdateProduct)
 	productsAdmin.Delete("/:id", r.productHandler.DeleteProduct)
 
+	// Current user (profile & address book)
+	me := api.Group("/me", r.authMW.AuthRequired())
+	me.Get("/", r.userHandler.GetMe)
+	me.Put("/", r.userHandler.UpdateMe)
+	me.Delete("/", r.userHandler.DeleteMe)
+	me.Post("/avatar", r.userHandler.UploadAvatar)
+	me.Post("/email", r.userHandler.RequestEmailChange)
+	me.Post("/email/confirm", r.userHandler.ConfirmEmailChange)
+	me.Get("/sessions", r.authHandler.ListSessions)
+	me.Delete("/sessions/:id", r.authHandler.RevokeSession)
+	me.Get("/addresses", r.addressHandler.GetAddresses)
+	me.Post("/addresses", r.addressHandler.CreateAddress)
+	me.Get("/addresses/:id", r.addressHandler.GetAddress)
+	me.Put("/addresses/:id", r.addressHandler.UpdateAddress)
+	me.Delete("/addresses/:id", r.addressHandler.DeleteAddress)
+
+	// Wishlist (user only)
+	wishlist := api.Group("/wishlist", r.authMW.AuthRequired())
+	wishlist.Get("/", r.wishlistHandler.GetWishlist)
+	wishlist.Post("/", r.wishlistHandler.AddToWishlist)
+	wishlist.Post("/:productId/move-to-cart", r.wishlistHandler.MoveToCart)
+	wishlist.Delete("/:productId", r.wishlistHandler.RemoveFromWishlist)
+
 	// Cart (user only)
 	cart := api.Group("/cart", r.authMW.AuthRequired())
 	cart.Get("/", r.cartHandler.GetCart)
 	cart.Post("/", r.cartHandler.AddToCart)
+	cart.Put("/currency", r.cartHandler.SetCurrency)
+	cart.Post("/coupon", r.cartHandler.ApplyCoupon)
+	cart.Delete("/coupon", r.cartHandler.RemoveCoupon)
+	cart.Get("/shipping-options", r.cartHandler.GetShippingOptions)
 	cart.Put("/:itemId", r.cartHandler.UpdateCartItem)
 	cart.Delete("/:itemId", r.cartHandler.RemoveFromCart)
 	cart.Delete("/", r.cartHandler.ClearCart)
 
-	// Orders (user for own orders, admin for all)
+	// Orders (user for own orders, staff by permission)
 	orders := api.Group("/orders", r.authMW.AuthRequired())
 	orders.Post("/", r.orderHandler.CreateOrder)
 	orders.Get("/", r.orderHandler.GetOrders)
 	orders.Get("/:id", r.orderHandler.GetOrderByID)
 	orders.Put("/:id/cancel", r.orderHandler.CancelOrder)
-	ordersAdmin := orders.Group("/admin", r.authMW.AdminRequired())
-	ordersAdmin.Get("/", r.orderHandler.GetAllOrders)
-	ordersAdmin.Put("/:id/status", r.orderHandler.UpdateOrderStatus)
+	ordersAdmin := orders.Group("/admin")
+	ordersAdmin.Get("/", r.authMW.RequirePermission(entities.PermOrdersRead), r.orderHandler.GetAllOrders)
+	ordersAdmin.Put("/:id/status", r.authMW.RequirePermission(entities.PermOrdersUpdate), r.orderHandler.UpdateOrderStatus)
+	ordersAdmin.Put("/:id/payment-status", r.authMW.RequirePermission(entities.PermOrdersUpdate), r.orderHandler.UpdatePaymentStatus)
+	ordersAdmin.Put("/:id/shipping-status", r.authMW.RequirePermission(entities.PermOrdersUpdate), r.orderHandler.UpdateShippingStatus)
+	ordersAdmin.Post("/:id/shipments", r.authMW.RequirePermission(entities.PermShipmentsWrite), r.orderHandler.CreateShipment)
+	ordersAdmin.Put("/:id/shipments/:shipmentId", r.authMW.RequirePermission(entities.PermShipmentsWrite), r.orderHandler.UpdateShipment)
+
+	// Payment gateway callbacks (public, verified by signature)
+	// ต้องประกาศก่อนกลุ่ม /payments เพื่อไม่ให้ผ่าน AuthRequired
+	api.Post("/payments/webhook/:provider", r.paymentHandler.PaymentWebhook)
 
 	// Payments (user only)
 	payments := api.Group("/payments", r.authMW.AuthRequired())
 	payments.Post("/", r.paymentHandler.CreatePayment)
 	payments.Post("/:id/verify", r.paymentHandler.VerifyPayment)
 	payments.Put("/:id/cancel", r.paymentHandler.CancelPayment)
+	payments.Post("/:id/refunds", r.authMW.RequirePermission(entities.PermPaymentsRefund), r.paymentHandler.CreateRefund)
 
-	// Stats (admin only)
-	stats := api.Group("/stats", r.authMW.AuthRequired(), r.authMW.AdminRequired())
+	// Stats (stats:read)
+	stats := api.Group("/stats", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermStatsRead))
 	stats.Get("/sales", r.statsHandler.GetSalesStats)
 	stats.Get("/products", r.statsHandler.GetProductStats)
 	stats.Get("/users", r.statsHandler.GetUserStats)
-}
\ No newline at end of file
+
+	// Webhooks (webhooks:manage)
+	webhooks := api.Group("/webhooks", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermWebhooksManage))
+	webhooks.Post("/", r.webhookHandler.CreateEndpoint)
+	webhooks.Get("/", r.webhookHandler.GetEndpoints)
+	webhooks.Post("/deliveries/:deliveryId/redeliver", r.webhookHandler.Redeliver)
+	webhooks.Get("/:id", r.webhookHandler.GetEndpointByID)
+	webhooks.Put("/:id", r.webhookHandler.UpdateEndpoint)
+	webhooks.Delete("/:id", r.webhookHandler.DeleteEndpoint)
+	webhooks.Get("/:id/deliveries", r.webhookHandler.GetDeliveries)
+
+	// Tax classes & rates (tax:manage)
+	tax := api.Group("/tax", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermTaxManage))
+	tax.Get("/classes", r.taxHandler.GetTaxClasses)
+	tax.Post("/classes", r.taxHandler.CreateTaxClass)
+	tax.Get("/classes/:id", r.taxHandler.GetTaxClassByID)
+	tax.Put("/classes/:id", r.taxHandler.UpdateTaxClass)
+	tax.Delete("/classes/:id", r.taxHandler.DeleteTaxClass)
+	tax.Post("/rates", r.taxHandler.CreateTaxRate)
+	tax.Put("/rates/:id", r.taxHandler.UpdateTaxRate)
+	tax.Delete("/rates/:id", r.taxHandler.DeleteTaxRate)
+
+	// Coupons & automatic discounts (coupons:manage)
+	coupons := api.Group("/coupons", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermCouponsManage))
+	coupons.Get("/", r.couponHandler.GetCoupons)
+	coupons.Post("/", r.couponHandler.CreateCoupon)
+	coupons.Get("/:id", r.couponHandler.GetCouponByID)
+	coupons.Put("/:id", r.couponHandler.UpdateCoupon)
+	coupons.Delete("/:id", r.couponHandler.DeleteCoupon)
+
+	// Shipping methods & rates (shipping:manage)
+	shipping := api.Group("/shipping", r.authMW.AuthRequired(), r.authMW.RequirePermission(entities.PermShippingManage))
+	shipping.Get("/methods", r.shippingHandler.GetShippingMethods)
+	shipping.Post("/methods", r.shippingHandler.CreateShippingMethod)
+	shipping.Get("/methods/:id", r.shippingHandler.GetShippingMethodByID)
+	shipping.Put("/methods/:id", r.shippingHandler.UpdateShippingMethod)
+	shipping.Delete("/methods/:id", r.shippingHandler.DeleteShippingMethod)
+	shipping.Post("/rates", r.shippingHandler.CreateShippingRate)
+	shipping.Put("/rates/:id", r.shippingHandler.UpdateShippingRate)
+	shipping.Delete("/rates/:id", r.shippingHandler.DeleteShippingRate)
+}
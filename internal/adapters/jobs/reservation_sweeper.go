@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+)
+
+// ReservationSweeperConfig ค่าตั้งค่าของงานเก็บกวาดการจองสต็อก
+type ReservationSweeperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// ReservationSweeper ยกเลิกคำสั่งซื้อที่เกินกำหนดชำระเงิน (คืนสต็อก) และลบการจองของตะกร้าที่หมดอายุ
+type ReservationSweeper struct {
+	inventory repositories.InventoryRepository
+	config    ReservationSweeperConfig
+}
+
+func NewReservationSweeper(inventory repositories.InventoryRepository, config ReservationSweeperConfig) *ReservationSweeper {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	return &ReservationSweeper{
+		inventory: inventory,
+		config:    config,
+	}
+}
+
+// Run ทำงานจนกว่า ctx จะถูกยกเลิก
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	log.Printf("Reservation sweeper started (every %s)", s.config.Interval)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SweepOnce(ctx); err != nil {
+			log.Printf("Reservation sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Reservation sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SweepOnce เก็บกวาดหนึ่งรอบ
+func (s *ReservationSweeper) SweepOnce(ctx context.Context) error {
+	now := time.Now()
+
+	expired, err := s.inventory.ExpireUnpaidOrders(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return err
+	}
+	for _, orderID := range expired {
+		log.Printf("Order %s cancelled after payment deadline, stock returned", orderID)
+	}
+
+	purged, err := s.inventory.PurgeExpiredCartHolds(ctx, now)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Purged %d expired cart hold(s)", purged)
+	}
+
+	return nil
+}
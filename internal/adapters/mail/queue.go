@@ -0,0 +1,122 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// QueueConfig ค่าตั้งค่าของคิวส่งอีเมล
+type QueueConfig struct {
+	Size        int
+	Workers     int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Queue ส่งอีเมลแบบ asynchronous: Send สร้างอีเมลจากเทมเพลตแล้วนำเข้าคิวทันที
+// worker ส่งผ่าน Transport และ retry แบบ exponential backoff เมื่อส่งไม่สำเร็จ
+// คิวอยู่ในหน่วยความจำ อีเมลที่ค้างอยู่จะหายหากปิดระบบ
+type Queue struct {
+	renderer  *Renderer
+	transport Transport
+	config    QueueConfig
+	jobs      chan *Message
+}
+
+func NewQueue(renderer *Renderer, transport Transport, config QueueConfig) *Queue {
+	if config.Size <= 0 {
+		config.Size = 1000
+	}
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 2 * time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+
+	return &Queue{
+		renderer:  renderer,
+		transport: transport,
+		config:    config,
+		jobs:      make(chan *Message, config.Size),
+	}
+}
+
+// Send สร้างอีเมลและนำเข้าคิว คืน ErrMailQueueFull หากคิวเต็ม
+func (q *Queue) Send(ctx context.Context, email entities.Email) error {
+	msg, err := q.renderer.Render(email)
+	if err != nil {
+		return err
+	}
+	msg.Date = time.Now()
+
+	select {
+	case q.jobs <- msg:
+		return nil
+	default:
+		return entities.ErrMailQueueFull
+	}
+}
+
+// Run เริ่ม worker และทำงานจนกว่า ctx จะถูกยกเลิก
+func (q *Queue) Run(ctx context.Context) {
+	log.Printf("Mail queue started with %d worker(s)", q.config.Workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-q.jobs:
+					q.deliver(ctx, msg)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	log.Printf("Mail queue stopped (%d message(s) not sent)", len(q.jobs))
+}
+
+// deliver ส่งหนึ่งฉบับพร้อม retry หากครบจำนวนครั้งแล้วยังไม่สำเร็จจะบันทึก log และทิ้งไป
+func (q *Queue) deliver(ctx context.Context, msg *Message) {
+	delay := q.config.BaseBackoff
+	for attempt := 1; ; attempt++ {
+		err := q.transport.Deliver(ctx, msg)
+		if err == nil {
+			return
+		}
+		if attempt >= q.config.MaxAttempts {
+			log.Printf("Mail to %s (%q) failed after %d attempt(s): %v", msg.To, msg.Subject, attempt, err)
+			return
+		}
+		log.Printf("Mail to %s failed (attempt %d/%d), retrying in %s: %v", msg.To, attempt, q.config.MaxAttempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > q.config.MaxBackoff {
+			delay = q.config.MaxBackoff
+		}
+	}
+}
+
+var _ gateways.Mailer = (*Queue)(nil)
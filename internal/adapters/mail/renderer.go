@@ -0,0 +1,111 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// templateNames เทมเพลตที่ต้องมีครบทั้ง .html และ .txt ในทุกภาษา
+var templateNames = []string{
+	entities.EmailPasswordReset,
+	entities.EmailVerification,
+	entities.EmailOrderConfirmation,
+	entities.EmailShippingUpdate,
+	entities.EmailPaymentReceipt,
+	entities.EmailBackInStock,
+	entities.EmailChangeNotice,
+}
+
+var locales = []string{entities.LocaleThai, entities.LocaleEnglish}
+
+// Renderer สร้างหัวเรื่อง ข้อความล้วน และ HTML ของอีเมลจากเทมเพลตที่ฝังไว้ใน binary
+// ไฟล์ templates/<locale>/<name>.txt กำหนด {{define "subject"}} และ {{define "text"}}
+// ไฟล์ templates/<locale>/<name>.html กำหนด {{define "content"}} ซึ่งแสดงภายใน layout.html ของภาษานั้น
+type Renderer struct {
+	from string
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// NewRenderer โหลดเทมเพลตทั้งหมดตอนเริ่มระบบ เทมเพลตที่ขาดหรือผิดรูปแบบจะทำให้เริ่มระบบไม่สำเร็จ
+func NewRenderer(from, shopName string) (*Renderer, error) {
+	funcs := map[string]interface{}{
+		"shop": func() string { return shopName },
+		"money": func(amount entities.Money, currency entities.Currency) string {
+			return amount.String() + " " + string(currency)
+		},
+		"date": func(t time.Time) string { return t.Format("02/01/2006 15:04") },
+		"short": func(id fmt.Stringer) string {
+			return strings.ToUpper(strings.SplitN(id.String(), "-", 2)[0])
+		},
+	}
+
+	r := &Renderer{
+		from: from,
+		html: map[string]*htmltemplate.Template{},
+		text: map[string]*texttemplate.Template{},
+	}
+
+	for _, locale := range locales {
+		for _, name := range templateNames {
+			key := locale + "/" + name
+
+			html, err := htmltemplate.New("layout.html").Funcs(funcs).ParseFS(templateFS,
+				"templates/"+locale+"/layout.html", "templates/"+key+".html")
+			if err != nil {
+				return nil, fmt.Errorf("parse mail template %s.html: %w", key, err)
+			}
+			text, err := texttemplate.New(name+".txt").Funcs(funcs).ParseFS(templateFS, "templates/"+key+".txt")
+			if err != nil {
+				return nil, fmt.Errorf("parse mail template %s.txt: %w", key, err)
+			}
+			if text.Lookup("subject") == nil || text.Lookup("text") == nil {
+				return nil, fmt.Errorf("mail template %s.txt must define subject and text", key)
+			}
+
+			r.html[key] = html
+			r.text[key] = text
+		}
+	}
+
+	return r, nil
+}
+
+// Render สร้าง Message จาก email ภาษาที่ไม่รองรับจะใช้ภาษาเริ่มต้น
+func (r *Renderer) Render(email entities.Email) (*Message, error) {
+	key := entities.NormalizeLocale(email.Locale) + "/" + email.Template
+	html, ok := r.html[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %q", email.Template)
+	}
+	text := r.text[key]
+
+	var subject, body, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", email.Data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", key, err)
+	}
+	if err := text.ExecuteTemplate(&body, "text", email.Data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", key, err)
+	}
+	if err := html.ExecuteTemplate(&htmlBody, "layout.html", email.Data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", key, err)
+	}
+
+	return &Message{
+		From:    r.from,
+		To:      email.To,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    htmlBody.String(),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport ช่องทางส่งอีเมลที่สร้างแล้วออกไปจริง
+type Transport interface {
+	Deliver(ctx context.Context, msg *Message) error
+}
+
+// ConsoleTransport พิมพ์อีเมลลง log แทนการส่งจริง เหมาะสำหรับการพัฒนาในเครื่อง
+type ConsoleTransport struct{}
+
+func NewConsoleTransport() *ConsoleTransport {
+	return &ConsoleTransport{}
+}
+
+func (t *ConsoleTransport) Deliver(ctx context.Context, msg *Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+	return nil
+}
+
+// FileTransport เขียนอีเมลเป็นไฟล์ .eml ในโฟลเดอร์ที่กำหนด เปิดดูได้ด้วยโปรแกรมอ่านอีเมลทั่วไป
+type FileTransport struct {
+	dir string
+}
+
+func NewFileTransport(dir string) (*FileTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail directory: %w", err)
+	}
+	return &FileTransport{dir: dir}, nil
+}
+
+func (t *FileTransport) Deliver(ctx context.Context, msg *Message) error {
+	raw, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), recipient)
+	return os.WriteFile(filepath.Join(t.dir, name), raw, 0o644)
+}
+
+// Mailbox เก็บอีเมลที่ส่งไว้ในหน่วยความจำ ใช้แทนเซิร์ฟเวอร์อย่าง MailHog ในการทดสอบ
+// ปลอดภัยต่อการเรียกใช้พร้อมกันจากหลาย goroutine
+type Mailbox struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+func NewMailbox() *Mailbox {
+	return &Mailbox{}
+}
+
+func (m *Mailbox) Deliver(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages อีเมลทั้งหมดที่ได้รับ เรียงตามลำดับที่ส่ง
+func (m *Mailbox) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.messages...)
+}
+
+// Last อีเมลล่าสุดที่ส่งถึงผู้รับ หรือ nil หากไม่มี
+func (m *Mailbox) Last(to string) *Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if strings.EqualFold(m.messages[i].To, to) {
+			return m.messages[i]
+		}
+	}
+	return nil
+}
+
+// Clear ล้างอีเมลทั้งหมด
+func (m *Mailbox) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
@@ -0,0 +1,154 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/events"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+)
+
+// DispatcherConfig ค่าตั้งค่าของ outbox dispatcher
+type DispatcherConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+}
+
+// Dispatcher ดึง event จาก outbox แล้วส่งไปยัง sink ทั้งหมด พร้อม retry แบบ exponential backoff
+// sink ที่ส่งสำเร็จแล้วถูกบันทึกไว้กับ event การ retry จึงส่งเฉพาะ sink ที่ล้มเหลว (เช่น ไม่ส่งอีเมลซ้ำ)
+type Dispatcher struct {
+	outbox repositories.OutboxRepository
+	sinks  []events.Sink
+	config DispatcherConfig
+}
+
+func NewDispatcher(outbox repositories.OutboxRepository, config DispatcherConfig, sinks ...events.Sink) *Dispatcher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 10
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 5 * time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Hour
+	}
+	if config.Lease <= 0 {
+		config.Lease = time.Minute
+	}
+
+	return &Dispatcher{
+		outbox: outbox,
+		sinks:  sinks,
+		config: config,
+	}
+}
+
+// Run ทำงานจนกว่า ctx จะถูกยกเลิก
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Printf("Outbox dispatcher started with %d sink(s)", len(d.sinks))
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchOnce(ctx); err != nil {
+			log.Printf("Outbox dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce ส่ง event หนึ่งรอบ และคืนจำนวน event ที่ส่งสำเร็จ
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	messages, err := d.outbox.ClaimPending(ctx, d.config.BatchSize, d.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, message := range messages {
+		delivered, err := d.deliver(ctx, message)
+		if err != nil {
+			attempts := message.Attempts + 1
+			var next *time.Time
+			if attempts < d.config.MaxAttempts {
+				at := time.Now().Add(backoffDelay(d.config.BaseBackoff, d.config.MaxBackoff, attempts))
+				next = &at
+			} else {
+				log.Printf("Outbox event %s (%s) gave up after %d attempts: %v", message.Event.ID, message.Event.Type, attempts, err)
+			}
+
+			if markErr := d.outbox.MarkFailed(ctx, message.Event.ID, attempts, err.Error(), next, delivered); markErr != nil {
+				return dispatched, markErr
+			}
+			continue
+		}
+
+		if err := d.outbox.MarkDispatched(ctx, message.Event.ID); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+// deliver ส่ง event ไปยัง sink ที่ยังไม่เคยส่งสำเร็จ แล้วคืนรายชื่อ sink ที่ส่งสำเร็จทั้งหมดจนถึงรอบนี้
+// sink ที่ล้มเหลวไม่หยุด sink ถัดไป ความผิดพลาดทั้งหมดถูกรวมไว้ใน error เดียว
+func (d *Dispatcher) deliver(ctx context.Context, message *entities.OutboxMessage) ([]string, error) {
+	delivered := append([]string(nil), message.DeliveredSinks...)
+	done := make(map[string]bool, len(delivered))
+	for _, name := range delivered {
+		done[name] = true
+	}
+
+	var failures []string
+	for _, sink := range d.sinks {
+		if done[sink.Name()] {
+			continue
+		}
+		if err := sink.Deliver(ctx, message.Event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name(), err))
+			continue
+		}
+		delivered = append(delivered, sink.Name())
+	}
+
+	if len(failures) > 0 {
+		return delivered, errors.New(strings.Join(failures, "; "))
+	}
+	return delivered, nil
+}
+
+// backoffDelay คำนวณเวลารอก่อนส่งใหม่ (base * 2^(attempts-1)) ไม่เกิน max
+func backoffDelay(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,137 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/events"
+)
+
+// HandlerFunc ฟังก์ชันที่รับ event ภายใน process
+type HandlerFunc func(ctx context.Context, event entities.DomainEvent) error
+
+// InProcessSink ส่ง event ไปยัง handler ที่ลงทะเบียนไว้ใน process เดียวกัน
+type InProcessSink struct {
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+}
+
+func NewInProcessSink() *InProcessSink {
+	return &InProcessSink{
+		handlers: map[string][]HandlerFunc{},
+	}
+}
+
+// Subscribe ลงทะเบียน handler ตามชนิด event ("*" หมายถึงทุก event)
+func (s *InProcessSink) Subscribe(eventType string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+func (s *InProcessSink) Name() string {
+	return "in-process"
+}
+
+func (s *InProcessSink) Deliver(ctx context.Context, event entities.DomainEvent) error {
+	s.mu.RLock()
+	handlers := append([]HandlerFunc{}, s.handlers[event.Type]...)
+	handlers = append(handlers, s.handlers["*"]...)
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookSink ส่ง event เป็น JSON ไปยัง URL ที่กำหนด
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Deliver(ctx context.Context, event entities.DomainEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID.String())
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogSink เขียน event ลง log และเก็บไว้ในหน่วยความจำ (เหมาะสำหรับการทดสอบ)
+type LogSink struct {
+	mu     sync.Mutex
+	events []entities.DomainEvent
+}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(ctx context.Context, event entities.DomainEvent) error {
+	log.Printf("📣 Event %s %s(%s): %s", event.Type, event.AggregateType, event.AggregateID, string(event.Payload))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Events คืนรายการ event ที่ได้รับทั้งหมด
+func (s *LogSink) Events() []entities.DomainEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]entities.DomainEvent{}, s.events...)
+}
+
+var (
+	_ events.Sink = (*InProcessSink)(nil)
+	_ events.Sink = (*WebhookSink)(nil)
+	_ events.Sink = (*LogSink)(nil)
+)
@@ -0,0 +1,195 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/events"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+// maxResponseBody จำนวน byte สูงสุดของ response ที่เก็บไว้ใน delivery log
+const maxResponseBody = 4096
+
+// WebhookFanoutSink สร้าง delivery ให้ทุก endpoint ที่สมัครรับ event นั้น
+type WebhookFanoutSink struct {
+	webhookRepo repositories.WebhookRepository
+}
+
+func NewWebhookFanoutSink(webhookRepo repositories.WebhookRepository) *WebhookFanoutSink {
+	return &WebhookFanoutSink{webhookRepo: webhookRepo}
+}
+
+func (s *WebhookFanoutSink) Name() string {
+	return "webhook-fanout"
+}
+
+func (s *WebhookFanoutSink) Deliver(ctx context.Context, event entities.DomainEvent) error {
+	endpoints, err := s.webhookRepo.GetActiveEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*entities.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event.Type) {
+			continue
+		}
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, &entities.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  event.Type,
+			Payload:    body,
+		})
+	}
+
+	return s.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
+
+// WebhookWorkerConfig ค่าตั้งค่าของ webhook worker
+type WebhookWorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+}
+
+// WebhookWorker ส่ง delivery ที่รออยู่ไปยัง endpoint พร้อมลายเซ็น HMAC-SHA256
+type WebhookWorker struct {
+	webhookRepo repositories.WebhookRepository
+	client      *http.Client
+	config      WebhookWorkerConfig
+}
+
+func NewWebhookWorker(webhookRepo repositories.WebhookRepository, config WebhookWorkerConfig) *WebhookWorker {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 30 * time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 6 * time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &WebhookWorker{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: config.Timeout},
+		config:      config,
+	}
+}
+
+// Run ทำงานจนกว่า ctx จะถูกยกเลิก
+func (w *WebhookWorker) Run(ctx context.Context) {
+	log.Println("Webhook worker started")
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.DeliverOnce(ctx); err != nil {
+			log.Printf("Webhook delivery failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce ส่ง delivery ที่ถึงเวลาหนึ่งรอบ
+func (w *WebhookWorker) DeliverOnce(ctx context.Context) error {
+	deliveries, err := w.webhookRepo.ClaimPendingDeliveries(ctx, w.config.BatchSize, w.config.Timeout*2)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		attempts := delivery.Attempts + 1
+
+		result := w.send(ctx, delivery)
+		delivered := result.Error == ""
+
+		var next *time.Time
+		if !delivered && attempts < w.config.MaxAttempts {
+			at := time.Now().Add(backoffDelay(w.config.BaseBackoff, w.config.MaxBackoff, attempts))
+			next = &at
+		}
+
+		if err := w.webhookRepo.RecordAttempt(ctx, delivery.ID, attempts, result, delivered, next); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WebhookWorker) send(ctx context.Context, delivery *entities.WebhookDelivery) *entities.WebhookDeliveryResult {
+	endpoint, err := w.webhookRepo.GetEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return &entities.WebhookDeliveryResult{Error: "endpoint not found: " + err.Error()}
+	}
+	if !endpoint.Active {
+		return &entities.WebhookDeliveryResult{Error: "endpoint is disabled"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return &entities.WebhookDeliveryResult{Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fiber-ecommerce-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", delivery.ID.String())
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID.String())
+	req.Header.Set("X-Webhook-Signature", utils.SignWebhookPayload(endpoint.Secret, time.Now(), delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return &entities.WebhookDeliveryResult{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := &entities.WebhookDeliveryResult{
+		ResponseStatus: resp.StatusCode,
+		ResponseBody:   string(body),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Error = http.StatusText(resp.StatusCode)
+		if result.Error == "" {
+			result.Error = "unexpected status"
+		}
+	}
+
+	return result
+}
+
+var _ events.Sink = (*WebhookFanoutSink)(nil)
@@ -0,0 +1,130 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+)
+
+const (
+	// MockSignatureHeader header ที่ mock gateway ใช้ส่งลายเซ็น
+	MockSignatureHeader = "X-Mock-Signature"
+
+	// mockSignatureTolerance อายุสูงสุดของ callback ที่ยอมรับ
+	mockSignatureTolerance = 5 * time.Minute
+)
+
+// MockWebhookPayload รูปแบบ callback ของ mock gateway
+//
+//	{"id":"evt_123","type":"payment_intent.succeeded","data":{"intent_id":"mock_pi_...","amount":100,"currency":"THB"}}
+type MockWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		IntentID string            `json:"intent_id"`
+		Amount   entities.Money    `json:"amount"`
+		Currency entities.Currency `json:"currency"`
+	} `json:"data"`
+}
+
+// MockGateway payment gateway จำลองสำหรับการพัฒนาและทดสอบ
+// ไม่มีการเรียกเครือข่าย ทุกการเรียกสำเร็จทันที ส่วนการยืนยันการชำระเงินต้องมาจาก callback ที่เซ็นด้วย secret
+type MockGateway struct {
+	webhookSecret string
+}
+
+func NewMockGateway(webhookSecret string) *MockGateway {
+	return &MockGateway{webhookSecret: webhookSecret}
+}
+
+func (g *MockGateway) Name() string {
+	return "mock"
+}
+
+func (g *MockGateway) CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	ref := "mock_pi_" + strings.ReplaceAll(uuid.New().String(), "-", "")
+	return &entities.PaymentIntent{
+		ProviderRef:  ref,
+		ClientSecret: ref + "_secret_" + uuid.New().String()[:8],
+		Status:       "requires_confirmation",
+	}, nil
+}
+
+func (g *MockGateway) Capture(ctx context.Context, providerRef string, amount entities.Money) (*entities.GatewayCapture, error) {
+	if !strings.HasPrefix(providerRef, "mock_pi_") {
+		return nil, fmt.Errorf("unknown payment intent %s", providerRef)
+	}
+
+	return &entities.GatewayCapture{
+		ProviderRef: providerRef,
+		Amount:      amount,
+		Status:      "captured",
+	}, nil
+}
+
+func (g *MockGateway) Refund(ctx context.Context, providerRef string, amount entities.Money, reason string) (*entities.GatewayRefund, error) {
+	if !strings.HasPrefix(providerRef, "mock_pi_") {
+		return nil, fmt.Errorf("unknown payment intent %s", providerRef)
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("refund amount must be greater than zero")
+	}
+
+	return &entities.GatewayRefund{
+		RefundRef: "mock_re_" + strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Amount:    amount,
+		Status:    "succeeded",
+	}, nil
+}
+
+func (g *MockGateway) ParseWebhook(ctx context.Context, headers http.Header, body []byte) (*entities.PaymentWebhookEvent, error) {
+	if err := utils.VerifyWebhookSignature(g.webhookSecret, headers.Get(MockSignatureHeader), body, mockSignatureTolerance); err != nil {
+		return nil, fmt.Errorf("%w: %v", gateways.ErrInvalidSignature, err)
+	}
+
+	var payload MockWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %v", err)
+	}
+
+	event := &entities.PaymentWebhookEvent{
+		Provider:    g.Name(),
+		EventID:     payload.ID,
+		ProviderRef: payload.Data.IntentID,
+		Amount:      payload.Data.Amount,
+		Currency:    payload.Data.Currency,
+	}
+	if event.Currency == "" {
+		event.Currency = entities.DefaultCurrency
+	}
+
+	switch payload.Type {
+	case "payment_intent.succeeded":
+		event.Status = entities.PaymentWebhookSucceeded
+	case "payment_intent.payment_failed":
+		event.Status = entities.PaymentWebhookFailed
+	default:
+		event.Status = payload.Type
+	}
+
+	return event, nil
+}
+
+// Sign สร้าง header ลายเซ็นสำหรับจำลอง callback ในการทดสอบ
+func (g *MockGateway) Sign(body []byte) string {
+	return utils.SignWebhookPayload(g.webhookSecret, time.Now(), body)
+}
+
+var _ gateways.PaymentGateway = (*MockGateway)(nil)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"gorm.io/gorm"
 )
 
@@ -43,22 +44,96 @@ type User struct {
 	Phone            string    `gorm:"type:varchar(20)" json:"phone"`
 	Address          string    `gorm:"type:text" json:"address"`
 	Active           bool      `gorm:"default:true" json:"active"`
+	Locale           string    `gorm:"type:varchar(5);default:'th'" json:"locale"`
 	RoleID           uuid.UUID `json:"role_id" validate:"required"`
 	Role             Role      `gorm:"foreignKey:RoleID" json:"role,omitempty"`
 	Orders           []Order   `gorm:"foreignKey:UserID" json:"orders,omitempty"`
 	WishList         []Product `gorm:"many2many:user_wishlist;" json:"wishlist,omitempty"`
-	RefreshToken     string    `gorm:"type:text" json:"-"`
 	ResetToken       string    `gorm:"type:text" json:"-"`
 	ResetTokenExpiry time.Time `json:"-"`
+	// PendingEmail อีเมลใหม่ที่รอยืนยันด้วย EmailChangeToken
+	PendingEmail           string     `gorm:"type:varchar(100)" json:"-"`
+	EmailChangeToken       string     `gorm:"type:text" json:"-"`
+	EmailChangeTokenExpiry *time.Time `json:"-"`
+	// EmailVerifiedAt เวลาที่ยืนยันอีเมล VerificationToken ใช้ได้ครั้งเดียวก่อน VerificationTokenExpiry
+	EmailVerifiedAt         *time.Time `json:"email_verified_at"`
+	VerificationToken       string     `gorm:"type:text" json:"-"`
+	VerificationTokenExpiry *time.Time `json:"-"`
+	VerificationSentAt      *time.Time `json:"-"`
+}
+
+// WishlistItem แถวในตาราง user_wishlist (ความสัมพันธ์ many2many ของ User.WishList)
+type WishlistItem struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ProductID uuid.UUID `gorm:"type:uuid;primaryKey" json:"product_id"`
+	Product   Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (WishlistItem) TableName() string {
+	return "user_wishlist"
+}
+
+// UserSession สำหรับเก็บ session ต่ออุปกรณ์ของผู้ใช้ (เก็บเฉพาะ hash ของ refresh token ปัจจุบัน)
+type UserSession struct {
+	BaseModel
+	UserID           uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	RefreshTokenHash string     `gorm:"type:varchar(64);not null" json:"-"`
+	UserAgent        string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress        string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+}
+
+// SessionRotatedToken สำหรับเก็บ hash ของ refresh token ที่ถูกหมุนไปแล้วของ session
+type SessionRotatedToken struct {
+	TokenHash string    `gorm:"type:varchar(64);primaryKey" json:"-"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null" json:"session_id"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// AddressFields คอลัมน์ที่อยู่แบบมีโครงสร้าง ใช้ร่วมกันระหว่าง Address และ OrderAddress
+type AddressFields struct {
+	Recipient   string `gorm:"type:varchar(100);not null" json:"recipient"`
+	Phone       string `gorm:"type:varchar(20);not null" json:"phone"`
+	Line1       string `gorm:"type:varchar(255);not null" json:"line1"`
+	Line2       string `gorm:"type:varchar(255)" json:"line2"`
+	SubDistrict string `gorm:"type:varchar(100)" json:"sub_district"`
+	District    string `gorm:"type:varchar(100)" json:"district"`
+	Province    string `gorm:"type:varchar(100);not null" json:"province"`
+	PostalCode  string `gorm:"type:varchar(10);not null" json:"postal_code"`
+	Country     string `gorm:"type:varchar(2);not null" json:"country"`
+	RegionCode  string `gorm:"type:varchar(10)" json:"region_code"`
+}
+
+// Address สำหรับเก็บสมุดที่อยู่ของผู้ใช้
+type Address struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Label  string    `gorm:"type:varchar(50)" json:"label"`
+	AddressFields
+	IsDefaultShipping bool `gorm:"default:false" json:"is_default_shipping"`
+	IsDefaultBilling  bool `gorm:"default:false" json:"is_default_billing"`
+}
+
+// OrderAddress สำหรับเก็บสำเนาที่อยู่จัดส่ง/ออกใบเสร็จของคำสั่งซื้อ
+type OrderAddress struct {
+	BaseModel
+	OrderID   uuid.UUID  `gorm:"type:uuid;not null" json:"order_id"`
+	Kind      string     `gorm:"type:varchar(20);not null" json:"kind"`
+	AddressID *uuid.UUID `gorm:"type:uuid" json:"address_id"`
+	AddressFields
 }
 
 // Category สำหรับเก็บข้อมูลหมวดหมู่สินค้า
 type Category struct {
 	BaseModel
-	Name        string    `gorm:"type:varchar(100);unique_index" json:"name" validate:"required"`
-	Description string    `gorm:"type:text" json:"description"`
-	Image       string    `gorm:"type:varchar(255)" json:"image"`
-	Products    []Product `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
+	Name        string     `gorm:"type:varchar(100);unique_index" json:"name" validate:"required"`
+	Description string     `gorm:"type:text" json:"description"`
+	Image       string     `gorm:"type:varchar(255)" json:"image"`
+	TaxClassID  *uuid.UUID `gorm:"type:uuid" json:"tax_class_id"`
+	Products    []Product  `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
 }
 
 // Product สำหรับเก็บข้อมูลสินค้า
@@ -66,12 +141,19 @@ type Product struct {
 	BaseModel
 	Name        string         `gorm:"type:varchar(100)" json:"name" validate:"required"`
 	Description string         `gorm:"type:text" json:"description"`
-	Price       float64        `gorm:"type:decimal(10,2)" json:"price" validate:"required,min=0"`
+	Price       entities.Money `gorm:"type:numeric(18,2)" json:"price" validate:"required,min=0"`
+	Currency    string         `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	Prices      []ProductPrice `gorm:"foreignKey:ProductID" json:"prices,omitempty"`
 	Stock       int            `gorm:"type:int" json:"stock" validate:"min=0"`
 	Image       string         `gorm:"type:varchar(255)" json:"image"`
 	Images      []ProductImage `gorm:"foreignKey:ProductID" json:"images,omitempty"`
 	CategoryID  uuid.UUID      `json:"category_id" validate:"required"`
 	Category    Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	TaxClassID  *uuid.UUID     `gorm:"type:uuid" json:"tax_class_id"`
+	WeightGrams int            `gorm:"type:int;default:0" json:"weight_grams"`
+	LengthCm    int            `gorm:"type:int;default:0" json:"length_cm"`
+	WidthCm     int            `gorm:"type:int;default:0" json:"width_cm"`
+	HeightCm    int            `gorm:"type:int;default:0" json:"height_cm"`
 	OrderItems  []OrderItem    `gorm:"foreignKey:ProductID" json:"order_items,omitempty"`
 	CartItems   []CartItem     `gorm:"foreignKey:ProductID" json:"cart_items,omitempty"`
 }
@@ -83,63 +165,293 @@ type ProductImage struct {
 	ImageURL  string    `gorm:"type:varchar(255)" json:"image_url" validate:"required"`
 }
 
+// ProductPrice สำหรับเก็บราคาสินค้าที่กำหนดเองแยกตามสกุลเงิน
+type ProductPrice struct {
+	BaseModel
+	ProductID uuid.UUID      `gorm:"type:uuid;not null" json:"product_id"`
+	Currency  string         `gorm:"type:varchar(3);not null" json:"currency"`
+	Price     entities.Money `gorm:"type:numeric(18,2);not null" json:"price"`
+}
+
 // Cart สำหรับเก็บข้อมูลตะกร้าสินค้า
 type Cart struct {
 	BaseModel
-	UserID     uuid.UUID  `json:"user_id"`
-	User       User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	CartItems  []CartItem `gorm:"foreignKey:CartID" json:"cart_items,omitempty"`
-	TotalPrice float64    `gorm:"type:decimal(10,2)" json:"total_price"`
+	UserID     uuid.UUID      `json:"user_id"`
+	User       User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CartItems  []CartItem     `gorm:"foreignKey:CartID" json:"cart_items,omitempty"`
+	TotalPrice entities.Money `gorm:"type:numeric(18,2)" json:"total_price"`
+	Currency   string         `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	CouponID   *uuid.UUID     `gorm:"type:uuid" json:"coupon_id"`
+	Coupon     *Coupon        `gorm:"foreignKey:CouponID" json:"coupon,omitempty"`
 }
 
 // CartItem สำหรับเก็บรายการสินค้าในตะกร้า
 type CartItem struct {
 	BaseModel
-	CartID    uuid.UUID `json:"cart_id"`
-	Cart      Cart      `gorm:"foreignKey:CartID" json:"cart,omitempty"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Quantity  int       `gorm:"type:int" json:"quantity" validate:"required,min=1"`
-	Price     float64   `gorm:"type:decimal(10,2)" json:"price"`
+	CartID    uuid.UUID      `json:"cart_id"`
+	Cart      Cart           `gorm:"foreignKey:CartID" json:"cart,omitempty"`
+	ProductID uuid.UUID      `json:"product_id"`
+	Product   Product        `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Quantity  int            `gorm:"type:int" json:"quantity" validate:"required,min=1"`
+	Price     entities.Money `gorm:"type:numeric(18,2)" json:"price"`
+	// RestockedQuantity จำนวนที่คืนเข้าสต็อกแล้ว (จากการยกเลิกหรือคืนเงิน) ป้องกันการคืนสต็อกซ้ำ
+	RestockedQuantity int `gorm:"type:int;default:0" json:"restocked_quantity"`
 }
 
 // Order สำหรับเก็บข้อมูลการสั่งซื้อ
 type Order struct {
 	BaseModel
-	UserID          uuid.UUID     `json:"user_id"`
-	User            User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	OrderItems      []OrderItem   `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
-	TotalPrice      float64       `gorm:"type:decimal(10,2)" json:"total_price"`
-	Status          string        `gorm:"type:varchar(50);default:'pending'" json:"status"`
-	PaymentMethod   string        `gorm:"type:varchar(50)" json:"payment_method"`
-	PaymentStatus   string        `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
-	ShippingMethod  string        `gorm:"type:varchar(50)" json:"shipping_method"`
-	ShippingStatus  string        `gorm:"type:varchar(50);default:'pending'" json:"shipping_status"`
-	ShippingAddress string        `gorm:"type:text" json:"shipping_address"`
-	TrackingNumber  string        `gorm:"type:varchar(100)" json:"tracking_number"`
-	Notes           string        `gorm:"type:text" json:"notes"`
-	Transactions    []Transaction `gorm:"foreignKey:OrderID" json:"transactions,omitempty"`
+	UserID           uuid.UUID             `json:"user_id"`
+	User             User                  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	OrderItems       []OrderItem           `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
+	Subtotal         entities.Money        `gorm:"type:numeric(18,2);default:0" json:"subtotal"`
+	DiscountAmount   entities.Money        `gorm:"type:numeric(18,2);default:0" json:"discount_amount"`
+	TaxAmount        entities.Money        `gorm:"type:numeric(18,2);default:0" json:"tax_amount"`
+	TotalPrice       entities.Money        `gorm:"type:numeric(18,2)" json:"total_price"`
+	PricesIncludeTax bool                  `gorm:"default:true" json:"prices_include_tax"`
+	TaxRegion        string                `gorm:"type:varchar(10)" json:"tax_region"`
+	CouponCode       string                `gorm:"type:varchar(50)" json:"coupon_code"`
+	Currency         string                `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	BaseCurrency     string                `gorm:"type:varchar(3);default:'THB'" json:"base_currency"`
+	ExchangeRate     entities.ExchangeRate `gorm:"type:decimal(18,6);default:1" json:"exchange_rate"`
+	Status           string                `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	PaymentMethod    string                `gorm:"type:varchar(50)" json:"payment_method"`
+	PaymentStatus    string                `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
+	ShippingMethodID *uuid.UUID            `gorm:"type:uuid" json:"shipping_method_id"`
+	ShippingMethod   string                `gorm:"type:varchar(50)" json:"shipping_method"`
+	ShippingAmount   entities.Money        `gorm:"type:numeric(18,2);default:0" json:"shipping_amount"`
+	ShippingStatus   string                `gorm:"type:varchar(50);default:'pending'" json:"shipping_status"`
+	ShippingAddress  string                `gorm:"type:text" json:"shipping_address"`
+	TrackingNumber   string                `gorm:"type:varchar(100)" json:"tracking_number"`
+	Carrier          string                `gorm:"type:varchar(100)" json:"carrier"`
+	ShippedAt        *time.Time            `json:"shipped_at"`
+	DeliveredAt      *time.Time            `json:"delivered_at"`
+	PaymentDueAt     *time.Time            `json:"payment_due_at"`
+	Notes            string                `gorm:"type:text" json:"notes"`
+	Transactions     []Transaction         `gorm:"foreignKey:OrderID" json:"transactions,omitempty"`
+	Shipments        []Shipment            `gorm:"foreignKey:OrderID" json:"shipments,omitempty"`
+	History          []OrderStatusHistory  `gorm:"foreignKey:OrderID" json:"history,omitempty"`
+	Redemptions      []CouponRedemption    `gorm:"foreignKey:OrderID" json:"redemptions,omitempty"`
+	Addresses        []OrderAddress        `gorm:"foreignKey:OrderID" json:"addresses,omitempty"`
 }
 
 // OrderItem สำหรับเก็บรายการสินค้าในคำสั่งซื้อ
 type OrderItem struct {
 	BaseModel
-	OrderID   uuid.UUID `json:"order_id"`
-	Order     Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Quantity  int       `gorm:"type:int" json:"quantity" validate:"required,min=1"`
-	Price     float64   `gorm:"type:decimal(10,2)" json:"price"`
+	OrderID   uuid.UUID      `json:"order_id"`
+	Order     Order          `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	ProductID uuid.UUID      `json:"product_id"`
+	Product   Product        `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Quantity  int            `gorm:"type:int" json:"quantity" validate:"required,min=1"`
+	Price     entities.Money `gorm:"type:numeric(18,2)" json:"price"`
+	// DiscountAmount ส่วนลดที่แบ่งลงรายการนี้
+	DiscountAmount entities.Money `gorm:"type:numeric(18,2);default:0" json:"discount_amount"`
+	// TaxClassID, TaxRate และ TaxAmount คือภาษีของรายการที่ตรึงไว้ตอนสั่งซื้อ
+	TaxClassID *uuid.UUID       `gorm:"type:uuid" json:"tax_class_id"`
+	TaxRate    entities.Percent `gorm:"type:numeric(7,4);default:0" json:"tax_rate"`
+	TaxAmount  entities.Money   `gorm:"type:numeric(18,2);default:0" json:"tax_amount"`
+	// RestockedQuantity จำนวนที่คืนเข้าสต็อกแล้ว (จากการยกเลิกหรือคืนเงิน) ป้องกันการคืนสต็อกซ้ำ
+	RestockedQuantity int `gorm:"type:int;default:0" json:"restocked_quantity"`
+}
+
+// TaxClass สำหรับเก็บประเภทภาษีของสินค้า
+type TaxClass struct {
+	BaseModel
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	IsDefault   bool      `gorm:"default:false" json:"is_default"`
+	Rates       []TaxRate `gorm:"foreignKey:TaxClassID" json:"rates,omitempty"`
+}
+
+// TaxRate สำหรับเก็บอัตราภาษีของประเภทภาษีแยกตามภูมิภาค
+type TaxRate struct {
+	BaseModel
+	TaxClassID uuid.UUID        `gorm:"type:uuid;not null" json:"tax_class_id"`
+	Region     string           `gorm:"type:varchar(10);not null" json:"region"`
+	Name       string           `gorm:"type:varchar(100);not null" json:"name"`
+	Rate       entities.Percent `gorm:"type:numeric(7,4);not null" json:"rate"`
+}
+
+// ShippingMethod สำหรับเก็บวิธีจัดส่ง
+type ShippingMethod struct {
+	BaseModel
+	Code              string         `gorm:"type:varchar(50);not null" json:"code"`
+	Name              string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description       string         `gorm:"type:varchar(255)" json:"description"`
+	Active            bool           `gorm:"default:true" json:"active"`
+	VolumetricDivisor int            `gorm:"type:int;default:0" json:"volumetric_divisor"`
+	SortOrder         int            `gorm:"type:int;default:0" json:"sort_order"`
+	Rates             []ShippingRate `gorm:"foreignKey:ShippingMethodID" json:"rates,omitempty"`
+}
+
+// ShippingRate สำหรับเก็บอัตราค่าจัดส่งของวิธีจัดส่งแยกตามโซนและช่วงน้ำหนัก
+type ShippingRate struct {
+	BaseModel
+	ShippingMethodID uuid.UUID      `gorm:"type:uuid;not null" json:"shipping_method_id"`
+	Zone             string         `gorm:"type:varchar(10);not null" json:"zone"`
+	Type             string         `gorm:"type:varchar(20);not null" json:"type"`
+	MinWeightGrams   int            `gorm:"type:int;default:0" json:"min_weight_grams"`
+	MaxWeightGrams   int            `gorm:"type:int;default:0" json:"max_weight_grams"`
+	Amount           entities.Money `gorm:"type:numeric(18,2);default:0" json:"amount"`
+	PerKg            entities.Money `gorm:"type:numeric(18,2);default:0" json:"per_kg"`
+	FreeOver         entities.Money `gorm:"type:numeric(18,2);default:0" json:"free_over"`
+}
+
+// Coupon สำหรับเก็บคูปองส่วนลดและส่วนลดอัตโนมัติ
+type Coupon struct {
+	BaseModel
+	Code         string           `gorm:"type:varchar(50);not null" json:"code"`
+	Description  string           `gorm:"type:varchar(255)" json:"description"`
+	Type         string           `gorm:"type:varchar(20);not null" json:"type"`
+	Percentage   entities.Percent `gorm:"type:numeric(7,4);default:0" json:"percentage"`
+	Amount       entities.Money   `gorm:"type:numeric(18,2);default:0" json:"amount"`
+	MaxDiscount  entities.Money   `gorm:"type:numeric(18,2);default:0" json:"max_discount"`
+	MinSpend     entities.Money   `gorm:"type:numeric(18,2);default:0" json:"min_spend"`
+	UsageLimit   int              `gorm:"type:int;default:0" json:"usage_limit"`
+	PerUserLimit int              `gorm:"type:int;default:0" json:"per_user_limit"`
+	UsedCount    int              `gorm:"type:int;default:0" json:"used_count"`
+	StartsAt     *time.Time       `json:"starts_at"`
+	EndsAt       *time.Time       `json:"ends_at"`
+	Active       bool             `gorm:"default:true" json:"active"`
+	Automatic    bool             `gorm:"default:false" json:"automatic"`
+	Products     []Product        `gorm:"many2many:coupon_products;" json:"products,omitempty"`
+	Categories   []Category       `gorm:"many2many:coupon_categories;" json:"categories,omitempty"`
+}
+
+// CouponRedemption สำหรับเก็บการใช้คูปองกับคำสั่งซื้อ
+type CouponRedemption struct {
+	BaseModel
+	CouponID   uuid.UUID      `gorm:"type:uuid;not null" json:"coupon_id"`
+	Code       string         `gorm:"type:varchar(50);not null" json:"code"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	OrderID    uuid.UUID      `gorm:"type:uuid;not null" json:"order_id"`
+	Amount     entities.Money `gorm:"type:numeric(18,2);not null" json:"amount"`
+	Currency   string         `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	ReleasedAt *time.Time     `json:"released_at"`
+}
+
+// StockReservation สำหรับเก็บการจองสต็อกชั่วคราวของตะกร้าหรือคำสั่งซื้อ
+type StockReservation struct {
+	BaseModel
+	ProductID uuid.UUID  `gorm:"type:uuid;not null" json:"product_id"`
+	CartID    *uuid.UUID `gorm:"type:uuid" json:"cart_id"`
+	OrderID   *uuid.UUID `gorm:"type:uuid" json:"order_id"`
+	Quantity  int        `gorm:"type:int;not null" json:"quantity"`
+	Status    string     `gorm:"type:varchar(20);default:'active'" json:"status"`
+	ExpiresAt time.Time  `json:"expires_at"`
 }
 
 // Transaction สำหรับเก็บข้อมูลธุรกรรมการชำระเงิน
 type Transaction struct {
 	BaseModel
-	OrderID       uuid.UUID `json:"order_id"`
-	Order         Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
-	Amount        float64   `gorm:"type:decimal(10,2)" json:"amount"`
-	PaymentMethod string    `gorm:"type:varchar(50)" json:"payment_method"`
-	Status        string    `gorm:"type:varchar(50);default:'pending'" json:"status"`
-	TransactionID string    `gorm:"type:varchar(100)" json:"transaction_id"`
-	PaymentData   string    `gorm:"type:text" json:"payment_data"`
-}
\ No newline at end of file
+	OrderID       uuid.UUID      `json:"order_id"`
+	Order         Order          `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	Amount        entities.Money `gorm:"type:numeric(18,2)" json:"amount"`
+	Currency      string         `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	PaymentMethod string         `gorm:"type:varchar(50)" json:"payment_method"`
+	Status        string         `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	TransactionID string         `gorm:"type:varchar(100)" json:"transaction_id"`
+	PaymentData   string         `gorm:"type:text" json:"payment_data"`
+	Provider      string         `gorm:"type:varchar(50)" json:"provider"`
+	ProviderRef   string         `gorm:"type:varchar(100)" json:"provider_ref"`
+	Refunds       []Refund       `gorm:"foreignKey:TransactionID" json:"refunds,omitempty"`
+}
+
+// Shipment สำหรับเก็บพัสดุที่จัดส่งของคำสั่งซื้อ
+type Shipment struct {
+	BaseModel
+	OrderID        uuid.UUID      `gorm:"type:uuid;index" json:"order_id"`
+	Carrier        string         `gorm:"type:varchar(100)" json:"carrier"`
+	TrackingNumber string         `gorm:"type:varchar(100)" json:"tracking_number"`
+	Status         string         `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	ShippedAt      *time.Time     `json:"shipped_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	Items          []ShipmentItem `gorm:"foreignKey:ShipmentID" json:"items,omitempty"`
+}
+
+// ShipmentItem สำหรับเก็บจำนวนสินค้าในแต่ละพัสดุ
+type ShipmentItem struct {
+	BaseModel
+	ShipmentID  uuid.UUID `gorm:"type:uuid;index" json:"shipment_id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;index" json:"order_item_id"`
+	Quantity    int       `gorm:"type:int" json:"quantity"`
+}
+
+// OrderStatusHistory สำหรับเก็บประวัติการเปลี่ยนสถานะคำสั่งซื้อ
+type OrderStatusHistory struct {
+	BaseModel
+	OrderID    uuid.UUID  `gorm:"type:uuid;index" json:"order_id"`
+	Field      string     `gorm:"type:varchar(30)" json:"field"`
+	FromStatus string     `gorm:"type:varchar(50)" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(50)" json:"to_status"`
+	ChangedBy  *uuid.UUID `gorm:"type:uuid" json:"changed_by"`
+	Note       string     `gorm:"type:text" json:"note"`
+}
+
+// Refund สำหรับเก็บรายการคืนเงินของ Transaction
+type Refund struct {
+	BaseModel
+	TransactionID uuid.UUID      `gorm:"type:uuid;index" json:"transaction_id"`
+	Amount        entities.Money `gorm:"type:numeric(18,2)" json:"amount"`
+	Reason        string         `gorm:"type:text" json:"reason"`
+	Status        string         `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	ProviderRef   string         `gorm:"type:varchar(100)" json:"provider_ref"`
+	Restock       bool           `gorm:"default:false" json:"restock"`
+	CreatedBy     *uuid.UUID     `gorm:"type:uuid" json:"created_by"`
+	Items         []RefundItem   `gorm:"foreignKey:RefundID" json:"items,omitempty"`
+}
+
+// RefundItem สำหรับเก็บจำนวนสินค้าที่คืนเข้าสต็อก
+type RefundItem struct {
+	BaseModel
+	RefundID    uuid.UUID `gorm:"type:uuid;index" json:"refund_id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;index" json:"order_item_id"`
+	Quantity    int       `gorm:"type:int" json:"quantity"`
+}
+
+// OutboxEvent สำหรับเก็บ domain event ที่รอส่งออก (transactional outbox)
+type OutboxEvent struct {
+	BaseModel
+	EventType     string     `gorm:"type:varchar(100);index" json:"event_type"`
+	AggregateType string     `gorm:"type:varchar(50)" json:"aggregate_type"`
+	AggregateID   uuid.UUID  `gorm:"type:uuid" json:"aggregate_id"`
+	Payload       string     `gorm:"type:jsonb" json:"payload"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	Status        string     `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Attempts      int        `gorm:"type:int;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	DispatchedAt  *time.Time `json:"dispatched_at"`
+	// DeliveredSinks ชื่อ sink ที่ส่งสำเร็จแล้ว คั่นด้วยจุลภาค
+	DeliveredSinks string `gorm:"type:text;not null;default:''" json:"delivered_sinks"`
+}
+
+// WebhookEndpoint สำหรับเก็บปลายทาง webhook ของ partner
+type WebhookEndpoint struct {
+	BaseModel
+	URL         string `gorm:"type:varchar(500)" json:"url"`
+	Description string `gorm:"type:text" json:"description"`
+	EventTypes  string `gorm:"type:text" json:"event_types"`
+	Secret      string `gorm:"type:varchar(100)" json:"-"`
+	Active      bool   `gorm:"default:true" json:"active"`
+}
+
+// WebhookDelivery สำหรับเก็บประวัติการส่ง webhook แต่ละครั้ง
+type WebhookDelivery struct {
+	BaseModel
+	EndpointID     uuid.UUID       `gorm:"type:uuid;index" json:"endpoint_id"`
+	Endpoint       WebhookEndpoint `gorm:"foreignKey:EndpointID" json:"endpoint,omitempty"`
+	EventID        uuid.UUID       `gorm:"type:uuid" json:"event_id"`
+	EventType      string          `gorm:"type:varchar(100)" json:"event_type"`
+	Payload        string          `gorm:"type:jsonb" json:"payload"`
+	Status         string          `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	Attempts       int             `gorm:"type:int;default:0" json:"attempts"`
+	ResponseStatus int             `gorm:"type:int" json:"response_status"`
+	ResponseBody   string          `gorm:"type:text" json:"response_body"`
+	LastError      string          `gorm:"type:text" json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LockedUntil    *time.Time      `json:"locked_until"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	RedeliveryOf   *uuid.UUID      `gorm:"type:uuid" json:"redelivery_of"`
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) repositories.AddressRepository {
+	return &addressRepository{db: db}
+}
+
+func (r *addressRepository) Create(ctx context.Context, address *entities.Address) error {
+	addressModel := &models.Address{
+		UserID:            address.UserID,
+		Label:             address.Label,
+		AddressFields:     models.AddressFields(address.AddressFields),
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultAddresses(tx, address.UserID, uuid.Nil, address.IsDefaultShipping, address.IsDefaultBilling); err != nil {
+			return err
+		}
+		return tx.Create(addressModel).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	address.ID = addressModel.ID
+	address.CreatedAt = addressModel.CreatedAt
+	address.UpdatedAt = addressModel.UpdatedAt
+	return nil
+}
+
+func (r *addressRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Address, error) {
+	var address models.Address
+	if err := r.db.WithContext(ctx).First(&address, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return addressModelToEntity(&address), nil
+}
+
+func (r *addressRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error) {
+	var addresses []models.Address
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("is_default_shipping DESC, is_default_billing DESC, created_at").
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	result := []*entities.Address{}
+	for _, address := range addresses {
+		result = append(result, addressModelToEntity(&address))
+	}
+
+	return result, nil
+}
+
+func (r *addressRepository) Update(ctx context.Context, id uuid.UUID, address *entities.Address) error {
+	updates := map[string]interface{}{
+		"label":               address.Label,
+		"recipient":           address.Recipient,
+		"phone":               address.Phone,
+		"line1":               address.Line1,
+		"line2":               address.Line2,
+		"sub_district":        address.SubDistrict,
+		"district":            address.District,
+		"province":            address.Province,
+		"postal_code":         address.PostalCode,
+		"country":             address.Country,
+		"region_code":         address.RegionCode,
+		"is_default_shipping": address.IsDefaultShipping,
+		"is_default_billing":  address.IsDefaultBilling,
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultAddresses(tx, address.UserID, id, address.IsDefaultShipping, address.IsDefaultBilling); err != nil {
+			return err
+		}
+		return tx.Model(&models.Address{}).Where("id = ?", id).Updates(updates).Error
+	})
+}
+
+// Delete ลบที่อยู่ออกจากสมุดที่อยู่ สำเนาที่อยู่ในคำสั่งซื้อเดิมยังอยู่ครบ
+func (r *addressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Address{}, "id = ?", id).Error
+}
+
+// clearDefaultAddresses ยกเลิกที่อยู่เริ่มต้นเดิมของผู้ใช้ (ยกเว้น exceptID) ก่อนกำหนดที่อยู่เริ่มต้นใหม่
+func clearDefaultAddresses(tx *gorm.DB, userID, exceptID uuid.UUID, shipping, billing bool) error {
+	if shipping {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND id <> ? AND is_default_shipping = ?", userID, exceptID, true).
+			Update("is_default_shipping", false).Error; err != nil {
+			return err
+		}
+	}
+	if billing {
+		if err := tx.Model(&models.Address{}).
+			Where("user_id = ? AND id <> ? AND is_default_billing = ?", userID, exceptID, true).
+			Update("is_default_billing", false).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createOrderAddress บันทึกสำเนาที่อยู่ของคำสั่งซื้อ
+func createOrderAddress(tx *gorm.DB, orderID uuid.UUID, kind string, address *entities.Address) error {
+	return tx.Create(&models.OrderAddress{
+		OrderID:       orderID,
+		Kind:          kind,
+		AddressID:     &address.ID,
+		AddressFields: models.AddressFields(address.AddressFields),
+	}).Error
+}
+
+func addressModelToEntity(address *models.Address) *entities.Address {
+	return &entities.Address{
+		ID:                address.ID,
+		UserID:            address.UserID,
+		Label:             address.Label,
+		AddressFields:     entities.AddressFields(address.AddressFields),
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt,
+		UpdatedAt:         address.UpdatedAt,
+	}
+}
+
+func orderAddressModelToEntity(address *models.OrderAddress) entities.OrderAddress {
+	return entities.OrderAddress{
+		ID:            address.ID,
+		OrderID:       address.OrderID,
+		Kind:          address.Kind,
+		AddressID:     address.AddressID,
+		AddressFields: entities.AddressFields(address.AddressFields),
+		CreatedAt:     address.CreatedAt,
+	}
+}
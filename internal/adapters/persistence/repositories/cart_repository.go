@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
@@ -22,12 +23,13 @@ func (r *cartRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*en
 	var cart models.Cart
 
 	// หาตะกร้าของผู้ใช้ หากไม่มีให้สร้างใหม่
-	if err := r.db.WithContext(ctx).Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("CartItems.Product").Preload("Coupon").Where("user_id = ?", userID).First(&cart).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// สร้างตะกร้าใหม่
 			newCart := &models.Cart{
 				UserID:     userID,
 				TotalPrice: 0,
+				Currency:   string(entities.DefaultCurrency),
 			}
 			if err := r.db.WithContext(ctx).Create(newCart).Error; err != nil {
 				return nil, err
@@ -40,66 +42,96 @@ func (r *cartRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*en
 	return r.modelToEntity(&cart), nil
 }
 
-func (r *cartRepository) AddItem(ctx context.Context, userID uuid.UUID, item *entities.AddToCartRequest) error {
+func (r *cartRepository) AddItem(ctx context.Context, userID uuid.UUID, item *entities.AddToCartRequest, pricing entities.PriceContext, holdUntil time.Time) error {
 	// หาตะกร้าของผู้ใช้
 	cart, err := r.GetByUserID(ctx, userID)
 	if err != nil {
 		return err
 	}
-
-	// ตรวจสอบว่าสินค้ามีอยู่หรือไม่
-	var product models.Product
-	if err := r.db.WithContext(ctx).First(&product, "id = ?", item.ProductID).Error; err != nil {
-		return err
+	if cart.Currency != pricing.Currency {
+		return entities.ErrCartCurrencyChanged
 	}
 
-	// ตรวจสอบสต็อก
-	if product.Stock < item.Quantity {
-		return gorm.ErrInvalidData
-	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// ตรวจสอบว่าสินค้ามีอยู่หรือไม่
+		var product models.Product
+		if err := tx.Preload("Prices").First(&product, "id = ?", item.ProductID).Error; err != nil {
+			return err
+		}
+		price := unitPrice(pricing, &product)
 
-	// ตรวจสอบว่าสินค้านี้มีในตะกร้าแล้วหรือไม่
-	var existingItem models.CartItem
-	if err := r.db.WithContext(ctx).Where("cart_id = ? AND product_id = ?", cart.ID, item.ProductID).First(&existingItem).Error; err == nil {
-		// อัพเดทจำนวน
-		newQuantity := existingItem.Quantity + item.Quantity
-		if product.Stock < newQuantity {
-			return gorm.ErrInvalidData
+		// ตรวจสอบว่าสินค้านี้มีในตะกร้าแล้วหรือไม่
+		var existingItem models.CartItem
+		newQuantity := item.Quantity
+		exists := tx.Where("cart_id = ? AND product_id = ?", cart.ID, item.ProductID).First(&existingItem).Error == nil
+		if exists {
+			newQuantity += existingItem.Quantity
 		}
-		return r.db.WithContext(ctx).Model(&existingItem).Updates(map[string]interface{}{
-			"quantity": newQuantity,
-			"price":    product.Price,
-		}).Error
-	}
 
-	// เพิ่มสินค้าใหม่ลงตะกร้า
-	cartItem := &models.CartItem{
-		CartID:    cart.ID,
-		ProductID: item.ProductID,
-		Quantity:  item.Quantity,
-		Price:     product.Price,
-	}
+		// ตรวจสอบสต็อกที่ขายได้ (หักที่ตะกร้าอื่นจองไว้)
+		if err := checkSellable(tx, &product, cart.ID, newQuantity); err != nil {
+			return err
+		}
+
+		if exists {
+			// อัพเดทจำนวน
+			if err := tx.Model(&existingItem).Updates(map[string]interface{}{
+				"quantity": newQuantity,
+				"price":    price,
+			}).Error; err != nil {
+				return err
+			}
+		} else {
+			// เพิ่มสินค้าใหม่ลงตะกร้า
+			cartItem := &models.CartItem{
+				CartID:    cart.ID,
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Price:     price,
+			}
+			if err := tx.Create(cartItem).Error; err != nil {
+				return err
+			}
+		}
 
-	return r.db.WithContext(ctx).Create(cartItem).Error
+		return holdCartItem(tx, cart.ID, product.ID, newQuantity, holdUntil)
+	})
 }
 
-func (r *cartRepository) UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int) error {
-	// หา cart item
-	var cartItem models.CartItem
-	if err := r.db.WithContext(ctx).Preload("Product").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
-		return err
-	}
+func (r *cartRepository) UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int, holdUntil time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// หา cart item
+		var cartItem models.CartItem
+		if err := tx.Preload("Product").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
+			return err
+		}
 
-	// ตรวจสอบสต็อก
-	if cartItem.Product.Stock < quantity {
-		return gorm.ErrInvalidData
-	}
+		// ตรวจสอบสต็อกที่ขายได้
+		if err := checkSellable(tx, &cartItem.Product, cartItem.CartID, quantity); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&cartItem).Update("quantity", quantity).Error; err != nil {
+			return err
+		}
 
-	return r.db.WithContext(ctx).Model(&cartItem).Update("quantity", quantity).Error
+		return holdCartItem(tx, cartItem.CartID, cartItem.ProductID, quantity, holdUntil)
+	})
 }
 
 func (r *cartRepository) RemoveItem(ctx context.Context, cartItemID uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.CartItem{}, "id = ?", cartItemID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cartItem models.CartItem
+		if err := tx.First(&cartItem, "id = ?", cartItemID).Error; err != nil {
+			return err
+		}
+
+		if err := releaseCartHolds(tx, cartItem.CartID, &cartItem.ProductID); err != nil {
+			return err
+		}
+
+		return tx.Delete(&cartItem).Error
+	})
 }
 
 func (r *cartRepository) ClearCart(ctx context.Context, userID uuid.UUID) error {
@@ -109,8 +141,53 @@ func (r *cartRepository) ClearCart(ctx context.Context, userID uuid.UUID) error
 		return err
 	}
 
-	// ลบรายการทั้งหมดในตะกร้า
-	return r.db.WithContext(ctx).Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error
+	// ลบรายการทั้งหมดในตะกร้าพร้อมปล่อยสต็อกที่จองไว้
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := releaseCartHolds(tx, cart.ID, nil); err != nil {
+			return err
+		}
+		return tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error
+	})
+}
+
+func (r *cartRepository) SetCurrency(ctx context.Context, userID uuid.UUID, pricing entities.PriceContext) (*entities.Cart, error) {
+	cart, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Cart{}).Where("id = ?", cart.ID).Update("currency", string(pricing.Currency)).Error; err != nil {
+			return err
+		}
+
+		// คิดราคาทุกรายการใหม่ในสกุลเงินที่เลือก
+		var items []models.CartItem
+		if err := tx.Preload("Product.Prices").Where("cart_id = ?", cart.ID).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := tx.Model(&item).Update("price", unitPrice(pricing, &item.Product)).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByUserID(ctx, userID)
+}
+
+func (r *cartRepository) SetCoupon(ctx context.Context, userID uuid.UUID, couponID *uuid.UUID) error {
+	cart, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Model(&models.Cart{}).Where("id = ?", cart.ID).Update("coupon_id", couponID).Error
 }
 
 func (r *cartRepository) GetCartItem(ctx context.Context, cartItemID uuid.UUID) (*entities.CartItem, error) {
@@ -127,16 +204,22 @@ func (r *cartRepository) modelToEntity(cart *models.Cart) *entities.Cart {
 		ID:         cart.ID,
 		UserID:     cart.UserID,
 		TotalPrice: cart.TotalPrice,
+		Currency:   entities.Currency(cart.Currency),
+		CouponID:   cart.CouponID,
 		CreatedAt:  cart.CreatedAt,
 		UpdatedAt:  cart.UpdatedAt,
 	}
+	if cart.Coupon != nil {
+		cartEntity.CouponCode = cart.Coupon.Code
+	}
 
-	// คำนวณราคารวม
-	var totalPrice float64
+	// คำนวณราคารวมจากราคารวมของแต่ละรายการ (ส่วนลดคิดใน CartService)
+	var totalPrice entities.Money
 	for _, item := range cart.CartItems {
 		cartEntity.CartItems = append(cartEntity.CartItems, *r.cartItemModelToEntity(&item))
-		totalPrice += item.Price * float64(item.Quantity)
+		totalPrice = totalPrice.Add(entities.LineTotal(item.Price, item.Quantity))
 	}
+	cartEntity.Subtotal = totalPrice
 	cartEntity.TotalPrice = totalPrice
 
 	return cartEntity
@@ -159,13 +242,42 @@ func (r *cartRepository) cartItemModelToEntity(cartItem *models.CartItem) *entit
 			Name:        cartItem.Product.Name,
 			Description: cartItem.Product.Description,
 			Price:       cartItem.Product.Price,
+			Currency:    entities.Currency(cartItem.Product.Currency),
 			Stock:       cartItem.Product.Stock,
 			Image:       cartItem.Product.Image,
 			CategoryID:  cartItem.Product.CategoryID,
+			TaxClassID:  cartItem.Product.TaxClassID,
+			WeightGrams: cartItem.Product.WeightGrams,
+			LengthCm:    cartItem.Product.LengthCm,
+			WidthCm:     cartItem.Product.WidthCm,
+			HeightCm:    cartItem.Product.HeightCm,
 			CreatedAt:   cartItem.Product.CreatedAt,
 			UpdatedAt:   cartItem.Product.UpdatedAt,
 		}
 	}
 
 	return item
-}
\ No newline at end of file
+}
+
+// checkSellable ตรวจว่าสต็อกหลังหักการจองของตะกร้าอื่นพอสำหรับจำนวนที่ต้องการ
+func checkSellable(tx *gorm.DB, product *models.Product, cartID uuid.UUID, quantity int) error {
+	held, err := heldByOtherCarts(tx, []uuid.UUID{product.ID}, cartID)
+	if err != nil {
+		return err
+	}
+
+	available := product.Stock - held[product.ID]
+	if available < 0 {
+		available = 0
+	}
+	if quantity > available {
+		return &entities.InsufficientStockError{Items: []entities.InsufficientStockItem{{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Requested: quantity,
+			Available: available,
+		}}}
+	}
+
+	return nil
+}
@@ -23,6 +23,7 @@ func (r *categoryRepository) Create(ctx context.Context, req *entities.CreateCat
 		Name:        req.Name,
 		Description: req.Description,
 		Image:       req.Image,
+		TaxClassID:  req.TaxClassID,
 	}
 
 	if err := r.db.WithContext(ctx).Create(categoryModel).Error; err != nil {
@@ -75,6 +76,9 @@ func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, req *enti
 	if req.Image != "" {
 		updates["image"] = req.Image
 	}
+	if req.TaxClassID != nil {
+		updates["tax_class_id"] = taxClassColumn(req.TaxClassID)
+	}
 
 	return r.db.WithContext(ctx).Model(&models.Category{}).Where("id = ?", id).Updates(updates).Error
 }
@@ -89,7 +93,8 @@ func (r *categoryRepository) modelToEntity(categoryModel *models.Category) *enti
 		Name:        categoryModel.Name,
 		Description: categoryModel.Description,
 		Image:       categoryModel.Image,
+		TaxClassID:  categoryModel.TaxClassID,
 		CreatedAt:   categoryModel.CreatedAt,
 		UpdatedAt:   categoryModel.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,412 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) repositories.CouponRepository {
+	return &couponRepository{db: db}
+}
+
+// Create สร้างคูปองพร้อมขอบเขตสินค้าและหมวดหมู่ใน transaction เดียวกัน
+func (r *couponRepository) Create(ctx context.Context, coupon *entities.Coupon) error {
+	couponModel := &models.Coupon{
+		Code:         coupon.Code,
+		Description:  coupon.Description,
+		Type:         coupon.Type,
+		Percentage:   coupon.Percentage,
+		Amount:       coupon.Amount,
+		MaxDiscount:  coupon.MaxDiscount,
+		MinSpend:     coupon.MinSpend,
+		UsageLimit:   coupon.UsageLimit,
+		PerUserLimit: coupon.PerUserLimit,
+		StartsAt:     coupon.StartsAt,
+		EndsAt:       coupon.EndsAt,
+		Active:       coupon.Active,
+		Automatic:    coupon.Automatic,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Products", "Categories").Create(couponModel).Error; err != nil {
+			return err
+		}
+		// GORM ข้ามค่า false ของฟิลด์ที่มี default:true จึงต้องบันทึกแยก
+		if !coupon.Active {
+			if err := tx.Model(couponModel).Update("active", false).Error; err != nil {
+				return err
+			}
+		}
+		return replaceCouponScope(tx, couponModel.ID, coupon.ProductIDs, coupon.CategoryIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	coupon.ID = couponModel.ID
+	coupon.CreatedAt = couponModel.CreatedAt
+	coupon.UpdatedAt = couponModel.UpdatedAt
+	return nil
+}
+
+func (r *couponRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Coupon, error) {
+	var coupon models.Coupon
+	if err := couponQuery(r.db.WithContext(ctx)).First(&coupon, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return couponModelToEntity(&coupon), nil
+}
+
+func (r *couponRepository) GetByCode(ctx context.Context, code string) (*entities.Coupon, error) {
+	var coupon models.Coupon
+	if err := couponQuery(r.db.WithContext(ctx)).Where("code = ?", code).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+
+	return couponModelToEntity(&coupon), nil
+}
+
+func (r *couponRepository) GetAll(ctx context.Context, page, limit int) ([]*entities.Coupon, int, error) {
+	var coupons []models.Coupon
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.WithContext(ctx).Model(&models.Coupon{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := couponQuery(r.db.WithContext(ctx)).Order("created_at DESC").Offset(offset).Limit(limit).Find(&coupons).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.Coupon
+	for _, coupon := range coupons {
+		result = append(result, couponModelToEntity(&coupon))
+	}
+
+	return result, int(total), nil
+}
+
+func (r *couponRepository) GetAutomatic(ctx context.Context) ([]*entities.Coupon, error) {
+	return automaticCoupons(r.db.WithContext(ctx))
+}
+
+// Update บันทึกทุกฟิลด์ของคูปอง (ยกเว้น used_count) และแทนที่ขอบเขตสินค้าและหมวดหมู่
+func (r *couponRepository) Update(ctx context.Context, id uuid.UUID, coupon *entities.Coupon) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Coupon{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"description":    coupon.Description,
+			"type":           coupon.Type,
+			"percentage":     coupon.Percentage,
+			"amount":         coupon.Amount,
+			"max_discount":   coupon.MaxDiscount,
+			"min_spend":      coupon.MinSpend,
+			"usage_limit":    coupon.UsageLimit,
+			"per_user_limit": coupon.PerUserLimit,
+			"starts_at":      coupon.StartsAt,
+			"ends_at":        coupon.EndsAt,
+			"active":         coupon.Active,
+			"automatic":      coupon.Automatic,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceCouponScope(tx, id, coupon.ProductIDs, coupon.CategoryIDs)
+	})
+}
+
+// Delete ลบคูปองแบบ soft delete และเอาออกจากตะกร้าที่กรอกไว้
+func (r *couponRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Cart{}).Where("coupon_id = ?", id).Update("coupon_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Coupon{}, "id = ?", id).Error
+	})
+}
+
+func (r *couponRepository) CountUserRedemptions(ctx context.Context, userID uuid.UUID, couponIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return countUserRedemptions(r.db.WithContext(ctx), userID, couponIDs)
+}
+
+// couponQuery โหลดคูปองพร้อมขอบเขต (เฉพาะ id ของสินค้าและหมวดหมู่)
+func couponQuery(db *gorm.DB) *gorm.DB {
+	return db.Preload("Products", func(db *gorm.DB) *gorm.DB {
+		return db.Select("products.id")
+	}).Preload("Categories", func(db *gorm.DB) *gorm.DB {
+		return db.Select("categories.id")
+	})
+}
+
+// automaticCoupons ส่วนลดอัตโนมัติที่เปิดใช้งานอยู่ (ช่วงเวลาและจำนวนครั้งตรวจตอนคิดส่วนลด)
+func automaticCoupons(db *gorm.DB) ([]*entities.Coupon, error) {
+	var coupons []models.Coupon
+	if err := couponQuery(db).Where("automatic = ? AND active = ?", true, true).Order("created_at").Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Coupon
+	for _, coupon := range coupons {
+		result = append(result, couponModelToEntity(&coupon))
+	}
+	return result, nil
+}
+
+// countUserRedemptions จำนวนครั้งที่ผู้ใช้ใช้คูปองแต่ละใบ ไม่นับการใช้ที่ถูกปล่อยคืนจากการยกเลิก
+func countUserRedemptions(db *gorm.DB, userID uuid.UUID, couponIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	result := make(map[uuid.UUID]int, len(couponIDs))
+	if len(couponIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		CouponID uuid.UUID
+		Count    int
+	}
+	if err := db.Model(&models.CouponRedemption{}).
+		Select("coupon_id, COUNT(*) AS count").
+		Where("user_id = ? AND coupon_id IN ? AND released_at IS NULL", userID, couponIDs).
+		Group("coupon_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.CouponID] = row.Count
+	}
+	return result, nil
+}
+
+// replaceCouponScope แทนที่สินค้าและหมวดหมู่ที่ร่วมรายการของคูปอง
+// เขียนตาราง join โดยตรงเพื่อไม่ให้ GORM upsert สินค้าหรือหมวดหมู่ที่ไม่มีอยู่จริง
+func replaceCouponScope(tx *gorm.DB, couponID uuid.UUID, productIDs, categoryIDs []uuid.UUID) error {
+	productIDs = uniqueIDs(productIDs)
+	categoryIDs = uniqueIDs(categoryIDs)
+
+	if err := ensureScopeExists(tx, &models.Product{}, productIDs, "สินค้า"); err != nil {
+		return err
+	}
+	if err := ensureScopeExists(tx, &models.Category{}, categoryIDs, "หมวดหมู่"); err != nil {
+		return err
+	}
+
+	if err := tx.Exec("DELETE FROM coupon_products WHERE coupon_id = ?", couponID).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM coupon_categories WHERE coupon_id = ?", couponID).Error; err != nil {
+		return err
+	}
+
+	for _, id := range productIDs {
+		if err := tx.Exec("INSERT INTO coupon_products (coupon_id, product_id) VALUES (?, ?)", couponID, id).Error; err != nil {
+			return err
+		}
+	}
+	for _, id := range categoryIDs {
+		if err := tx.Exec("INSERT INTO coupon_categories (coupon_id, category_id) VALUES (?, ?)", couponID, id).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ensureScopeExists(tx *gorm.DB, model interface{}, ids []uuid.UUID, label string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := tx.Model(model).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(ids) {
+		return fmt.Errorf("%w: ไม่พบ%sบางรายการที่กำหนดให้คูปอง", entities.ErrInvalidCoupon, label)
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var result []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// checkoutDiscounts คิดส่วนลดของคำสั่งซื้อจากราคาที่คิดใหม่ตอนสั่งซื้อ
+// คืนรหัสคูปองที่ลูกค้ากรอก หรือ error หากคูปองในตะกร้าใช้ไม่ได้แล้ว (ไม่สร้างคำสั่งซื้อโดยตัดส่วนลดทิ้งเงียบ ๆ)
+func checkoutDiscounts(tx *gorm.DB, userID uuid.UUID, couponID *uuid.UUID, lines []entities.DiscountLine, pricing entities.PriceContext) (entities.DiscountResult, string, error) {
+	automatic, err := automaticCoupons(tx)
+	if err != nil {
+		return entities.DiscountResult{}, "", err
+	}
+
+	couponIDs := make([]uuid.UUID, 0, len(automatic)+1)
+	for _, candidate := range automatic {
+		couponIDs = append(couponIDs, candidate.ID)
+	}
+
+	var coupon *entities.Coupon
+	if couponID != nil {
+		var couponModel models.Coupon
+		if err := couponQuery(tx).First(&couponModel, "id = ?", *couponID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.DiscountResult{}, "", entities.ErrCouponNotApplicable
+			}
+			return entities.DiscountResult{}, "", err
+		}
+		coupon = couponModelToEntity(&couponModel)
+		couponIDs = append(couponIDs, coupon.ID)
+	}
+
+	used, err := countUserRedemptions(tx, userID, couponIDs)
+	if err != nil {
+		return entities.DiscountResult{}, "", err
+	}
+
+	result := entities.ApplyDiscounts(lines, automatic, coupon, used, pricing, time.Now())
+	if result.CouponErr != nil {
+		return entities.DiscountResult{}, "", result.CouponErr
+	}
+
+	code := ""
+	if coupon != nil {
+		code = coupon.Code
+	}
+	return result, code, nil
+}
+
+// redeemCoupons บันทึกการใช้คูปองที่คิดส่วนลดให้คำสั่งซื้อ
+// ล็อกแถวคูปองตามลำดับ id (กัน deadlock) แล้วตรวจจำนวนครั้งอีกครั้งด้วยค่าล่าสุด
+// คำสั่งซื้อที่ชนกันจึงไม่สามารถใช้คูปองเกินจำนวนที่กำหนดได้
+func redeemCoupons(tx *gorm.DB, userID, orderID uuid.UUID, discounts []entities.AppliedDiscount, currency entities.Currency) error {
+	if len(discounts) == 0 {
+		return nil
+	}
+
+	sorted := append([]entities.AppliedDiscount(nil), discounts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CouponID.String() < sorted[j].CouponID.String()
+	})
+
+	for _, discount := range sorted {
+		var coupon models.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, "id = ?", discount.CouponID).Error; err != nil {
+			return err
+		}
+
+		used, err := countUserRedemptions(tx, userID, []uuid.UUID{coupon.ID})
+		if err != nil {
+			return err
+		}
+		if err := couponModelToEntity(&coupon).CheckUsage(used[coupon.ID]); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.CouponRedemption{
+			CouponID: coupon.ID,
+			Code:     coupon.Code,
+			UserID:   userID,
+			OrderID:  orderID,
+			Amount:   discount.Amount,
+			Currency: string(currency),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Coupon{}).Where("id = ?", coupon.ID).
+			UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseCouponRedemptions คืนสิทธิ์การใช้คูปองของคำสั่งซื้อที่ถูกยกเลิก (เรียกซ้ำได้)
+func releaseCouponRedemptions(tx *gorm.DB, orderID uuid.UUID) error {
+	var redemptions []models.CouponRedemption
+	if err := tx.Where("order_id = ? AND released_at IS NULL", orderID).Find(&redemptions).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, redemption := range redemptions {
+		if err := tx.Model(&models.CouponRedemption{}).Where("id = ?", redemption.ID).Update("released_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Coupon{}).Where("id = ? AND used_count > 0", redemption.CouponID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func couponModelToEntity(coupon *models.Coupon) *entities.Coupon {
+	couponEntity := &entities.Coupon{
+		ID:           coupon.ID,
+		Code:         coupon.Code,
+		Description:  coupon.Description,
+		Type:         coupon.Type,
+		Percentage:   coupon.Percentage,
+		Amount:       coupon.Amount,
+		MaxDiscount:  coupon.MaxDiscount,
+		MinSpend:     coupon.MinSpend,
+		UsageLimit:   coupon.UsageLimit,
+		PerUserLimit: coupon.PerUserLimit,
+		UsedCount:    coupon.UsedCount,
+		StartsAt:     coupon.StartsAt,
+		EndsAt:       coupon.EndsAt,
+		Active:       coupon.Active,
+		Automatic:    coupon.Automatic,
+		ProductIDs:   []uuid.UUID{},
+		CategoryIDs:  []uuid.UUID{},
+		CreatedAt:    coupon.CreatedAt,
+		UpdatedAt:    coupon.UpdatedAt,
+	}
+
+	for _, product := range coupon.Products {
+		couponEntity.ProductIDs = append(couponEntity.ProductIDs, product.ID)
+	}
+	for _, category := range coupon.Categories {
+		couponEntity.CategoryIDs = append(couponEntity.CategoryIDs, category.ID)
+	}
+
+	return couponEntity
+}
+
+func couponRedemptionModelToEntity(redemption *models.CouponRedemption) entities.CouponRedemption {
+	return entities.CouponRedemption{
+		ID:         redemption.ID,
+		CouponID:   redemption.CouponID,
+		Code:       redemption.Code,
+		UserID:     redemption.UserID,
+		OrderID:    redemption.OrderID,
+		Amount:     redemption.Amount,
+		Currency:   entities.Currency(redemption.Currency),
+		ReleasedAt: redemption.ReleasedAt,
+		CreatedAt:  redemption.CreatedAt,
+	}
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paymentTimeoutNote หมายเหตุในประวัติคำสั่งซื้อเมื่อถูกยกเลิกอัตโนมัติ
+const paymentTimeoutNote = "ยกเลิกอัตโนมัติ: เกินกำหนดเวลาชำระเงิน"
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) repositories.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) GetStockLevel(ctx context.Context, productID uuid.UUID) (*entities.StockLevel, error) {
+	var product models.Product
+	if err := r.db.WithContext(ctx).Select("id", "stock").First(&product, "id = ?", productID).Error; err != nil {
+		return nil, err
+	}
+
+	var held struct {
+		Orders int
+		Carts  int
+	}
+	if err := r.db.WithContext(ctx).Model(&models.StockReservation{}).
+		Select("COALESCE(SUM(CASE WHEN order_id IS NOT NULL THEN quantity END), 0) AS orders, "+
+			"COALESCE(SUM(CASE WHEN cart_id IS NOT NULL AND expires_at > ? THEN quantity END), 0) AS carts", time.Now()).
+		Where("product_id = ? AND status = ?", productID, entities.ReservationActive).
+		Scan(&held).Error; err != nil {
+		return nil, err
+	}
+
+	sellable := product.Stock - held.Carts
+	if sellable < 0 {
+		sellable = 0
+	}
+
+	return &entities.StockLevel{
+		OnHand:       product.Stock + held.Orders,
+		HeldByOrders: held.Orders,
+		HeldByCarts:  held.Carts,
+		Sellable:     sellable,
+	}, nil
+}
+
+// ExpireUnpaidOrders ยกเลิกคำสั่งซื้อที่การจองหมดอายุแล้วแต่ยังไม่ชำระเงิน พร้อมคืนสต็อก
+// แต่ละคำสั่งซื้อทำใน transaction ของตัวเอง คำสั่งซื้อที่ล้มเหลวจะถูกลองใหม่ในรอบถัดไป
+func (r *inventoryRepository) ExpireUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	var orderIDs []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.StockReservation{}).
+		Distinct("order_id").
+		Where("order_id IS NOT NULL AND status = ? AND expires_at <= ?", entities.ReservationActive, now).
+		Limit(limit).
+		Pluck("order_id", &orderIDs).Error; err != nil {
+		return nil, err
+	}
+
+	var expired []uuid.UUID
+	for _, orderID := range orderIDs {
+		cancelled, err := r.expireOrder(ctx, orderID, now)
+		if err != nil {
+			log.Printf("Failed to expire order %s: %v", orderID, err)
+			continue
+		}
+		if cancelled {
+			expired = append(expired, orderID)
+		}
+	}
+
+	return expired, nil
+}
+
+func (r *inventoryRepository) expireOrder(ctx context.Context, orderID uuid.UUID, now time.Time) (bool, error) {
+	cancelled := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		// คำสั่งซื้อที่ชำระหรือยืนยันไปแล้วระหว่างรอ sweeper ถือว่าขายแล้ว
+		if order.Status != entities.OrderStatusPending || order.PaymentStatus == entities.PaymentStatusPaid {
+			return updateOrderReservations(tx, orderID, entities.ReservationCommitted)
+		}
+
+		if order.PaymentDueAt != nil && order.PaymentDueAt.After(now) {
+			return nil
+		}
+
+		if err := closeOrderPayments(tx, order, nil, paymentTimeoutNote); err != nil {
+			return err
+		}
+		if _, err := transitionOrder(tx, order, entities.OrderFieldStatus, entities.OrderStatusCancelled, nil, paymentTimeoutNote); err != nil {
+			return err
+		}
+
+		cancelled = true
+		if err := releaseCouponRedemptions(tx, orderID); err != nil {
+			return err
+		}
+		return restockOrder(tx, orderID)
+	})
+
+	return cancelled, err
+}
+
+// PurgeExpiredCartHolds ลบการจองของตะกร้าที่หมดอายุแล้ว (การจองที่หมดอายุไม่ถูกนับอยู่แล้ว จึงเป็นเพียงการเก็บกวาด)
+func (r *inventoryRepository) PurgeExpiredCartHolds(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("cart_id IS NOT NULL AND expires_at <= ?", now).
+		Delete(&models.StockReservation{})
+	return result.RowsAffected, result.Error
+}
+
+// heldByOtherCarts รวมจำนวนที่ตะกร้าอื่นจองไว้และยังไม่หมดอายุ แยกตามสินค้า
+func heldByOtherCarts(tx *gorm.DB, productIDs []uuid.UUID, cartID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ProductID uuid.UUID
+		Quantity  int
+	}
+	if err := tx.Model(&models.StockReservation{}).
+		Select("product_id, SUM(quantity) AS quantity").
+		Where("product_id IN ? AND cart_id IS NOT NULL AND cart_id <> ? AND status = ? AND expires_at > ?",
+			productIDs, cartID, entities.ReservationActive, time.Now()).
+		Group("product_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	held := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		held[row.ProductID] = row.Quantity
+	}
+	return held, nil
+}
+
+// holdCartItem จองสต็อกให้สินค้าในตะกร้าจนถึง until (แทนที่การจองเดิมของสินค้านั้น)
+func holdCartItem(tx *gorm.DB, cartID, productID uuid.UUID, quantity int, until time.Time) error {
+	if err := releaseCartHolds(tx, cartID, &productID); err != nil {
+		return err
+	}
+	if until.IsZero() || quantity <= 0 {
+		return nil
+	}
+
+	return tx.Create(&models.StockReservation{
+		ProductID: productID,
+		CartID:    &cartID,
+		Quantity:  quantity,
+		Status:    entities.ReservationActive,
+		ExpiresAt: until,
+	}).Error
+}
+
+// releaseCartHolds ลบการจองของตะกร้า (ทุกสินค้าเมื่อ productID เป็น nil)
+func releaseCartHolds(tx *gorm.DB, cartID uuid.UUID, productID *uuid.UUID) error {
+	query := tx.Unscoped().Where("cart_id = ?", cartID)
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+	return query.Delete(&models.StockReservation{}).Error
+}
+
+// addStock คืนสต็อกสินค้า qty ชิ้น และบันทึก event back-in-stock หากสินค้ากลับมามีสต็อก
+func addStock(tx *gorm.DB, productID uuid.UUID, qty int) error {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&product, "id = ?", productID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("stock", gorm.Expr("stock + ?", qty)).Error; err != nil {
+		return err
+	}
+	return recordBackInStock(tx, productID, product.Stock, product.Stock+qty)
+}
+
+// updateOrderReservations เปลี่ยนสถานะการจองที่ยัง active ของคำสั่งซื้อ
+func updateOrderReservations(tx *gorm.DB, orderID uuid.UUID, status string) error {
+	return tx.Model(&models.StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, entities.ReservationActive).
+		Update("status", status).Error
+}
@@ -3,14 +3,21 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// lowStockThreshold เกณฑ์สต็อกต่ำ (ใช้ค่าเดียวกับรายงานสินค้าใน stats)
+const lowStockThreshold = 10
+
 type orderRepository struct {
 	db *gorm.DB
 }
@@ -19,38 +26,140 @@ func NewOrderRepository(db *gorm.DB) repositories.OrderRepository {
 	return &orderRepository{db: db}
 }
 
-func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (*entities.Order, error) {
+func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest, checkout *entities.Checkout) (*entities.Order, error) {
+	pricing := checkout.Pricing
+	tax := checkout.Tax
+	paymentDueAt := checkout.PaymentDueAt
+
 	tx := r.db.WithContext(ctx).Begin()
 
-	// หาตะกร้าของผู้ใช้
+	// ล็อกตะกร้าของผู้ใช้ก่อนอ่านรายการสินค้า การสั่งซื้อจากตะกร้าเดียวกันพร้อมกันจะรอจนอีกรายการล้างตะกร้าแล้ว
+	// (ตะกร้าถูกล้างใน tx เดียวกันด้านล่าง) จึงได้ตะกร้าว่างแทนการสร้างคำสั่งซื้อซ้ำ
+	var locked models.Cart
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&locked).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	var cart models.Cart
-	if err := tx.Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+	if err := tx.Preload("CartItems.Product.Prices").Preload("CartItems.Product.Category").First(&cart, "id = ?", locked.ID).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	if entities.Currency(cart.Currency) != pricing.Currency {
+		tx.Rollback()
+		return nil, entities.ErrCartCurrencyChanged
+	}
+
 	if len(cart.CartItems) == 0 {
 		tx.Rollback()
 		return nil, errors.New("ตะกร้าสินค้าว่าง")
 	}
 
-	// คำนวณราคารวม
-	var totalPrice float64
-	for _, item := range cart.CartItems {
-		totalPrice += item.Price * float64(item.Quantity)
+	// จองสต็อกก่อนสร้างคำสั่งซื้อ หากสินค้าใดไม่พอจะยกเลิกทั้งคำสั่งซื้อ
+	stockBefore, err := reserveStock(tx, cart.ID, cart.CartItems)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// คิดราคาทุกรายการใหม่ด้วยอัตราแลกเปลี่ยนที่ตรึงไว้ตอนสั่งซื้อ แล้วคิดส่วนลดจากราคาใหม่
+	lines := make([]entities.DiscountLine, len(cart.CartItems))
+	for i := range cart.CartItems {
+		item := &cart.CartItems[i]
+		item.Price = unitPrice(pricing, &item.Product)
+		lines[i] = entities.DiscountLine{
+			ItemID:     item.ID,
+			ProductID:  item.ProductID,
+			CategoryID: item.Product.CategoryID,
+			Total:      entities.LineTotal(item.Price, item.Quantity),
+		}
+	}
+
+	discounts, couponCode, err := checkoutDiscounts(tx, userID, cart.CouponID, lines, pricing)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	lineDiscounts := discounts.ByItem()
+
+	// คิดภาษีต่อรายการจากยอดหลังหักส่วนลด แล้วคำนวณยอดรวมจากรายการย่อย
+	// เพื่อให้ยอดรวม ส่วนลด และภาษีรวมตรงกับรายการย่อยเสมอ
+	orderItems := make([]models.OrderItem, len(cart.CartItems))
+	var subtotal, taxAmount entities.Money
+	for i := range cart.CartItems {
+		item := &cart.CartItems[i]
+		lineTotal := lines[i].Total
+		lineDiscount := lineDiscounts[item.ID]
+
+		taxClassID := tax.ClassFor(item.Product.TaxClassID, item.Product.Category.TaxClassID)
+		taxRate := tax.RateFor(taxClassID)
+		lineTax := tax.LineTax(lineTotal.Sub(lineDiscount), taxRate).RoundTo(pricing.Currency)
+
+		orderItems[i] = models.OrderItem{
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			DiscountAmount: lineDiscount,
+			TaxClassID:     taxClassID,
+			TaxRate:        taxRate,
+			TaxAmount:      lineTax,
+		}
+		subtotal = subtotal.Add(lineTotal)
+		taxAmount = taxAmount.Add(lineTax)
+	}
+
+	// ค่าจัดส่งคิดจากน้ำหนักของสินค้าและยอดหลังหักส่วนลด (ไม่คิดภาษี) แล้วตรึงไว้กับคำสั่งซื้อ
+	shippingItems := make([]entities.ShippingItem, len(cart.CartItems))
+	for i, item := range cart.CartItems {
+		shippingItems[i] = entities.ShippingItem{
+			WeightGrams: item.Product.WeightGrams,
+			LengthCm:    item.Product.LengthCm,
+			WidthCm:     item.Product.WidthCm,
+			HeightCm:    item.Product.HeightCm,
+			Quantity:    item.Quantity,
+		}
+	}
+	shipping, err := checkout.Shipping.Quote(shippingItems, subtotal.Sub(discounts.Total), checkout.ShippingRegion, pricing)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	totalPrice := tax.GrandTotal(subtotal.Sub(discounts.Total), taxAmount).Add(shipping.Amount)
+
+	// ที่อยู่จากสมุดที่อยู่แทนข้อความที่ส่งมา
+	shippingAddress := req.ShippingAddress
+	if checkout.ShippingAddress != nil {
+		shippingAddress = checkout.ShippingAddress.Format()
 	}
 
 	// สร้างคำสั่งซื้อ
 	order := &models.Order{
-		UserID:          userID,
-		TotalPrice:      totalPrice,
-		Status:          "pending",
-		PaymentMethod:   req.PaymentMethod,
-		PaymentStatus:   "pending",
-		ShippingMethod:  req.ShippingMethod,
-		ShippingStatus:  "pending",
-		ShippingAddress: req.ShippingAddress,
-		Notes:           req.Notes,
+		UserID:           userID,
+		Subtotal:         subtotal,
+		DiscountAmount:   discounts.Total,
+		TaxAmount:        taxAmount,
+		TotalPrice:       totalPrice,
+		PricesIncludeTax: tax.PricesIncludeTax,
+		TaxRegion:        tax.Region,
+		CouponCode:       couponCode,
+		Currency:         string(pricing.Currency),
+		BaseCurrency:     string(pricing.BaseCurrency),
+		ExchangeRate:     pricing.ExchangeRate,
+		Status:           entities.OrderStatusPending,
+		PaymentMethod:    req.PaymentMethod,
+		PaymentStatus:    entities.PaymentStatusPending,
+		ShippingMethodID: &checkout.Shipping.ID,
+		ShippingMethod:   checkout.Shipping.Code,
+		ShippingAmount:   shipping.Amount,
+		ShippingStatus:   entities.ShippingStatusPending,
+		ShippingAddress:  shippingAddress,
+		Notes:            req.Notes,
+	}
+	if !paymentDueAt.IsZero() {
+		order.PaymentDueAt = &paymentDueAt
 	}
 
 	if err := tx.Create(order).Error; err != nil {
@@ -58,32 +167,116 @@ func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *ent
 		return nil, err
 	}
 
-	// สร้างรายการสินค้าในคำสั่งซื้อ
-	for _, cartItem := range cart.CartItems {
-		orderItem := &models.OrderItem{
-			OrderID:   order.ID,
-			ProductID: cartItem.ProductID,
-			Quantity:  cartItem.Quantity,
-			Price:     cartItem.Price,
-		}
+	if err := tx.Create(&models.OrderStatusHistory{
+		OrderID:   order.ID,
+		Field:     entities.OrderFieldStatus,
+		ToStatus:  order.Status,
+		ChangedBy: &userID,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-		if err := tx.Create(orderItem).Error; err != nil {
+	// สำเนาที่อยู่ ณ เวลาสั่งซื้อ
+	if checkout.ShippingAddress != nil {
+		if err := createOrderAddress(tx, order.ID, entities.AddressKindShipping, checkout.ShippingAddress); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if checkout.BillingAddress != nil {
+		if err := createOrderAddress(tx, order.ID, entities.AddressKindBilling, checkout.BillingAddress); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
+
+	// บันทึกการใช้คูปองโดยล็อกแถวคูปองไว้จนจบ transaction
+	if err := redeemCoupons(tx, userID, order.ID, discounts.Discounts, pricing.Currency); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// สร้างรายการสินค้าในคำสั่งซื้อ
+	var eventItems []entities.OrderEventItem
+	for i, cartItem := range cart.CartItems {
+		orderItem := &orderItems[i]
+		orderItem.OrderID = order.ID
 
-		// อัพเดทสต็อกสินค้า
-		if err := tx.Model(&models.Product{}).Where("id = ?", cartItem.ProductID).Update("stock", gorm.Expr("stock - ?", cartItem.Quantity)).Error; err != nil {
+		if err := tx.Create(orderItem).Error; err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+
+		// สต็อกที่ตัดแล้วถูกจองไว้ให้คำสั่งซื้อจนกว่าจะชำระเงินหรือหมดเวลา
+		if !paymentDueAt.IsZero() {
+			if err := tx.Create(&models.StockReservation{
+				ProductID: cartItem.ProductID,
+				OrderID:   &order.ID,
+				Quantity:  cartItem.Quantity,
+				Status:    entities.ReservationActive,
+				ExpiresAt: paymentDueAt,
+			}).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		// แจ้งเตือนเมื่อสต็อกลดลงต่ำกว่าเกณฑ์เป็นครั้งแรก
+		before := stockBefore[cartItem.ProductID]
+		after := before - cartItem.Quantity
+		stockBefore[cartItem.ProductID] = after
+		if before >= lowStockThreshold && after < lowStockThreshold {
+			if err := recordEvent(tx, entities.EventProductLowStock, "product", cartItem.ProductID, entities.ProductLowStockPayload{
+				ProductID: cartItem.ProductID,
+				Name:      cartItem.Product.Name,
+				Stock:     after,
+				Threshold: lowStockThreshold,
+			}); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		eventItems = append(eventItems, entities.OrderEventItem{
+			ProductID: cartItem.ProductID,
+			Quantity:  cartItem.Quantity,
+			Price:     cartItem.Price,
+		})
+	}
+
+	// บันทึก event ลง outbox ใน transaction เดียวกัน
+	if err := recordEvent(tx, entities.EventOrderCreated, "order", order.ID, entities.OrderCreatedPayload{
+		OrderID:       order.ID,
+		UserID:        userID,
+		Subtotal:      subtotal,
+		Discount:      discounts.Total,
+		Shipping:      shipping.Amount,
+		TaxAmount:     taxAmount,
+		TotalPrice:    totalPrice,
+		Currency:      pricing.Currency,
+		PaymentMethod: req.PaymentMethod,
+		Items:         eventItems,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	// ล้างตะกร้าสินค้า
+	// ล้างตะกร้าสินค้าและการจองของตะกร้า (ย้ายไปเป็นการจองของคำสั่งซื้อแล้ว)
+	if err := releaseCartHolds(tx, cart.ID, nil); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 	if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+	if cart.CouponID != nil {
+		if err := tx.Model(&models.Cart{}).Where("id = ?", cart.ID).Update("coupon_id", nil).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -94,7 +287,11 @@ func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *ent
 
 func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
 	var order models.Order
-	if err := r.db.WithContext(ctx).Preload("User").Preload("OrderItems.Product").Preload("Transactions").First(&order, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("User").Preload("OrderItems.Product").Preload("Transactions").Preload("Shipments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).Preload("Shipments.Items").Preload("History", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).Preload("Redemptions").Preload("Addresses").First(&order, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -145,73 +342,634 @@ func (r *orderRepository) GetAll(ctx context.Context, page, limit int) ([]*entit
 	return result, int(total), nil
 }
 
-func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("status", status).Error
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, changedBy *uuid.UUID, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		changed, err := transitionOrder(tx, order, entities.OrderFieldStatus, status, changedBy, note)
+		if err != nil || !changed {
+			return err
+		}
+
+		// ยกเลิกโดย admin ต้องปิดการชำระเงินที่ค้างอยู่และคืนสต็อกเช่นเดียวกับที่ผู้ใช้ยกเลิกเอง
+		if status == entities.OrderStatusCancelled {
+			if err := closeOrderPayments(tx, order, changedBy, note); err != nil {
+				return err
+			}
+			if err := releaseCouponRedemptions(tx, order.ID); err != nil {
+				return err
+			}
+			return restockOrder(tx, order.ID)
+		}
+
+		return nil
+	})
+}
+
+func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string, changedBy *uuid.UUID, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = transitionOrder(tx, order, entities.OrderFieldPaymentStatus, paymentStatus, changedBy, note)
+		return err
+	})
+}
+
+func (r *orderRepository) UpdateShippingStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingStatusRequest, changedBy *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := transitionOrder(tx, order, entities.OrderFieldShippingStatus, req.ShippingStatus, changedBy, req.Note); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.Carrier != "" {
+			updates["carrier"] = req.Carrier
+		}
+		if req.TrackingNumber != "" {
+			updates["tracking_number"] = req.TrackingNumber
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.Order{}).Where("id = ?", id).Updates(updates).Error
+	})
+}
+
+// CreateShipment สร้างพัสดุจากสินค้าในคำสั่งซื้อ โดยจำนวนรวมทุกพัสดุต้องไม่เกินจำนวนที่สั่ง
+func (r *orderRepository) CreateShipment(ctx context.Context, orderID uuid.UUID, req *entities.CreateShipmentRequest, changedBy *uuid.UUID) (*entities.Shipment, error) {
+	var shipment models.Shipment
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status == entities.OrderStatusCancelled {
+			return errors.New("ไม่สามารถจัดส่งคำสั่งซื้อที่ถูกยกเลิกแล้ว")
+		}
+
+		unshipped, orderItems, err := unshippedQuantities(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		var items []models.ShipmentItem
+		if len(req.Items) == 0 {
+			for _, item := range orderItems {
+				if qty := unshipped[item.ID]; qty > 0 {
+					items = append(items, models.ShipmentItem{OrderItemID: item.ID, Quantity: qty})
+				}
+			}
+		} else {
+			for _, item := range req.Items {
+				qty, ok := unshipped[item.OrderItemID]
+				if !ok {
+					return fmt.Errorf("ไม่พบรายการสินค้า %s ในคำสั่งซื้อนี้", item.OrderItemID)
+				}
+				if item.Quantity > qty {
+					return fmt.Errorf("จำนวนจัดส่งของรายการ %s เกินจำนวนที่ยังไม่ได้จัดส่ง (%d)", item.OrderItemID, qty)
+				}
+				unshipped[item.OrderItemID] -= item.Quantity
+				items = append(items, models.ShipmentItem{OrderItemID: item.OrderItemID, Quantity: item.Quantity})
+			}
+		}
+		if len(items) == 0 {
+			return errors.New("ไม่มีสินค้าที่ต้องจัดส่งแล้ว")
+		}
+
+		shipment = models.Shipment{
+			OrderID:        orderID,
+			Carrier:        req.Carrier,
+			TrackingNumber: req.TrackingNumber,
+			Status:         entities.ShipmentStatusPending,
+			Items:          items,
+		}
+		if err := tx.Create(&shipment).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.OrderStatusHistory{
+			OrderID:   orderID,
+			Field:     entities.OrderFieldShipmentStatus,
+			ToStatus:  shipment.Status,
+			ChangedBy: changedBy,
+			Note:      "shipment " + shipment.ID.String(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shipmentModelToEntity(&shipment), nil
+}
+
+// UpdateShipment เปลี่ยนสถานะ/ข้อมูลขนส่งของพัสดุ แล้วคำนวณสถานะการจัดส่งของคำสั่งซื้อใหม่
+func (r *orderRepository) UpdateShipment(ctx context.Context, orderID, shipmentID uuid.UUID, req *entities.UpdateShipmentRequest, changedBy *uuid.UUID) (*entities.Shipment, error) {
+	var shipment models.Shipment
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Preload("Items").First(&shipment, "id = ? AND order_id = ?", shipmentID, orderID).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.Carrier != "" {
+			updates["carrier"] = req.Carrier
+			shipment.Carrier = req.Carrier
+		}
+		if req.TrackingNumber != "" {
+			updates["tracking_number"] = req.TrackingNumber
+			shipment.TrackingNumber = req.TrackingNumber
+		}
+
+		statusChanged := false
+		if req.Status != "" && req.Status != shipment.Status {
+			if err := entities.ValidateOrderTransition(entities.OrderFieldShipmentStatus, shipment.Status, req.Status); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			switch req.Status {
+			case entities.ShipmentStatusShipped:
+				updates["shipped_at"] = now
+				shipment.ShippedAt = &now
+			case entities.ShipmentStatusDelivered:
+				updates["delivered_at"] = now
+				shipment.DeliveredAt = &now
+			}
+
+			if err := tx.Create(&models.OrderStatusHistory{
+				OrderID:    orderID,
+				Field:      entities.OrderFieldShipmentStatus,
+				FromStatus: shipment.Status,
+				ToStatus:   req.Status,
+				ChangedBy:  changedBy,
+				Note:       strings.TrimSpace("shipment " + shipment.ID.String() + " " + req.Note),
+			}).Error; err != nil {
+				return err
+			}
+
+			updates["status"] = req.Status
+			shipment.Status = req.Status
+			statusChanged = true
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&models.Shipment{}).Where("id = ?", shipment.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		// ข้อมูลขนส่งล่าสุดแสดงที่ระดับคำสั่งซื้อด้วย
+		if shipment.TrackingNumber != "" || shipment.Carrier != "" {
+			if err := tx.Model(&models.Order{}).Where("id = ?", orderID).Updates(map[string]interface{}{
+				"carrier":         shipment.Carrier,
+				"tracking_number": shipment.TrackingNumber,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if !statusChanged {
+			return nil
+		}
+
+		return syncShippingStatus(tx, order, changedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.shipmentModelToEntity(&shipment), nil
+}
+
+// unshippedQuantities จำนวนที่ยังไม่ได้จัดสรรเข้าพัสดุ (ไม่นับพัสดุที่ยกเลิกหรือถูกตีกลับ)
+func unshippedQuantities(tx *gorm.DB, orderID uuid.UUID) (map[uuid.UUID]int, []models.OrderItem, error) {
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Order("created_at").Find(&orderItems).Error; err != nil {
+		return nil, nil, err
+	}
+
+	allocated, err := shipmentQuantities(tx, orderID, []string{
+		entities.ShipmentStatusPending,
+		entities.ShipmentStatusShipped,
+		entities.ShipmentStatusInTransit,
+		entities.ShipmentStatusDelivered,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unshipped := make(map[uuid.UUID]int, len(orderItems))
+	for _, item := range orderItems {
+		unshipped[item.ID] = item.Quantity - allocated[item.ID]
+	}
+
+	return unshipped, orderItems, nil
 }
 
-func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error {
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("payment_status", paymentStatus).Error
+// shipmentQuantities รวมจำนวนสินค้าแต่ละรายการในพัสดุที่มีสถานะตามที่กำหนด
+func shipmentQuantities(tx *gorm.DB, orderID uuid.UUID, statuses []string) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Table("shipment_items").
+		Select("shipment_items.order_item_id, SUM(shipment_items.quantity) AS quantity").
+		Joins("JOIN shipments ON shipments.id = shipment_items.shipment_id").
+		Where("shipments.order_id = ? AND shipments.status IN ?", orderID, statuses).
+		Where("shipments.deleted_at IS NULL AND shipment_items.deleted_at IS NULL").
+		Group("shipment_items.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		result[row.OrderItemID] = row.Quantity
+	}
+	return result, nil
+}
+
+// syncShippingStatus คำนวณสถานะการจัดส่งของคำสั่งซื้อจากพัสดุทั้งหมด
+// หากสถานะที่คำนวณได้ไม่อยู่ใน state machine (เช่น admin ตั้งสถานะไปไกลกว่าแล้ว) จะข้ามไป
+func syncShippingStatus(tx *gorm.DB, order *models.Order, changedBy *uuid.UUID) error {
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", order.ID).Find(&orderItems).Error; err != nil {
+		return err
+	}
+
+	shipped, err := shipmentQuantities(tx, order.ID, []string{
+		entities.ShipmentStatusShipped,
+		entities.ShipmentStatusInTransit,
+		entities.ShipmentStatusDelivered,
+	})
+	if err != nil {
+		return err
+	}
+	delivered, err := shipmentQuantities(tx, order.ID, []string{entities.ShipmentStatusDelivered})
+	if err != nil {
+		return err
+	}
+
+	allShipped, allDelivered, anyShipped := true, true, false
+	for _, item := range orderItems {
+		if shipped[item.ID] > 0 {
+			anyShipped = true
+		}
+		if shipped[item.ID] < item.Quantity {
+			allShipped = false
+		}
+		if delivered[item.ID] < item.Quantity {
+			allDelivered = false
+		}
+	}
+
+	var target string
+	switch {
+	case allDelivered:
+		target = entities.ShippingStatusDelivered
+	case allShipped:
+		target = entities.ShippingStatusShipped
+	case anyShipped:
+		target = entities.ShippingStatusPartiallyShipped
+	default:
+		return nil
+	}
+
+	if !entities.ShippingStatusMachine.Allows(order.ShippingStatus, target) {
+		return nil
+	}
+
+	_, err = transitionOrder(tx, order, entities.OrderFieldShippingStatus, target, changedBy, "derived from shipments")
+	return err
 }
 
-func (r *orderRepository) UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus, trackingNumber string) error {
-	updates := map[string]interface{}{
-		"shipping_status": shippingStatus,
+func (r *orderRepository) shipmentModelToEntity(shipment *models.Shipment) *entities.Shipment {
+	result := &entities.Shipment{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		CreatedAt:      shipment.CreatedAt,
+		UpdatedAt:      shipment.UpdatedAt,
 	}
-	if trackingNumber != "" {
-		updates["tracking_number"] = trackingNumber
+
+	for _, item := range shipment.Items {
+		result.Items = append(result.Items, entities.ShipmentItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
 	}
 
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Updates(updates).Error
+	return result
 }
 
-func (r *orderRepository) Cancel(ctx context.Context, id uuid.UUID) error {
-	tx := r.db.WithContext(ctx).Begin()
+func (r *orderRepository) Cancel(ctx context.Context, id uuid.UUID, changedBy *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
 
-	// ดึงข้อมูลคำสั่งซื้อ
+		// ผู้ใช้ยกเลิกได้เฉพาะคำสั่งซื้อที่ยังรอดำเนินการ
+		if order.Status != entities.OrderStatusPending {
+			return &entities.InvalidTransitionError{
+				Field:   entities.OrderFieldStatus,
+				From:    order.Status,
+				To:      entities.OrderStatusCancelled,
+				Allowed: entities.OrderStatusMachine[order.Status],
+			}
+		}
+
+		if _, err := transitionOrder(tx, order, entities.OrderFieldStatus, entities.OrderStatusCancelled, changedBy, ""); err != nil {
+			return err
+		}
+
+		if err := closeOrderPayments(tx, order, changedBy, ""); err != nil {
+			return err
+		}
+		if err := releaseCouponRedemptions(tx, order.ID); err != nil {
+			return err
+		}
+		return restockOrder(tx, order.ID)
+	})
+}
+
+// reserveStock ล็อกแถวสินค้าทั้งหมดในตะกร้า (เรียงตาม ID เพื่อกัน deadlock ระหว่าง checkout ที่มาพร้อมกัน)
+// ตรวจว่าสต็อกหลังหักการจองของตะกร้าอื่นพอทุกรายการ แล้วจึงตัดสต็อกด้วยเงื่อนไข stock >= จำนวนที่ตัด
+// คืนค่าสต็อกก่อนตัดของแต่ละสินค้า หรือ InsufficientStockError พร้อมรายการที่ไม่พอ
+func reserveStock(tx *gorm.DB, cartID uuid.UUID, cartItems []models.CartItem) (map[uuid.UUID]int, error) {
+	requested := make(map[uuid.UUID]int)
+	var productIDs []uuid.UUID
+	for _, item := range cartItems {
+		if _, ok := requested[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		requested[item.ProductID] += item.Quantity
+	}
+
+	var products []models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", productIDs).
+		Order("id").
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	stock := make(map[uuid.UUID]int, len(products))
+	names := make(map[uuid.UUID]string, len(products))
+	for _, product := range products {
+		stock[product.ID] = product.Stock
+		names[product.ID] = product.Name
+	}
+
+	held, err := heldByOtherCarts(tx, productIDs, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	var shortages []entities.InsufficientStockItem
+	for _, productID := range productIDs {
+		// สินค้าที่ถูกลบไปแล้วหลังจากใส่ตะกร้าจะไม่มีใน stock (ถือว่าเหลือ 0)
+		available := stock[productID] - held[productID]
+		if available < 0 {
+			available = 0
+		}
+		if requested[productID] > available {
+			shortages = append(shortages, entities.InsufficientStockItem{
+				ProductID: productID,
+				Name:      names[productID],
+				Requested: requested[productID],
+				Available: available,
+			})
+		}
+	}
+	if len(shortages) > 0 {
+		return nil, &entities.InsufficientStockError{Items: shortages}
+	}
+
+	for _, productID := range productIDs {
+		result := tx.Model(&models.Product{}).
+			Where("id = ? AND stock >= ?", productID, requested[productID]).
+			Update("stock", gorm.Expr("stock - ?", requested[productID]))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, &entities.InsufficientStockError{Items: []entities.InsufficientStockItem{{
+				ProductID: productID,
+				Name:      names[productID],
+				Requested: requested[productID],
+				Available: stock[productID],
+			}}}
+		}
+	}
+
+	return stock, nil
+}
+
+// lockOrder อ่านคำสั่งซื้อพร้อมล็อกแถว เพื่อให้การตรวจ state machine กับการเขียนเป็นหนึ่งเดียวกัน
+func lockOrder(tx *gorm.DB, id uuid.UUID) (*models.Order, error) {
 	var order models.Order
-	if err := tx.Preload("OrderItems").First(&order, "id = ?", id).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// closeOrderPayments ปิดการชำระเงินที่ค้างอยู่ของคำสั่งซื้อที่ถูกยกเลิกใน tx เดียวกับการยกเลิก
+// เพื่อไม่ให้ callback ที่มาช้าเปลี่ยนคำสั่งซื้อที่ยกเลิกและคืนสต็อกแล้วเป็นชำระแล้ว
+// คำสั่งซื้อที่ชำระแล้วคงสถานะการชำระเงินไว้ (คืนเงินผ่าน RefundPayment แยกต่างหาก)
+func closeOrderPayments(tx *gorm.DB, order *models.Order, changedBy *uuid.UUID, note string) error {
+	if err := tx.Model(&models.Transaction{}).
+		Where("order_id = ? AND status = ?", order.ID, "pending").
+		Update("status", "cancelled").Error; err != nil {
 		return err
 	}
 
-	// ตรวจสอบสถานะ
-	if order.Status != "pending" {
-		tx.Rollback()
-		return errors.New("ไม่สามารถยกเลิกคำสั่งซื้อนี้ได้")
+	if order.PaymentStatus != entities.PaymentStatusPending && order.PaymentStatus != entities.PaymentStatusFailed {
+		return nil
 	}
+	_, err := transitionOrder(tx, order, entities.OrderFieldPaymentStatus, entities.PaymentStatusCancelled, changedBy, note)
+	return err
+}
 
-	// คืนสต็อกสินค้า
-	for _, item := range order.OrderItems {
-		if err := tx.Model(&models.Product{}).Where("id = ?", item.ProductID).Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
-			tx.Rollback()
-			return err
+// transitionOrder ตรวจสอบ state machine แล้วบันทึกสถานะใหม่พร้อมประวัติใน tx เดียวกัน
+// คืนค่า false เมื่อสถานะเดิมกับสถานะใหม่เหมือนกัน
+func transitionOrder(tx *gorm.DB, order *models.Order, field, to string, changedBy *uuid.UUID, note string) (bool, error) {
+	return applyOrderTransition(tx, order, field, to, changedBy, note, entities.ValidateOrderTransition)
+}
+
+// retryOrderPayment เปิดการชำระเงินที่ล้มเหลวกลับเป็น pending ผ่าน PaymentRetryMachine
+// สถานะอื่นไม่เปลี่ยนแปลง ผู้เรียกต้องล็อกคำสั่งซื้อไว้แล้ว
+func retryOrderPayment(tx *gorm.DB, order *models.Order, changedBy *uuid.UUID, note string) error {
+	if order.PaymentStatus != entities.PaymentStatusFailed {
+		return nil
+	}
+	_, err := applyOrderTransition(tx, order, entities.OrderFieldPaymentStatus, entities.PaymentStatusPending, changedBy, note,
+		func(field, from, to string) error { return entities.ValidatePaymentRetry(from, to) })
+	return err
+}
+
+// applyOrderTransition บันทึกสถานะใหม่ ประวัติ การจองสต็อก และ event หลังผ่าน validate
+func applyOrderTransition(tx *gorm.DB, order *models.Order, field, to string, changedBy *uuid.UUID, note string, validate func(field, from, to string) error) (bool, error) {
+	var current *string
+	switch field {
+	case entities.OrderFieldStatus:
+		current = &order.Status
+	case entities.OrderFieldPaymentStatus:
+		current = &order.PaymentStatus
+	case entities.OrderFieldShippingStatus:
+		current = &order.ShippingStatus
+	default:
+		return false, fmt.Errorf("unknown order status field %s", field)
+	}
+
+	from := *current
+	if err := validate(field, from, to); err != nil {
+		return false, err
+	}
+	if from == to {
+		return false, nil
+	}
+
+	updates := map[string]interface{}{field: to}
+	if field == entities.OrderFieldShippingStatus {
+		now := time.Now()
+		if (to == entities.ShippingStatusShipped || to == entities.ShippingStatusPartiallyShipped) && order.ShippedAt == nil {
+			updates["shipped_at"] = now
+			order.ShippedAt = &now
+		}
+		if to == entities.ShippingStatusDelivered && order.DeliveredAt == nil {
+			updates["delivered_at"] = now
+			order.DeliveredAt = &now
 		}
 	}
 
-	// อัพเดทสถานะ
-	if err := tx.Model(&order).Update("status", "cancelled").Error; err != nil {
-		tx.Rollback()
+	if err := tx.Model(&models.Order{}).Where("id = ?", order.ID).Updates(updates).Error; err != nil {
+		return false, err
+	}
+	*current = to
+
+	if err := tx.Create(&models.OrderStatusHistory{
+		OrderID:    order.ID,
+		Field:      field,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+		Note:       note,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	// ชำระเงินหรือยืนยันแล้วถือว่าขายขาด ยกเลิกแล้วสต็อกจะถูกคืนโดยผู้เรียก
+	switch {
+	case field == entities.OrderFieldPaymentStatus && to == entities.PaymentStatusPaid,
+		field == entities.OrderFieldStatus && to == entities.OrderStatusConfirmed:
+		if err := updateOrderReservations(tx, order.ID, entities.ReservationCommitted); err != nil {
+			return false, err
+		}
+	case field == entities.OrderFieldStatus && to == entities.OrderStatusCancelled:
+		if err := updateOrderReservations(tx, order.ID, entities.ReservationReleased); err != nil {
+			return false, err
+		}
+	}
+
+	eventType := ""
+	switch field {
+	case entities.OrderFieldStatus:
+		eventType = entities.EventOrderStatusChanged
+	case entities.OrderFieldShippingStatus:
+		eventType = entities.EventOrderShippingStatusChanged
+	}
+	if eventType != "" {
+		if err := recordEvent(tx, eventType, "order", order.ID, entities.OrderStatusChangedPayload{
+			OrderID: order.ID,
+			UserID:  order.UserID,
+			From:    from,
+			To:      to,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// restockOrder คืนสต็อกสินค้าส่วนที่ยังไม่เคยคืน
+func restockOrder(tx *gorm.DB, orderID uuid.UUID) error {
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
 		return err
 	}
 
-	return tx.Commit().Error
+	for _, item := range items {
+		qty := item.Quantity - item.RestockedQuantity
+		if qty <= 0 {
+			continue
+		}
+		if err := addStock(tx, item.ProductID, qty); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.OrderItem{}).Where("id = ?", item.ID).Update("restocked_quantity", item.Quantity).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 	orderEntity := &entities.Order{
-		ID:              order.ID,
-		UserID:          order.UserID,
-		TotalPrice:      order.TotalPrice,
-		Status:          order.Status,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		ShippingAddress: order.ShippingAddress,
-		TrackingNumber:  order.TrackingNumber,
-		Notes:           order.Notes,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
+		ID:               order.ID,
+		UserID:           order.UserID,
+		Subtotal:         order.Subtotal,
+		DiscountAmount:   order.DiscountAmount,
+		TaxAmount:        order.TaxAmount,
+		TotalPrice:       order.TotalPrice,
+		PricesIncludeTax: order.PricesIncludeTax,
+		TaxRegion:        order.TaxRegion,
+		CouponCode:       order.CouponCode,
+		Currency:         entities.Currency(order.Currency),
+		BaseCurrency:     entities.Currency(order.BaseCurrency),
+		ExchangeRate:     order.ExchangeRate,
+		Status:           order.Status,
+		PaymentMethod:    order.PaymentMethod,
+		PaymentStatus:    order.PaymentStatus,
+		ShippingMethodID: order.ShippingMethodID,
+		ShippingMethod:   order.ShippingMethod,
+		ShippingAmount:   order.ShippingAmount,
+		ShippingStatus:   order.ShippingStatus,
+		ShippingAddress:  order.ShippingAddress,
+		TrackingNumber:   order.TrackingNumber,
+		Carrier:          order.Carrier,
+		ShippedAt:        order.ShippedAt,
+		DeliveredAt:      order.DeliveredAt,
+		PaymentDueAt:     order.PaymentDueAt,
+		Notes:            order.Notes,
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
 	}
 
 	if order.User.ID != uuid.Nil {
@@ -223,6 +981,7 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 			Phone:     order.User.Phone,
 			Address:   order.User.Address,
 			Active:    order.User.Active,
+			Locale:    order.User.Locale,
 			RoleID:    order.User.RoleID,
 			CreatedAt: order.User.CreatedAt,
 			UpdatedAt: order.User.UpdatedAt,
@@ -231,13 +990,17 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 
 	for _, item := range order.OrderItems {
 		orderItem := entities.OrderItem{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:             item.ID,
+			OrderID:        item.OrderID,
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			DiscountAmount: item.DiscountAmount,
+			TaxClassID:     item.TaxClassID,
+			TaxRate:        item.TaxRate,
+			TaxAmount:      item.TaxAmount,
+			CreatedAt:      item.CreatedAt,
+			UpdatedAt:      item.UpdatedAt,
 		}
 
 		if item.Product.ID != uuid.Nil {
@@ -246,9 +1009,15 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 				Name:        item.Product.Name,
 				Description: item.Product.Description,
 				Price:       item.Product.Price,
+				Currency:    entities.Currency(item.Product.Currency),
 				Stock:       item.Product.Stock,
 				Image:       item.Product.Image,
 				CategoryID:  item.Product.CategoryID,
+				TaxClassID:  item.Product.TaxClassID,
+				WeightGrams: item.Product.WeightGrams,
+				LengthCm:    item.Product.LengthCm,
+				WidthCm:     item.Product.WidthCm,
+				HeightCm:    item.Product.HeightCm,
 				CreatedAt:   item.Product.CreatedAt,
 				UpdatedAt:   item.Product.UpdatedAt,
 			}
@@ -257,20 +1026,47 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 		orderEntity.OrderItems = append(orderEntity.OrderItems, orderItem)
 	}
 
+	for _, redemption := range order.Redemptions {
+		orderEntity.Discounts = append(orderEntity.Discounts, couponRedemptionModelToEntity(&redemption))
+	}
+
+	for _, address := range order.Addresses {
+		orderEntity.Addresses = append(orderEntity.Addresses, orderAddressModelToEntity(&address))
+	}
+
 	for _, transaction := range order.Transactions {
 		transactionEntity := entities.Transaction{
 			ID:            transaction.ID,
 			OrderID:       transaction.OrderID,
 			Amount:        transaction.Amount,
+			Currency:      entities.Currency(transaction.Currency),
 			PaymentMethod: transaction.PaymentMethod,
 			Status:        transaction.Status,
 			TransactionID: transaction.TransactionID,
 			PaymentData:   transaction.PaymentData,
+			Provider:      transaction.Provider,
+			ProviderRef:   transaction.ProviderRef,
 			CreatedAt:     transaction.CreatedAt,
 			UpdatedAt:     transaction.UpdatedAt,
 		}
 		orderEntity.Transactions = append(orderEntity.Transactions, transactionEntity)
 	}
 
+	for _, shipment := range order.Shipments {
+		orderEntity.Shipments = append(orderEntity.Shipments, *r.shipmentModelToEntity(&shipment))
+	}
+
+	for _, history := range order.History {
+		orderEntity.History = append(orderEntity.History, entities.OrderStatusHistory{
+			ID:        history.ID,
+			Field:     history.Field,
+			From:      history.FromStatus,
+			To:        history.ToStatus,
+			ChangedBy: history.ChangedBy,
+			Note:      history.Note,
+			CreatedAt: history.CreatedAt,
+		})
+	}
+
 	return orderEntity
-}
\ No newline at end of file
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/config"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase เชื่อมต่อ Postgres จาก TEST_DATABASE_DSN แล้ว migrate ลง schema ใหม่ของการทดสอบนี้
+// schema ถูกลบพร้อมข้อมูลทั้งหมดเมื่อการทดสอบจบ จึงไม่กระทบข้อมูลอื่นในฐานข้อมูล
+// ข้ามการทดสอบหากไม่ได้กำหนด TEST_DATABASE_DSN
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	gormConfig := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema)), gormConfig)
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	// ปิด connection ของ schema ก่อน DROP SCHEMA (Cleanup ทำงานย้อนลำดับ)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := config.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Up(0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+// withSearchPath เพิ่ม search_path ให้ DSN ได้ทั้งรูปแบบ URL และ key=value
+func withSearchPath(dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&search_path=" + schema
+	}
+	return dsn + "?search_path=" + schema
+}
+
+// TestOrderRepositoryCreateConcurrentStock ผู้ใช้ N คนสั่งสินค้าชิ้นเดียวกันพร้อมกันโดยมีสต็อกน้อยกว่า N
+// สต็อกต้องไม่ติดลบ คำสั่งซื้อสำเร็จเท่ากับสต็อกพอดี และที่เหลือได้ InsufficientStockError
+func TestOrderRepositoryCreateConcurrentStock(t *testing.T) {
+	const (
+		buyers = 20
+		stock  = 5
+	)
+
+	db := openTestDatabase(t)
+	suffix := uuid.NewString()
+
+	role := models.Role{Name: "stock-test-" + suffix}
+	category := models.Category{Name: "stock-test-" + suffix}
+	if err := db.Create(&role).Error; err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	product := models.Product{
+		Name:       "stock-test-" + suffix,
+		Price:      entities.MoneyFromMajor(100),
+		Currency:   string(entities.DefaultCurrency),
+		Stock:      stock,
+		CategoryID: category.ID,
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	method := models.ShippingMethod{Code: "stock-test-" + suffix[:8], Name: "Stock test", Active: true}
+	if err := db.Create(&method).Error; err != nil {
+		t.Fatalf("create shipping method: %v", err)
+	}
+	shipping := &entities.ShippingMethod{
+		ID:     method.ID,
+		Code:   method.Code,
+		Name:   method.Name,
+		Active: true,
+		Rates:  []entities.ShippingRate{{ShippingMethodID: method.ID, Zone: "*", Type: entities.ShippingRateFlat}},
+	}
+
+	// ผู้ซื้อแต่ละคนมีตะกร้าของตัวเองที่มีสินค้าชิ้นนี้ 1 ชิ้น (ไม่จองสต็อกไว้ในตะกร้า)
+	userIDs := make([]uuid.UUID, buyers)
+	for i := range userIDs {
+		user := models.User{
+			Email:     uuid.NewString() + "@stock-test.local",
+			Password:  "x",
+			FirstName: "Stock",
+			LastName:  "Test",
+			RoleID:    role.ID,
+		}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		cart := models.Cart{UserID: user.ID, Currency: string(entities.DefaultCurrency)}
+		if err := db.Create(&cart).Error; err != nil {
+			t.Fatalf("create cart: %v", err)
+		}
+		if err := db.Create(&models.CartItem{CartID: cart.ID, ProductID: product.ID, Quantity: 1, Price: product.Price}).Error; err != nil {
+			t.Fatalf("create cart item: %v", err)
+		}
+		userIDs[i] = user.ID
+	}
+
+	repo := NewOrderRepository(db)
+	errs := make([]error, buyers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i int, userID uuid.UUID) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = repo.Create(context.Background(), userID, &entities.CreateOrderRequest{
+				PaymentMethod:   "cod",
+				ShippingMethod:  shipping.Code,
+				ShippingAddress: "Bangkok",
+			}, &entities.Checkout{
+				Pricing:        entities.BasePriceContext(),
+				Shipping:       shipping,
+				ShippingRegion: "TH",
+			})
+		}(i, userID)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		var shortage *entities.InsufficientStockError
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.As(err, &shortage):
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != stock {
+		t.Errorf("succeeded orders = %d, want %d", succeeded, stock)
+	}
+
+	var remaining models.Product
+	if err := db.First(&remaining, "id = ?", product.ID).Error; err != nil {
+		t.Fatalf("reload product: %v", err)
+	}
+	if remaining.Stock < 0 {
+		t.Fatalf("stock went negative: %d", remaining.Stock)
+	}
+	if remaining.Stock != stock-succeeded {
+		t.Errorf("remaining stock = %d, want %d", remaining.Stock, stock-succeeded)
+	}
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Publish บันทึก event ลง outbox ในการทำงานแยก (ไม่ผูกกับ transaction อื่น)
+func (r *outboxRepository) Publish(ctx context.Context, events ...entities.DomainEvent) error {
+	return appendOutbox(r.db.WithContext(ctx), events...)
+}
+
+// ClaimPending จอง event ที่ถึงเวลาส่งไว้ชั่วคราว เพื่อให้หลาย instance ทำงานพร้อมกันได้โดยไม่ส่งซ้ำ
+func (r *outboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*entities.OutboxMessage, error) {
+	now := time.Now()
+	lockedUntil := now.Add(lease)
+
+	var rows []models.OutboxEvent
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = 'pending' AND deleted_at IS NULL
+			  AND next_attempt_at <= ?
+			  AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, lockedUntil, now, now, now, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.OutboxMessage
+	for _, row := range rows {
+		result = append(result, r.modelToEntity(&row))
+	}
+
+	return result, nil
+}
+
+func (r *outboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        "dispatched",
+		"attempts":      gorm.Expr("attempts + 1"),
+		"dispatched_at": now,
+		"locked_until":  nil,
+		"last_error":    "",
+	}).Error
+}
+
+// MarkFailed บันทึกความผิดพลาดและ sink ที่ส่งสำเร็จแล้ว หาก nextAttemptAt เป็น nil จะถือว่าเลิกส่งแล้ว
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt *time.Time, deliveredSinks []string) error {
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      lastError,
+		"locked_until":    nil,
+		"delivered_sinks": strings.Join(deliveredSinks, ","),
+	}
+	if nextAttemptAt == nil {
+		updates["status"] = "failed"
+	} else {
+		updates["next_attempt_at"] = *nextAttemptAt
+	}
+
+	return r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *outboxRepository) modelToEntity(row *models.OutboxEvent) *entities.OutboxMessage {
+	var deliveredSinks []string
+	if row.DeliveredSinks != "" {
+		deliveredSinks = strings.Split(row.DeliveredSinks, ",")
+	}
+
+	return &entities.OutboxMessage{
+		Event: entities.DomainEvent{
+			ID:            row.ID,
+			Type:          row.EventType,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			Payload:       []byte(row.Payload),
+			OccurredAt:    row.OccurredAt,
+		},
+		Status:         row.Status,
+		Attempts:       row.Attempts,
+		LastError:      row.LastError,
+		NextAttemptAt:  row.NextAttemptAt,
+		DeliveredSinks: deliveredSinks,
+	}
+}
+
+// appendOutbox เขียน event ลง outbox โดยใช้ tx ที่ส่งเข้ามา
+// repository อื่นเรียกใช้ภายใน transaction ของตัวเอง เพื่อให้ event ถูก commit พร้อมข้อมูล
+func appendOutbox(tx *gorm.DB, events ...entities.DomainEvent) error {
+	for _, event := range events {
+		row := &models.OutboxEvent{
+			EventType:     event.Type,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			Payload:       string(event.Payload),
+			OccurredAt:    event.OccurredAt,
+			Status:        "pending",
+			NextAttemptAt: event.OccurredAt,
+		}
+		row.ID = event.ID
+
+		if err := tx.Create(row).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordEvent สร้าง event และเขียนลง outbox ใน tx เดียวกัน
+func recordEvent(tx *gorm.DB, eventType, aggregateType string, aggregateID uuid.UUID, payload interface{}) error {
+	event, err := entities.NewDomainEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+
+	return appendOutbox(tx, event)
+}
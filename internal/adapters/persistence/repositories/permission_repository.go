@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+)
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) repositories.PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) Create(ctx context.Context, permission *entities.Permission) error {
+	permissionModel := &models.Permission{
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+
+	if err := r.db.WithContext(ctx).Create(permissionModel).Error; err != nil {
+		return err
+	}
+
+	permission.ID = permissionModel.ID
+	permission.CreatedAt = permissionModel.CreatedAt
+	permission.UpdatedAt = permissionModel.UpdatedAt
+	return nil
+}
+
+func (r *permissionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Permission, error) {
+	var permission models.Permission
+	if err := r.db.WithContext(ctx).First(&permission, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return permissionModelToEntity(&permission), nil
+}
+
+func (r *permissionRepository) GetByName(ctx context.Context, name string) (*entities.Permission, error) {
+	var permission models.Permission
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&permission).Error; err != nil {
+		return nil, err
+	}
+
+	return permissionModelToEntity(&permission), nil
+}
+
+func (r *permissionRepository) GetByNames(ctx context.Context, names []string) ([]*entities.Permission, error) {
+	var permissions []models.Permission
+	if len(names) > 0 {
+		if err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var result []*entities.Permission
+	for _, permission := range permissions {
+		result = append(result, permissionModelToEntity(&permission))
+	}
+
+	return result, nil
+}
+
+func (r *permissionRepository) GetAll(ctx context.Context) ([]*entities.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Permission
+	for _, permission := range permissions {
+		result = append(result, permissionModelToEntity(&permission))
+	}
+
+	return result, nil
+}
+
+func (r *permissionRepository) Update(ctx context.Context, id uuid.UUID, permission *entities.Permission) error {
+	updates := map[string]interface{}{
+		"description": permission.Description,
+	}
+	return r.db.WithContext(ctx).Model(&models.Permission{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete ลบสิทธิ์และถอดออกจากทุกบทบาท
+func (r *permissionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE permission_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Permission{}, "id = ?", id).Error
+	})
+}
+
+func permissionModelToEntity(permission *models.Permission) *entities.Permission {
+	return &entities.Permission{
+		ID:          permission.ID,
+		Name:        permission.Name,
+		Description: permission.Description,
+		CreatedAt:   permission.CreatedAt,
+		UpdatedAt:   permission.UpdatedAt,
+	}
+}
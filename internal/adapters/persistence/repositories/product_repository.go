@@ -9,6 +9,7 @@ import (
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -24,9 +25,15 @@ func (r *productRepository) Create(ctx context.Context, req *entities.CreateProd
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
+		Currency:    string(entities.DefaultCurrency),
 		Stock:       req.Stock,
 		Image:       req.Image,
 		CategoryID:  req.CategoryID,
+		TaxClassID:  req.TaxClassID,
+		WeightGrams: req.WeightGrams,
+		LengthCm:    req.LengthCm,
+		WidthCm:     req.WidthCm,
+		HeightCm:    req.HeightCm,
 	}
 
 	tx := r.db.WithContext(ctx).Begin()
@@ -48,6 +55,11 @@ func (r *productRepository) Create(ctx context.Context, req *entities.CreateProd
 		}
 	}
 
+	if err := replaceProductPrices(tx, productModel.ID, req.Prices); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -57,7 +69,7 @@ func (r *productRepository) Create(ctx context.Context, req *entities.CreateProd
 
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var productModel models.Product
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").First(&productModel, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Preload("Prices").First(&productModel, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -74,7 +86,7 @@ func (r *productRepository) GetAll(ctx context.Context, page, limit int) ([]*ent
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Preload("Prices").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -96,7 +108,7 @@ func (r *productRepository) GetByCategory(ctx context.Context, categoryID uuid.U
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Where("category_id = ?", categoryID).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Preload("Prices").Where("category_id = ?", categoryID).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -147,7 +159,7 @@ func (r *productRepository) Search(ctx context.Context, req *entities.ProductSea
 
 	offset := (page - 1) * limit
 
-	if err := query.Preload("Category").Preload("Images").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := query.Preload("Category").Preload("Images").Preload("Prices").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -180,14 +192,42 @@ func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entit
 	if req.CategoryID != uuid.Nil {
 		updates["category_id"] = req.CategoryID
 	}
+	if req.TaxClassID != nil {
+		updates["tax_class_id"] = taxClassColumn(req.TaxClassID)
+	}
+	if req.WeightGrams != nil {
+		updates["weight_grams"] = *req.WeightGrams
+	}
+	if req.LengthCm != nil {
+		updates["length_cm"] = *req.LengthCm
+	}
+	if req.WidthCm != nil {
+		updates["width_cm"] = *req.WidthCm
+	}
+	if req.HeightCm != nil {
+		updates["height_cm"] = *req.HeightCm
+	}
 
 	tx := r.db.WithContext(ctx).Begin()
 
+	var before models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&before, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Model(&models.Product{}).Where("id = ?", id).Updates(updates).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if stock, ok := updates["stock"].(int); ok {
+		if err := recordBackInStock(tx, id, before.Stock, stock); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// อัพเดทรูปภาพเพิ่มเติม (ลบรูปเก่าและเพิ่มรูปใหม่)
 	if len(req.Images) > 0 {
 		// ลบรูปเก่า
@@ -209,15 +249,74 @@ func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entit
 		}
 	}
 
+	if req.Prices != nil {
+		if err := replaceProductPrices(tx, id, *req.Prices); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	return tx.Commit().Error
 }
 
+// replaceProductPrices แทนที่รายการราคาแยกตามสกุลเงินของสินค้าทั้งหมด
+func replaceProductPrices(tx *gorm.DB, productID uuid.UUID, prices []entities.ProductPriceRequest) error {
+	if err := tx.Where("product_id = ?", productID).Delete(&models.ProductPrice{}).Error; err != nil {
+		return err
+	}
+
+	for _, price := range prices {
+		if err := tx.Create(&models.ProductPrice{
+			ProductID: productID,
+			Currency:  string(price.Currency),
+			Price:     price.Price,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unitPrice ราคาต่อหน่วยของสินค้าตาม pricing (ต้อง preload Prices ไว้แล้ว)
+func unitPrice(pricing entities.PriceContext, product *models.Product) entities.Money {
+	return pricing.PriceOf(&entities.Product{
+		Price:    product.Price,
+		Currency: entities.Currency(product.Currency),
+		Prices:   productPricesToEntity(product.Prices),
+	})
+}
+
+func productPricesToEntity(prices []models.ProductPrice) []entities.ProductPrice {
+	var result []entities.ProductPrice
+	for _, price := range prices {
+		result = append(result, entities.ProductPrice{
+			ID:        price.ID,
+			ProductID: price.ProductID,
+			Currency:  entities.Currency(price.Currency),
+			Price:     price.Price,
+			CreatedAt: price.CreatedAt,
+			UpdatedAt: price.UpdatedAt,
+		})
+	}
+	return result
+}
+
 func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Product{}, "id = ?", id).Error
 }
 
 func (r *productRepository) UpdateStock(ctx context.Context, id uuid.UUID, stock int) error {
-	return r.db.WithContext(ctx).Model(&models.Product{}).Where("id = ?", id).Update("stock", stock).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&before, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Product{}).Where("id = ?", id).Update("stock", stock).Error; err != nil {
+			return err
+		}
+		return recordBackInStock(tx, id, before.Stock, stock)
+	})
 }
 
 func (r *productRepository) GetLowStockProducts(ctx context.Context, threshold int) ([]*entities.Product, error) {
@@ -241,9 +340,16 @@ func (r *productRepository) modelToEntity(productModel *models.Product) *entitie
 		Name:        productModel.Name,
 		Description: productModel.Description,
 		Price:       productModel.Price,
+		Currency:    entities.Currency(productModel.Currency),
+		Prices:      productPricesToEntity(productModel.Prices),
 		Stock:       productModel.Stock,
 		Image:       productModel.Image,
 		CategoryID:  productModel.CategoryID,
+		TaxClassID:  productModel.TaxClassID,
+		WeightGrams: productModel.WeightGrams,
+		LengthCm:    productModel.LengthCm,
+		WidthCm:     productModel.WidthCm,
+		HeightCm:    productModel.HeightCm,
 		CreatedAt:   productModel.CreatedAt,
 		UpdatedAt:   productModel.UpdatedAt,
 	}
@@ -254,6 +360,7 @@ func (r *productRepository) modelToEntity(productModel *models.Product) *entitie
 			Name:        productModel.Category.Name,
 			Description: productModel.Category.Description,
 			Image:       productModel.Category.Image,
+			TaxClassID:  productModel.Category.TaxClassID,
 			CreatedAt:   productModel.Category.CreatedAt,
 			UpdatedAt:   productModel.Category.UpdatedAt,
 		}
@@ -270,4 +377,4 @@ func (r *productRepository) modelToEntity(productModel *models.Product) *entitie
 	}
 
 	return product
-}
\ No newline at end of file
+}
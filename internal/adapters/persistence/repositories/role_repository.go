@@ -24,7 +24,14 @@ func (r *roleRepository) Create(ctx context.Context, role *entities.Role) error
 		Description: role.Description,
 	}
 
-	return r.db.WithContext(ctx).Create(roleModel).Error
+	if err := r.db.WithContext(ctx).Create(roleModel).Error; err != nil {
+		return err
+	}
+
+	role.ID = roleModel.ID
+	role.CreatedAt = roleModel.CreatedAt
+	role.UpdatedAt = roleModel.UpdatedAt
+	return nil
 }
 
 func (r *roleRepository) GetByName(ctx context.Context, name string) (*entities.Role, error) {
@@ -38,7 +45,7 @@ func (r *roleRepository) GetByName(ctx context.Context, name string) (*entities.
 
 func (r *roleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
 	var role models.Role
-	if err := r.db.WithContext(ctx).First(&role, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Permissions").First(&role, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -67,16 +74,84 @@ func (r *roleRepository) Update(ctx context.Context, id uuid.UUID, role *entitie
 	return r.db.WithContext(ctx).Model(&models.Role{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// Delete ลบบทบาทพร้อมความสัมพันธ์กับสิทธิ์
 func (r *roleRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Role{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, "id = ?", id).Error
+	})
+}
+
+// SetPermissions แทนที่สิทธิ์ทั้งหมดของบทบาทด้วยรายการใหม่
+func (r *roleRepository) SetPermissions(ctx context.Context, id uuid.UUID, permissionIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role models.Role
+		if err := tx.First(&role, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		var permissions []models.Permission
+		if len(permissionIDs) > 0 {
+			if err := tx.Where("id IN ?", permissionIDs).Find(&permissions).Error; err != nil {
+				return err
+			}
+		}
+
+		// ไม่ต้อง upsert ตาราง permissions ซ้ำ แก้เฉพาะตาราง role_permissions
+		return tx.Omit("Permissions.*").Model(&role).Association("Permissions").Replace(permissions)
+	})
+}
+
+// GetPermissionNames ดึงชื่อสิทธิ์ทั้งหมดของบทบาทตามชื่อบทบาท
+func (r *roleRepository) GetPermissionNames(ctx context.Context, roleName string) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).
+		Table("permissions").
+		Select("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ? AND roles.deleted_at IS NULL AND permissions.deleted_at IS NULL", roleName).
+		Pluck("permissions.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (r *roleRepository) CountUsers(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("role_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetUserIDs รายชื่อผู้ใช้ทั้งหมดในบทบาท
+func (r *roleRepository) GetUserIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("role_id = ?", id).Pluck("id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
 }
 
 func (r *roleRepository) modelToEntity(role *models.Role) *entities.Role {
-	return &entities.Role{
+	roleEntity := &entities.Role{
 		ID:          role.ID,
 		Name:        role.Name,
 		Description: role.Description,
 		CreatedAt:   role.CreatedAt,
 		UpdatedAt:   role.UpdatedAt,
 	}
-}
\ No newline at end of file
+
+	for _, permission := range role.Permissions {
+		roleEntity.Permissions = append(roleEntity.Permissions, *permissionModelToEntity(&permission))
+	}
+
+	return roleEntity
+}
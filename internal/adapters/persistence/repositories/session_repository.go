@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ความยาวสูงสุดของคอลัมน์ user_agent
+const maxUserAgentLength = 255
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) repositories.SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, userID uuid.UUID, tokenHash string, client entities.ClientInfo, expiresAt time.Time) (*entities.Session, error) {
+	sessionModel := models.UserSession{
+		UserID:           userID,
+		RefreshTokenHash: tokenHash,
+		UserAgent:        truncateUserAgent(client.UserAgent),
+		IPAddress:        client.IPAddress,
+		LastUsedAt:       time.Now(),
+		ExpiresAt:        expiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&sessionModel).Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(&sessionModel), nil
+}
+
+// Rotate ล็อก session ของ token ปัจจุบันก่อนหมุน การรีเฟรชพร้อมกันด้วย token เดียวกันจึงสำเร็จได้เพียงครั้งเดียว
+// ครั้งที่เหลือจะพบ token ในรายการที่ถูกหมุนแล้วและยกเลิก session
+func (r *sessionRepository) Rotate(ctx context.Context, oldHash, newHash string, client entities.ClientInfo, expiresAt time.Time) (*entities.Session, error) {
+	var sessionModel models.UserSession
+	var reusedSessionID uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?", oldHash, time.Now()).
+			First(&sessionModel).Error
+		if err == nil && !utils.TokenHashEqual(sessionModel.RefreshTokenHash, oldHash) {
+			err = gorm.ErrRecordNotFound
+		}
+		if err == gorm.ErrRecordNotFound {
+			// token เก่าที่ถูกหมุนไปแล้วถูกนำกลับมาใช้: ยกเลิกทั้ง session (commit การยกเลิกก่อนคืนข้อผิดพลาด)
+			var rotated models.SessionRotatedToken
+			if err := tx.First(&rotated, "token_hash = ?", oldHash).Error; err != nil {
+				return err
+			}
+			reusedSessionID = rotated.SessionID
+			return tx.Model(&models.UserSession{}).
+				Where("id = ? AND revoked_at IS NULL", rotated.SessionID).
+				Update("revoked_at", time.Now()).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Create(&models.SessionRotatedToken{
+			TokenHash: oldHash,
+			SessionID: sessionModel.ID,
+			RotatedAt: now,
+		}).Error; err != nil {
+			return err
+		}
+
+		sessionModel.RefreshTokenHash = newHash
+		sessionModel.UserAgent = truncateUserAgent(client.UserAgent)
+		sessionModel.IPAddress = client.IPAddress
+		sessionModel.LastUsedAt = now
+		sessionModel.ExpiresAt = expiresAt
+		return tx.Model(&sessionModel).Updates(map[string]interface{}{
+			"refresh_token_hash": newHash,
+			"user_agent":         sessionModel.UserAgent,
+			"ip_address":         sessionModel.IPAddress,
+			"last_used_at":       now,
+			"expires_at":         expiresAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reusedSessionID != uuid.Nil {
+		return nil, &entities.RefreshTokenReusedError{SessionID: reusedSessionID}
+	}
+
+	return r.modelToEntity(&sessionModel), nil
+}
+
+func (r *sessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error) {
+	var sessionModels []models.UserSession
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessionModels).Error; err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*entities.Session, 0, len(sessionModels))
+	for i := range sessionModels {
+		sessions = append(sessions, r.modelToEntity(&sessionModels[i]))
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAll(ctx context.Context, userID uuid.UUID) (int, error) {
+	result := revokeUserSessions(r.db.WithContext(ctx), userID)
+	return int(result.RowsAffected), result.Error
+}
+
+// revokeUserSessions ยกเลิกทุก session ที่ยังใช้งานได้ของผู้ใช้ ใช้ร่วมกับ transaction ของ userRepository
+func revokeUserSessions(tx *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return tx.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+}
+
+func truncateUserAgent(userAgent string) string {
+	runes := []rune(userAgent)
+	if len(runes) > maxUserAgentLength {
+		return string(runes[:maxUserAgentLength])
+	}
+	return userAgent
+}
+
+func (r *sessionRepository) modelToEntity(sessionModel *models.UserSession) *entities.Session {
+	return &entities.Session{
+		ID:         sessionModel.ID,
+		UserID:     sessionModel.UserID,
+		UserAgent:  sessionModel.UserAgent,
+		IPAddress:  sessionModel.IPAddress,
+		LastUsedAt: sessionModel.LastUsedAt,
+		ExpiresAt:  sessionModel.ExpiresAt,
+		CreatedAt:  sessionModel.CreatedAt,
+	}
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+)
+
+type shippingRepository struct {
+	db *gorm.DB
+}
+
+func NewShippingRepository(db *gorm.DB) repositories.ShippingRepository {
+	return &shippingRepository{db: db}
+}
+
+func (r *shippingRepository) CreateMethod(ctx context.Context, method *entities.ShippingMethod) error {
+	methodModel := &models.ShippingMethod{
+		Code:              method.Code,
+		Name:              method.Name,
+		Description:       method.Description,
+		Active:            method.Active,
+		VolumetricDivisor: method.VolumetricDivisor,
+		SortOrder:         method.SortOrder,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(methodModel).Error; err != nil {
+			return err
+		}
+		// GORM ข้ามค่า false ของคอลัมน์ที่มี default จึงต้องปิดใช้งานแยก
+		if !method.Active {
+			return tx.Model(methodModel).Update("active", false).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	method.ID = methodModel.ID
+	method.CreatedAt = methodModel.CreatedAt
+	method.UpdatedAt = methodModel.UpdatedAt
+	return nil
+}
+
+func (r *shippingRepository) GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error) {
+	var method models.ShippingMethod
+	if err := r.db.WithContext(ctx).Preload("Rates", shippingRateOrder).
+		First(&method, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return shippingMethodModelToEntity(&method), nil
+}
+
+func (r *shippingRepository) GetMethodByCode(ctx context.Context, code string) (*entities.ShippingMethod, error) {
+	var method models.ShippingMethod
+	if err := r.db.WithContext(ctx).Preload("Rates", shippingRateOrder).
+		Where("code = ?", code).First(&method).Error; err != nil {
+		return nil, err
+	}
+
+	return shippingMethodModelToEntity(&method), nil
+}
+
+func (r *shippingRepository) GetMethods(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error) {
+	query := r.db.WithContext(ctx).Preload("Rates", shippingRateOrder)
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var methods []models.ShippingMethod
+	if err := query.Order("sort_order, code").Find(&methods).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.ShippingMethod
+	for _, method := range methods {
+		result = append(result, shippingMethodModelToEntity(&method))
+	}
+
+	return result, nil
+}
+
+func (r *shippingRepository) UpdateMethod(ctx context.Context, id uuid.UUID, method *entities.ShippingMethod) error {
+	updates := map[string]interface{}{
+		"name":               method.Name,
+		"description":        method.Description,
+		"active":             method.Active,
+		"volumetric_divisor": method.VolumetricDivisor,
+		"sort_order":         method.SortOrder,
+	}
+	return r.db.WithContext(ctx).Model(&models.ShippingMethod{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// DeleteMethod ลบวิธีจัดส่งพร้อมอัตราค่าจัดส่งทั้งหมดของวิธีนั้น (คำสั่งซื้อเดิมยังเก็บรหัสและค่าจัดส่งไว้)
+func (r *shippingRepository) DeleteMethod(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shipping_method_id = ?", id).Delete(&models.ShippingRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ShippingMethod{}, "id = ?", id).Error
+	})
+}
+
+func (r *shippingRepository) CreateRate(ctx context.Context, rate *entities.ShippingRate) error {
+	rateModel := &models.ShippingRate{
+		ShippingMethodID: rate.ShippingMethodID,
+		Zone:             rate.Zone,
+		Type:             rate.Type,
+		MinWeightGrams:   rate.MinWeightGrams,
+		MaxWeightGrams:   rate.MaxWeightGrams,
+		Amount:           rate.Amount,
+		PerKg:            rate.PerKg,
+		FreeOver:         rate.FreeOver,
+	}
+
+	if err := r.db.WithContext(ctx).Create(rateModel).Error; err != nil {
+		return err
+	}
+
+	rate.ID = rateModel.ID
+	rate.CreatedAt = rateModel.CreatedAt
+	rate.UpdatedAt = rateModel.UpdatedAt
+	return nil
+}
+
+func (r *shippingRepository) GetRateByID(ctx context.Context, id uuid.UUID) (*entities.ShippingRate, error) {
+	var rate models.ShippingRate
+	if err := r.db.WithContext(ctx).First(&rate, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return shippingRateModelToEntity(&rate), nil
+}
+
+func (r *shippingRepository) UpdateRate(ctx context.Context, id uuid.UUID, rate *entities.ShippingRate) error {
+	updates := map[string]interface{}{
+		"zone":             rate.Zone,
+		"type":             rate.Type,
+		"min_weight_grams": rate.MinWeightGrams,
+		"max_weight_grams": rate.MaxWeightGrams,
+		"amount":           rate.Amount,
+		"per_kg":           rate.PerKg,
+		"free_over":        rate.FreeOver,
+	}
+	return r.db.WithContext(ctx).Model(&models.ShippingRate{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *shippingRepository) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.ShippingRate{}, "id = ?", id).Error
+}
+
+func shippingRateOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("zone, min_weight_grams")
+}
+
+func shippingMethodModelToEntity(method *models.ShippingMethod) *entities.ShippingMethod {
+	methodEntity := &entities.ShippingMethod{
+		ID:                method.ID,
+		Code:              method.Code,
+		Name:              method.Name,
+		Description:       method.Description,
+		Active:            method.Active,
+		VolumetricDivisor: method.VolumetricDivisor,
+		SortOrder:         method.SortOrder,
+		CreatedAt:         method.CreatedAt,
+		UpdatedAt:         method.UpdatedAt,
+	}
+
+	for _, rate := range method.Rates {
+		methodEntity.Rates = append(methodEntity.Rates, *shippingRateModelToEntity(&rate))
+	}
+
+	return methodEntity
+}
+
+func shippingRateModelToEntity(rate *models.ShippingRate) *entities.ShippingRate {
+	return &entities.ShippingRate{
+		ID:               rate.ID,
+		ShippingMethodID: rate.ShippingMethodID,
+		Zone:             rate.Zone,
+		Type:             rate.Type,
+		MinWeightGrams:   rate.MinWeightGrams,
+		MaxWeightGrams:   rate.MaxWeightGrams,
+		Amount:           rate.Amount,
+		PerKg:            rate.PerKg,
+		FreeOver:         rate.FreeOver,
+		CreatedAt:        rate.CreatedAt,
+		UpdatedAt:        rate.UpdatedAt,
+	}
+}
@@ -18,20 +18,24 @@ func NewStatsRepository(db *gorm.DB) repositories.StatsRepository {
 	return &statsRepository{db: db}
 }
 
+// baseSalesSum ผลรวมยอดขายในสกุลเงินหลัก ปัดแต่ละคำสั่งซื้อเป็นสองตำแหน่งก่อนรวม
+const baseSalesSum = "COALESCE(SUM(ROUND(total_price / exchange_rate, 2)), 0)"
+
 func (r *statsRepository) GetSalesStats(ctx context.Context) (*entities.SalesStats, error) {
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	thisYear := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
 
-	stats := &entities.SalesStats{}
+	// ยอดขายทุกสกุลเงินแปลงกลับเป็นสกุลเงินหลักด้วยอัตราที่ตรึงไว้ในคำสั่งซื้อ
+	stats := &entities.SalesStats{Currency: entities.DefaultCurrency}
 
 	// Total sales และ orders (ทั้งหมด)
-	var totalSales float64
+	var totalSales entities.Money
 	var totalOrders int64
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
 		Where("status != ?", "cancelled").
-		Select("COALESCE(SUM(total_price), 0)").
+		Select(baseSalesSum).
 		Scan(&totalSales).Error; err != nil {
 		return nil, err
 	}
@@ -44,11 +48,11 @@ func (r *statsRepository) GetSalesStats(ctx context.Context) (*entities.SalesSta
 	stats.TotalOrders = int(totalOrders)
 
 	// Today's sales และ orders
-	var todaySales float64
+	var todaySales entities.Money
 	var todayOrders int64
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
 		Where("status != ? AND created_at >= ?", "cancelled", today).
-		Select("COALESCE(SUM(total_price), 0)").
+		Select(baseSalesSum).
 		Scan(&todaySales).Error; err != nil {
 		return nil, err
 	}
@@ -61,11 +65,11 @@ func (r *statsRepository) GetSalesStats(ctx context.Context) (*entities.SalesSta
 	stats.TodayOrders = int(todayOrders)
 
 	// Monthly sales และ orders
-	var monthlySales float64
+	var monthlySales entities.Money
 	var monthlyOrders int64
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
 		Where("status != ? AND created_at >= ?", "cancelled", thisMonth).
-		Select("COALESCE(SUM(total_price), 0)").
+		Select(baseSalesSum).
 		Scan(&monthlySales).Error; err != nil {
 		return nil, err
 	}
@@ -78,11 +82,11 @@ func (r *statsRepository) GetSalesStats(ctx context.Context) (*entities.SalesSta
 	stats.MonthlyOrders = int(monthlyOrders)
 
 	// Yearly sales และ orders
-	var yearlySales float64
+	var yearlySales entities.Money
 	var yearlyOrders int64
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
 		Where("status != ? AND created_at >= ?", "cancelled", thisYear).
-		Select("COALESCE(SUM(total_price), 0)").
+		Select(baseSalesSum).
 		Scan(&yearlySales).Error; err != nil {
 		return nil, err
 	}
@@ -174,4 +178,4 @@ func (r *statsRepository) GetUserStats(ctx context.Context) (*entities.UserStats
 	stats.NewUsers = int(newUsers)
 
 	return stats, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+)
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) repositories.TaxRepository {
+	return &taxRepository{db: db}
+}
+
+// CreateClass สร้างประเภทภาษี หากเป็นประเภทเริ่มต้นจะยกเลิกค่าเริ่มต้นของประเภทเดิมใน transaction เดียวกัน
+func (r *taxRepository) CreateClass(ctx context.Context, class *entities.TaxClass) error {
+	classModel := &models.TaxClass{
+		Name:        class.Name,
+		Description: class.Description,
+		IsDefault:   class.IsDefault,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if classModel.IsDefault {
+			if err := clearDefaultTaxClass(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(classModel).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	class.ID = classModel.ID
+	class.CreatedAt = classModel.CreatedAt
+	class.UpdatedAt = classModel.UpdatedAt
+	return nil
+}
+
+func (r *taxRepository) GetClassByID(ctx context.Context, id uuid.UUID) (*entities.TaxClass, error) {
+	var class models.TaxClass
+	if err := r.db.WithContext(ctx).Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("region")
+	}).First(&class, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.classModelToEntity(&class), nil
+}
+
+func (r *taxRepository) GetClassByName(ctx context.Context, name string) (*entities.TaxClass, error) {
+	var class models.TaxClass
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&class).Error; err != nil {
+		return nil, err
+	}
+
+	return r.classModelToEntity(&class), nil
+}
+
+func (r *taxRepository) GetClasses(ctx context.Context) ([]*entities.TaxClass, error) {
+	var classes []models.TaxClass
+	if err := r.db.WithContext(ctx).Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("region")
+	}).Order("name").Find(&classes).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.TaxClass
+	for _, class := range classes {
+		result = append(result, r.classModelToEntity(&class))
+	}
+
+	return result, nil
+}
+
+func (r *taxRepository) UpdateClass(ctx context.Context, id uuid.UUID, class *entities.TaxClass) error {
+	updates := map[string]interface{}{
+		"name":        class.Name,
+		"description": class.Description,
+		"is_default":  class.IsDefault,
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if class.IsDefault {
+			if err := clearDefaultTaxClass(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.TaxClass{}).Where("id = ?", id).Updates(updates).Error
+	})
+}
+
+// DeleteClass ลบประเภทภาษีพร้อมอัตราภาษีทั้งหมดของประเภทนั้น
+func (r *taxRepository) DeleteClass(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tax_class_id = ?", id).Delete(&models.TaxRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.TaxClass{}, "id = ?", id).Error
+	})
+}
+
+// CountClassUsage จำนวนสินค้าและหมวดหมู่ที่ผูกกับประเภทภาษีนี้
+func (r *taxRepository) CountClassUsage(ctx context.Context, id uuid.UUID) (int64, error) {
+	var products, categories int64
+	if err := r.db.WithContext(ctx).Model(&models.Product{}).Where("tax_class_id = ?", id).Count(&products).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Where("tax_class_id = ?", id).Count(&categories).Error; err != nil {
+		return 0, err
+	}
+
+	return products + categories, nil
+}
+
+func (r *taxRepository) CreateRate(ctx context.Context, rate *entities.TaxRate) error {
+	rateModel := &models.TaxRate{
+		TaxClassID: rate.TaxClassID,
+		Region:     rate.Region,
+		Name:       rate.Name,
+		Rate:       rate.Rate,
+	}
+
+	if err := r.db.WithContext(ctx).Create(rateModel).Error; err != nil {
+		return err
+	}
+
+	rate.ID = rateModel.ID
+	rate.CreatedAt = rateModel.CreatedAt
+	rate.UpdatedAt = rateModel.UpdatedAt
+	return nil
+}
+
+func (r *taxRepository) GetRateByID(ctx context.Context, id uuid.UUID) (*entities.TaxRate, error) {
+	var rate models.TaxRate
+	if err := r.db.WithContext(ctx).First(&rate, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return taxRateModelToEntity(&rate), nil
+}
+
+func (r *taxRepository) UpdateRate(ctx context.Context, id uuid.UUID, rate *entities.TaxRate) error {
+	updates := map[string]interface{}{
+		"region": rate.Region,
+		"name":   rate.Name,
+		"rate":   rate.Rate,
+	}
+	return r.db.WithContext(ctx).Model(&models.TaxRate{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *taxRepository) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.TaxRate{}, "id = ?", id).Error
+}
+
+// taxClassColumn ค่าคอลัมน์ tax_class_id สำหรับการแก้ไข UUID ศูนย์หมายถึงล้างค่า (ใช้ประเภทภาษีที่สืบทอดมา)
+func taxClassColumn(id *uuid.UUID) interface{} {
+	if *id == uuid.Nil {
+		return nil
+	}
+	return *id
+}
+
+// clearDefaultTaxClass ยกเลิกค่าเริ่มต้นของประเภทภาษีเดิม (มีประเภทเริ่มต้นได้เพียงหนึ่งเดียว)
+func clearDefaultTaxClass(tx *gorm.DB) error {
+	return tx.Model(&models.TaxClass{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
+
+func (r *taxRepository) classModelToEntity(class *models.TaxClass) *entities.TaxClass {
+	classEntity := &entities.TaxClass{
+		ID:          class.ID,
+		Name:        class.Name,
+		Description: class.Description,
+		IsDefault:   class.IsDefault,
+		CreatedAt:   class.CreatedAt,
+		UpdatedAt:   class.UpdatedAt,
+	}
+
+	for _, rate := range class.Rates {
+		classEntity.Rates = append(classEntity.Rates, *taxRateModelToEntity(&rate))
+	}
+
+	return classEntity
+}
+
+func taxRateModelToEntity(rate *models.TaxRate) *entities.TaxRate {
+	return &entities.TaxRate{
+		ID:         rate.ID,
+		TaxClassID: rate.TaxClassID,
+		Region:     rate.Region,
+		Name:       rate.Name,
+		Rate:       rate.Rate,
+		CreatedAt:  rate.CreatedAt,
+		UpdatedAt:  rate.UpdatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type transactionRepository struct {
@@ -20,26 +22,46 @@ func NewTransactionRepository(db *gorm.DB) repositories.TransactionRepository {
 	return &transactionRepository{db: db}
 }
 
+// Create สร้างการชำระเงินใหม่โดยล็อกคำสั่งซื้อไว้ ตรวจซ้ำว่ายังชำระได้ (กันการยกเลิกที่เกิดพร้อมกัน)
+// และถ้าการชำระเงินครั้งก่อนล้มเหลว จะเปิดกลับเป็น pending ผ่าน retry path ใน tx เดียวกัน
 func (r *transactionRepository) Create(ctx context.Context, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
-	// ตรวจสอบว่าคำสั่งซื้อมีอยู่จริง
-	var order models.Order
-	if err := r.db.WithContext(ctx).First(&order, "id = ?", req.OrderID).Error; err != nil {
+	tx := r.db.WithContext(ctx).Begin()
+
+	order, err := lockOrder(tx, req.OrderID)
+	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
+	if order.Status != entities.OrderStatusPending ||
+		(order.PaymentStatus != entities.PaymentStatusPending && order.PaymentStatus != entities.PaymentStatusFailed) {
+		tx.Rollback()
+		return nil, entities.ErrOrderNotPayable
+	}
 
 	// สร้าง transaction ID แบบ unique
 	transactionID := fmt.Sprintf("TXN_%d_%s", time.Now().Unix(), uuid.New().String()[:8])
 
+	if err := retryOrderPayment(tx, order, nil, "payment retry "+transactionID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	transaction := &models.Transaction{
 		OrderID:       req.OrderID,
 		Amount:        order.TotalPrice,
+		Currency:      order.Currency,
 		PaymentMethod: req.PaymentMethod,
 		Status:        "pending",
 		TransactionID: transactionID,
 		PaymentData:   req.PaymentData,
 	}
 
-	if err := r.db.WithContext(ctx).Create(transaction).Error; err != nil {
+	if err := tx.Create(transaction).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
 
@@ -48,7 +70,7 @@ func (r *transactionRepository) Create(ctx context.Context, req *entities.Create
 
 func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
 	var transaction models.Transaction
-	if err := r.db.WithContext(ctx).First(&transaction, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Refunds.Items").First(&transaction, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -78,38 +100,105 @@ func (r *transactionRepository) GetByTransactionID(ctx context.Context, transact
 	return r.modelToEntity(&transaction), nil
 }
 
+func (r *transactionRepository) GetByProviderRef(ctx context.Context, provider, providerRef string) (*entities.Transaction, error) {
+	var transaction models.Transaction
+	if err := r.db.WithContext(ctx).Where("provider = ? AND provider_ref = ?", provider, providerRef).First(&transaction).Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(&transaction), nil
+}
+
+func (r *transactionRepository) SetProviderRef(ctx context.Context, id uuid.UUID, provider, providerRef string) error {
+	return r.db.WithContext(ctx).Model(&models.Transaction{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"provider":     provider,
+		"provider_ref": providerRef,
+	}).Error
+}
+
+// UpdateStatus ปิดการชำระเงินที่ยังรอชำระ (completed, failed หรือ cancelled) และอัพเดทคำสั่งซื้อใน tx เดียวกัน
+// ล็อกคำสั่งซื้อก่อนแถวการชำระเงิน (ลำดับเดียวกับการยกเลิกคำสั่งซื้อ) แล้วเปลี่ยนเฉพาะแถวที่ยังเป็น pending
+// การชำระเงินที่ปิดไปแล้ว เช่น ถูกยกเลิกพร้อมคำสั่งซื้อ จะได้ ErrPaymentNotPending
 func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	tx := r.db.WithContext(ctx).Begin()
 
-	// อัพเดทสถานะ transaction
-	if err := tx.Model(&models.Transaction{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+	var current models.Transaction
+	if err := tx.Select("order_id").First(&current, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	order, err := lockOrder(tx, current.OrderID)
+	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	// หา transaction เพื่อดึง order ID
 	var transaction models.Transaction
-	if err := tx.First(&transaction, "id = ?", id).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, "id = ?", id).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	if transaction.Status != "pending" {
+		tx.Rollback()
+		return entities.ErrPaymentNotPending
+	}
+
+	result := tx.Model(&models.Transaction{}).Where("id = ? AND status = ?", id, "pending").Update("status", status)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return entities.ErrPaymentNotPending
+	}
 
-	// อัพเดทสถานะการชำระเงินของคำสั่งซื้อ
+	// อัพเดทสถานะการชำระเงินของคำสั่งซื้อตาม state machine
+	// การยกเลิกการชำระเงินครั้งเดียวไม่ยกเลิกการชำระเงินของคำสั่งซื้อ (ผู้ใช้ชำระใหม่ได้จนกว่าคำสั่งซื้อจะถูกยกเลิก)
 	var paymentStatus string
 	switch status {
 	case "completed":
-		paymentStatus = "paid"
+		paymentStatus = entities.PaymentStatusPaid
 	case "failed":
-		paymentStatus = "failed"
-	case "cancelled":
-		paymentStatus = "cancelled"
-	default:
-		paymentStatus = "pending"
+		paymentStatus = entities.PaymentStatusFailed
 	}
 
-	if err := tx.Model(&models.Order{}).Where("id = ?", transaction.OrderID).Update("payment_status", paymentStatus).Error; err != nil {
-		tx.Rollback()
-		return err
+	if paymentStatus == entities.PaymentStatusPaid {
+		// การชำระเงินที่ค้างอยู่สำเร็จหลังจากครั้งอื่นล้มเหลว ต้องเปิดกลับเป็น pending ก่อน (failed → paid ไม่อนุญาตโดยตรง)
+		if err := retryOrderPayment(tx, order, nil, "transaction "+transaction.TransactionID+" completed after failure"); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if paymentStatus != "" {
+		if _, err := transitionOrder(tx, order, entities.OrderFieldPaymentStatus, paymentStatus, nil, "transaction "+transaction.TransactionID+" "+status); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// บันทึก event การชำระเงินลง outbox
+	var eventType string
+	switch status {
+	case "completed":
+		eventType = entities.EventPaymentCompleted
+	case "failed":
+		eventType = entities.EventPaymentFailed
+	}
+	if eventType != "" {
+		if err := recordEvent(tx, eventType, "payment", transaction.ID, entities.PaymentEventPayload{
+			PaymentID:     transaction.ID,
+			OrderID:       transaction.OrderID,
+			TransactionID: transaction.TransactionID,
+			Amount:        transaction.Amount,
+			Currency:      entities.Currency(transaction.Currency),
+			PaymentMethod: transaction.PaymentMethod,
+			Status:        status,
+		}); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
 	return tx.Commit().Error
@@ -119,16 +208,262 @@ func (r *transactionRepository) Cancel(ctx context.Context, id uuid.UUID) error
 	return r.UpdateStatus(ctx, id, "cancelled")
 }
 
+func (r *transactionRepository) CreateRefund(ctx context.Context, transactionID uuid.UUID, req *entities.CreateRefundRequest, createdBy uuid.UUID) (*entities.Refund, error) {
+	var refund models.Refund
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// ล็อก transaction ไว้เพื่อป้องกันการคืนเงินซ้อนกันจนเกินยอด
+		var transaction models.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, "id = ?", transactionID).Error; err != nil {
+			return err
+		}
+
+		if transaction.Status != "completed" && transaction.Status != "partially_refunded" {
+			return errors.New("การชำระเงินนี้ยังไม่สามารถคืนเงินได้")
+		}
+
+		// ยอดที่ถูกจองหรือคืนไปแล้ว
+		var reserved entities.Money
+		if err := tx.Model(&models.Refund{}).
+			Where("transaction_id = ? AND status IN ?", transactionID, []string{"pending", "succeeded"}).
+			Select("COALESCE(SUM(amount), 0)").Scan(&reserved).Error; err != nil {
+			return err
+		}
+
+		remaining := transaction.Amount.Sub(reserved)
+		amount := req.Amount
+		if amount.IsZero() {
+			amount = remaining
+		}
+		if !amount.IsPositive() || amount > remaining {
+			return fmt.Errorf("ยอดคืนเงินเกินยอดที่ชำระแล้ว (คงเหลือ %s)", remaining)
+		}
+
+		refund = models.Refund{
+			TransactionID: transactionID,
+			Amount:        amount,
+			Reason:        req.Reason,
+			Status:        "pending",
+			Restock:       req.Restock,
+			CreatedBy:     &createdBy,
+		}
+
+		if req.Restock {
+			items, err := r.refundItems(tx, transaction.OrderID, req.Items)
+			if err != nil {
+				return err
+			}
+			refund.Items = items
+		}
+
+		return tx.Create(&refund).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refundModelToEntity(&refund), nil
+}
+
+// refundItems คำนวณจำนวนสินค้าที่จะคืนสต็อก โดยไม่ให้เกินจำนวนที่สั่งลบด้วยจำนวนที่คืนแล้วหรือรอคืนอยู่
+func (r *transactionRepository) refundItems(tx *gorm.DB, orderID uuid.UUID, requested []entities.RefundItemRequest) ([]models.RefundItem, error) {
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&orderItems).Error; err != nil {
+		return nil, err
+	}
+
+	type restocked struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	var rows []restocked
+	if err := tx.Table("refund_items").
+		Select("refund_items.order_item_id, SUM(refund_items.quantity) AS quantity").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id").
+		Where("refunds.status = ? AND refunds.deleted_at IS NULL AND refund_items.deleted_at IS NULL", "pending").
+		Where("refund_items.order_item_id IN (?)", tx.Model(&models.OrderItem{}).Select("id").Where("order_id = ?", orderID)).
+		Group("refund_items.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	available := make(map[uuid.UUID]int, len(orderItems))
+	for _, item := range orderItems {
+		available[item.ID] = item.Quantity - item.RestockedQuantity
+	}
+	for _, row := range rows {
+		available[row.OrderItemID] -= row.Quantity
+	}
+
+	var items []models.RefundItem
+	if len(requested) == 0 {
+		// ไม่ระบุรายการ หมายถึงคืนสต็อกทุกรายการที่ยังไม่เคยคืน
+		for _, item := range orderItems {
+			if qty := available[item.ID]; qty > 0 {
+				items = append(items, models.RefundItem{OrderItemID: item.ID, Quantity: qty})
+			}
+		}
+		return items, nil
+	}
+
+	for _, req := range requested {
+		qty, ok := available[req.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("ไม่พบรายการสินค้า %s ในคำสั่งซื้อนี้", req.OrderItemID)
+		}
+		if req.Quantity > qty {
+			return nil, fmt.Errorf("จำนวนคืนสต็อกของรายการ %s เกินจำนวนที่คืนได้ (%d)", req.OrderItemID, qty)
+		}
+		available[req.OrderItemID] -= req.Quantity
+		items = append(items, models.RefundItem{OrderItemID: req.OrderItemID, Quantity: req.Quantity})
+	}
+
+	return items, nil
+}
+
+func (r *transactionRepository) CompleteRefund(ctx context.Context, refundID uuid.UUID, providerRef string) (*entities.Refund, error) {
+	var refund models.Refund
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&refund, "id = ?", refundID).Error; err != nil {
+			return err
+		}
+		if refund.Status != "pending" {
+			return errors.New("รายการคืนเงินนี้ถูกดำเนินการแล้ว")
+		}
+
+		refund.Status = "succeeded"
+		refund.ProviderRef = providerRef
+		if err := tx.Model(&refund).Updates(map[string]interface{}{
+			"status":       refund.Status,
+			"provider_ref": providerRef,
+		}).Error; err != nil {
+			return err
+		}
+
+		// คืนสต็อกสินค้า โดยไม่เกินจำนวนที่ยังไม่เคยคืน (เช่น คำสั่งซื้อถูกยกเลิกไปก่อนแล้ว)
+		for _, item := range refund.Items {
+			var orderItem models.OrderItem
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&orderItem, "id = ?", item.OrderItemID).Error; err != nil {
+				return err
+			}
+
+			qty := item.Quantity
+			if remaining := orderItem.Quantity - orderItem.RestockedQuantity; qty > remaining {
+				qty = remaining
+			}
+			if qty <= 0 {
+				continue
+			}
+
+			if err := addStock(tx, orderItem.ProductID, qty); err != nil {
+				return err
+			}
+			if err := tx.Model(&orderItem).Update("restocked_quantity", gorm.Expr("restocked_quantity + ?", qty)).Error; err != nil {
+				return err
+			}
+		}
+
+		var transaction models.Transaction
+		if err := tx.First(&transaction, "id = ?", refund.TransactionID).Error; err != nil {
+			return err
+		}
+
+		var refunded entities.Money
+		if err := tx.Model(&models.Refund{}).
+			Where("transaction_id = ? AND status = ?", refund.TransactionID, "succeeded").
+			Select("COALESCE(SUM(amount), 0)").Scan(&refunded).Error; err != nil {
+			return err
+		}
+
+		status := entities.PaymentStatusPartiallyRefunded
+		if !transaction.Amount.Sub(refunded).IsPositive() {
+			status = entities.PaymentStatusRefunded
+		}
+
+		if err := tx.Model(&models.Transaction{}).Where("id = ?", transaction.ID).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		order, err := lockOrder(tx, transaction.OrderID)
+		if err != nil {
+			return err
+		}
+		if _, err := transitionOrder(tx, order, entities.OrderFieldPaymentStatus, status, refund.CreatedBy, "refund "+refund.ID.String()); err != nil {
+			return err
+		}
+
+		return recordEvent(tx, entities.EventPaymentRefunded, "payment", transaction.ID, entities.PaymentRefundedPayload{
+			PaymentID:      transaction.ID,
+			OrderID:        transaction.OrderID,
+			RefundID:       refund.ID,
+			Amount:         refund.Amount,
+			RefundedAmount: refunded,
+			Currency:       entities.Currency(transaction.Currency),
+			Status:         status,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.refundModelToEntity(&refund), nil
+}
+
+func (r *transactionRepository) FailRefund(ctx context.Context, refundID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.Refund{}).
+		Where("id = ? AND status = ?", refundID, "pending").
+		Update("status", "failed").Error
+}
+
+func (r *transactionRepository) refundModelToEntity(refund *models.Refund) *entities.Refund {
+	result := &entities.Refund{
+		ID:            refund.ID,
+		TransactionID: refund.TransactionID,
+		Amount:        refund.Amount,
+		Reason:        refund.Reason,
+		Status:        refund.Status,
+		ProviderRef:   refund.ProviderRef,
+		Restock:       refund.Restock,
+		CreatedBy:     refund.CreatedBy,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+	}
+
+	for _, item := range refund.Items {
+		result.Items = append(result.Items, entities.RefundItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	return result
+}
+
 func (r *transactionRepository) modelToEntity(transaction *models.Transaction) *entities.Transaction {
+	var refunds []entities.Refund
+	var refundedAmount entities.Money
+	for _, refund := range transaction.Refunds {
+		refunds = append(refunds, *r.refundModelToEntity(&refund))
+		if refund.Status == "succeeded" {
+			refundedAmount = refundedAmount.Add(refund.Amount)
+		}
+	}
+
 	return &entities.Transaction{
-		ID:            transaction.ID,
-		OrderID:       transaction.OrderID,
-		Amount:        transaction.Amount,
-		PaymentMethod: transaction.PaymentMethod,
-		Status:        transaction.Status,
-		TransactionID: transaction.TransactionID,
-		PaymentData:   transaction.PaymentData,
-		CreatedAt:     transaction.CreatedAt,
-		UpdatedAt:     transaction.UpdatedAt,
-	}
-}
\ No newline at end of file
+		ID:             transaction.ID,
+		OrderID:        transaction.OrderID,
+		Amount:         transaction.Amount,
+		Currency:       entities.Currency(transaction.Currency),
+		PaymentMethod:  transaction.PaymentMethod,
+		Status:         transaction.Status,
+		TransactionID:  transaction.TransactionID,
+		PaymentData:    transaction.PaymentData,
+		Provider:       transaction.Provider,
+		ProviderRef:    transaction.ProviderRef,
+		RefundedAmount: refundedAmount,
+		Refunds:        refunds,
+		CreatedAt:      transaction.CreatedAt,
+		UpdatedAt:      transaction.UpdatedAt,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
 	"gorm.io/gorm"
 )
 
@@ -30,10 +31,25 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User, passwo
 		Phone:     user.Phone,
 		Address:   user.Address,
 		Active:    true,
+		Locale:    entities.NormalizeLocale(user.Locale),
 		RoleID:    user.RoleID,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}
 
-	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(userModel).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, entities.EventUserRegistered, "user", userModel.ID, entities.UserRegisteredPayload{
+			UserID:    userModel.ID,
+			Email:     userModel.Email,
+			FirstName: userModel.FirstName,
+			LastName:  userModel.LastName,
+		})
+	})
+	if err != nil {
 		return err
 	}
 
@@ -102,6 +118,9 @@ func (r *userRepository) Update(ctx context.Context, id uuid.UUID, req *entities
 	if req.Address != "" {
 		updates["address"] = req.Address
 	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
 
 	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(updates).Error
 }
@@ -114,32 +133,33 @@ func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashe
 	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
 }
 
-func (r *userRepository) SetRefreshToken(ctx context.Context, id uuid.UUID, token string) error {
-	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("refresh_token", token).Error
-}
-
-func (r *userRepository) GetByRefreshToken(ctx context.Context, token string) (*entities.User, error) {
-	var userModel models.User
-	if err := r.db.WithContext(ctx).Preload("Role").First(&userModel, "refresh_token = ?", token).Error; err != nil {
-		return nil, err
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, roleID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("role_id", roleID)
+	if result.Error != nil {
+		return result.Error
 	}
-
-	return r.modelToEntity(&userModel), nil
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *userRepository) SetResetToken(ctx context.Context, email string, token string) error {
-	expiry := time.Now().Add(24 * time.Hour) // Token หมดอายุใน 24 ชั่วโมง
+func (r *userRepository) SetResetToken(ctx context.Context, email string, tokenHash string) error {
+	expiry := time.Now().Add(entities.PasswordResetTTL)
 	return r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Updates(map[string]interface{}{
-		"reset_token":        token,
+		"reset_token":        tokenHash,
 		"reset_token_expiry": expiry,
 	}).Error
 }
 
-func (r *userRepository) GetByResetToken(ctx context.Context, token string) (*entities.User, error) {
+func (r *userRepository) GetByResetToken(ctx context.Context, tokenHash string) (*entities.User, error) {
 	var userModel models.User
-	if err := r.db.WithContext(ctx).Preload("Role").Where("reset_token = ? AND reset_token_expiry > ?", token, time.Now()).First(&userModel).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Role").Where("reset_token = ? AND reset_token_expiry > ?", tokenHash, time.Now()).First(&userModel).Error; err != nil {
 		return nil, err
 	}
+	if !utils.TokenHashEqual(userModel.ResetToken, tokenHash) {
+		return nil, gorm.ErrRecordNotFound
+	}
 
 	return r.modelToEntity(&userModel), nil
 }
@@ -159,6 +179,188 @@ func (r *userRepository) GetPasswordHash(ctx context.Context, id uuid.UUID) (str
 	return user.Password, nil
 }
 
+func (r *userRepository) UpdateProfile(ctx context.Context, id uuid.UUID, req *entities.UpdateProfileRequest) error {
+	updates := map[string]interface{}{}
+
+	if req.FirstName != nil {
+		updates["first_name"] = *req.FirstName
+	}
+	if req.LastName != nil {
+		updates["last_name"] = *req.LastName
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Locale != nil {
+		updates["locale"] = entities.NormalizeLocale(*req.Locale)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *userRepository) SetAvatar(ctx context.Context, id uuid.UUID, url string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("avatar", url).Error
+}
+
+func (r *userRepository) SetEmailChange(ctx context.Context, id uuid.UUID, email, tokenHash string, expiry time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"pending_email":             email,
+		"email_change_token":        tokenHash,
+		"email_change_token_expiry": expiry,
+	}).Error
+}
+
+func (r *userRepository) GetByEmailChangeToken(ctx context.Context, tokenHash string) (*entities.User, string, error) {
+	var userModel models.User
+	if err := r.db.WithContext(ctx).Preload("Role").
+		Where("email_change_token = ? AND email_change_token_expiry > ?", tokenHash, time.Now()).
+		First(&userModel).Error; err != nil {
+		return nil, "", err
+	}
+	if !utils.TokenHashEqual(userModel.EmailChangeToken, tokenHash) {
+		return nil, "", gorm.ErrRecordNotFound
+	}
+
+	return r.modelToEntity(&userModel), userModel.PendingEmail, nil
+}
+
+func (r *userRepository) ConfirmEmailChange(ctx context.Context, id uuid.UUID, email string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email":                     email,
+			"pending_email":             "",
+			"email_change_token":        "",
+			"email_change_token_expiry": nil,
+			"email_verified_at":         time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, id).Error
+	})
+}
+
+// SetVerificationToken คืน entities.ErrVerificationRecentlySent หากเพิ่งส่ง token ไปภายใน VerificationResendInterval
+func (r *userRepository) SetVerificationToken(ctx context.Context, id uuid.UUID, tokenHash string, expiry time.Time) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at <= ?)", id, now.Add(-entities.VerificationResendInterval)).
+		Updates(map[string]interface{}{
+			"verification_token":        tokenHash,
+			"verification_token_expiry": expiry,
+			"verification_sent_at":      now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrVerificationRecentlySent
+	}
+	return nil
+}
+
+// VerifyEmail ยืนยันอีเมลด้วย token ที่ยังไม่หมดอายุ token ถูกล้างในคำสั่งเดียวกันจึงใช้ได้ครั้งเดียว
+func (r *userRepository) VerifyEmail(ctx context.Context, tokenHash string) (*entities.User, error) {
+	var userModel models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Role").
+			Where("verification_token = ? AND verification_token <> '' AND verification_token_expiry > ?", tokenHash, time.Now()).
+			First(&userModel).Error; err != nil {
+			return err
+		}
+		if !utils.TokenHashEqual(userModel.VerificationToken, tokenHash) {
+			return gorm.ErrRecordNotFound
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.User{}).Where("id = ? AND verification_token = ?", userModel.ID, tokenHash).Updates(map[string]interface{}{
+			"email_verified_at":         now,
+			"verification_token":        "",
+			"verification_token_expiry": nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		userModel.EmailVerifiedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(&userModel), nil
+}
+
+// Anonymize แทนที่ข้อมูลส่วนตัวด้วยค่าที่ระบุตัวตนไม่ได้ ลบสมุดที่อยู่ session ตะกร้าและรายการโปรด แล้ว soft delete ผู้ใช้
+// คำสั่งซื้อ สำเนาที่อยู่ของคำสั่งซื้อ และการชำระเงินยังคงอยู่เพื่อการบัญชีและภาษี
+func (r *userRepository) Anonymize(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email":                     "deleted-" + id.String() + "@deleted.invalid",
+			"password":                  "",
+			"first_name":                "Deleted",
+			"last_name":                 "User",
+			"avatar":                    "",
+			"phone":                     "",
+			"address":                   "",
+			"active":                    false,
+			"reset_token":               "",
+			"reset_token_expiry":        nil,
+			"pending_email":             "",
+			"email_change_token":        "",
+			"email_change_token_expiry": nil,
+			"verification_token":        "",
+			"verification_token_expiry": nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&models.Address{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&models.UserSession{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM user_wishlist WHERE user_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		var carts []models.Cart
+		if err := tx.Where("user_id = ?", id).Find(&carts).Error; err != nil {
+			return err
+		}
+		for _, cart := range carts {
+			if err := releaseCartHolds(tx, cart.ID, nil); err != nil {
+				return err
+			}
+			if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.Cart{}, "id = ?", cart.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&models.User{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return recordEvent(tx, entities.EventUserDeleted, "user", id, entities.UserDeletedPayload{UserID: id})
+	})
+}
+
 func (r *userRepository) modelToEntity(userModel *models.User) *entities.User {
 	user := &entities.User{
 		ID:        userModel.ID,
@@ -169,9 +371,12 @@ func (r *userRepository) modelToEntity(userModel *models.User) *entities.User {
 		Phone:     userModel.Phone,
 		Address:   userModel.Address,
 		Active:    userModel.Active,
+		Locale:    userModel.Locale,
 		RoleID:    userModel.RoleID,
 		CreatedAt: userModel.CreatedAt,
 		UpdatedAt: userModel.UpdatedAt,
+
+		EmailVerifiedAt: userModel.EmailVerifiedAt,
 	}
 
 	if userModel.Role.ID != uuid.Nil {
@@ -185,4 +390,4 @@ func (r *userRepository) modelToEntity(userModel *models.User) *entities.User {
 	}
 
 	return user
-}
\ No newline at end of file
+}
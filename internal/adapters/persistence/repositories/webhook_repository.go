@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) repositories.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *entities.WebhookEndpoint) error {
+	endpointModel := &models.WebhookEndpoint{
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		EventTypes:  strings.Join(endpoint.EventTypes, ","),
+		Secret:      endpoint.Secret,
+		Active:      true,
+	}
+
+	if err := r.db.WithContext(ctx).Create(endpointModel).Error; err != nil {
+		return err
+	}
+
+	endpoint.ID = endpointModel.ID
+	endpoint.Active = endpointModel.Active
+	endpoint.CreatedAt = endpointModel.CreatedAt
+	endpoint.UpdatedAt = endpointModel.UpdatedAt
+
+	return nil
+}
+
+func (r *webhookRepository) GetEndpointByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error) {
+	var endpointModel models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).First(&endpointModel, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.endpointModelToEntity(&endpointModel), nil
+}
+
+func (r *webhookRepository) GetEndpoints(ctx context.Context, page, limit int) ([]*entities.WebhookEndpoint, int, error) {
+	var endpoints []models.WebhookEndpoint
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Offset(offset).Limit(limit).Find(&endpoints).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.WebhookEndpoint
+	for _, endpoint := range endpoints {
+		result = append(result, r.endpointModelToEntity(&endpoint))
+	}
+
+	return result, int(total), nil
+}
+
+func (r *webhookRepository) GetActiveEndpoints(ctx context.Context) ([]*entities.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.WebhookEndpoint
+	for _, endpoint := range endpoints {
+		result = append(result, r.endpointModelToEntity(&endpoint))
+	}
+
+	return result, nil
+}
+
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, id uuid.UUID, req *entities.UpdateWebhookEndpointRequest) error {
+	updates := map[string]interface{}{}
+
+	if req.URL != "" {
+		updates["url"] = req.URL
+	}
+	if req.Description != "" {
+		updates["description"] = req.Description
+	}
+	if len(req.EventTypes) > 0 {
+		updates["event_types"] = strings.Join(req.EventTypes, ",")
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	return r.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.WebhookEndpoint{}, "id = ?", id).Error
+}
+
+// CreateDeliveries สร้างรายการส่ง โดยข้ามรายการที่เคยสร้างไว้แล้วสำหรับ endpoint/event เดียวกัน
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	// กำหนด ID ฝั่ง client เพราะแถวที่ชนกับ unique index จะไม่ถูก RETURNING กลับมา
+	var rows []models.WebhookDelivery
+	for _, delivery := range deliveries {
+		row := models.WebhookDelivery{
+			EndpointID:    delivery.EndpointID,
+			EventID:       delivery.EventID,
+			EventType:     delivery.EventType,
+			Payload:       string(delivery.Payload),
+			Status:        "pending",
+			NextAttemptAt: time.Now(),
+			RedeliveryOf:  delivery.RedeliveryOf,
+		}
+		row.ID = uuid.New()
+		rows = append(rows, row)
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return err
+	}
+
+	for i := range rows {
+		deliveries[i].ID = rows[i].ID
+		deliveries[i].Status = rows[i].Status
+		deliveries[i].NextAttemptAt = rows[i].NextAttemptAt
+		deliveries[i].CreatedAt = rows[i].CreatedAt
+		deliveries[i].UpdatedAt = rows[i].UpdatedAt
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entities.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return r.deliveryModelToEntity(&delivery), nil
+}
+
+func (r *webhookRepository) GetDeliveriesByEndpoint(ctx context.Context, endpointID uuid.UUID, page, limit int) ([]*entities.WebhookDelivery, int, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointID).Order("created_at DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.WebhookDelivery
+	for _, delivery := range deliveries {
+		result = append(result, r.deliveryModelToEntity(&delivery))
+	}
+
+	return result, int(total), nil
+}
+
+// ClaimPendingDeliveries จองรายการที่ถึงเวลาส่ง (ใช้ SKIP LOCKED เช่นเดียวกับ outbox)
+func (r *webhookRepository) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*entities.WebhookDelivery, error) {
+	now := time.Now()
+
+	var rows []models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND deleted_at IS NULL
+			  AND next_attempt_at <= ?
+			  AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, now, now, limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.WebhookDelivery
+	for _, row := range rows {
+		result = append(result, r.deliveryModelToEntity(&row))
+	}
+
+	return result, nil
+}
+
+// RecordAttempt บันทึกผลการส่ง หากส่งไม่สำเร็จและ nextAttemptAt เป็น nil จะถือว่าล้มเหลวถาวร
+func (r *webhookRepository) RecordAttempt(ctx context.Context, id uuid.UUID, attempts int, result *entities.WebhookDeliveryResult, delivered bool, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"response_status": result.ResponseStatus,
+		"response_body":   result.ResponseBody,
+		"last_error":      result.Error,
+		"locked_until":    nil,
+	}
+
+	switch {
+	case delivered:
+		updates["status"] = "delivered"
+		updates["delivered_at"] = time.Now()
+	case nextAttemptAt == nil:
+		updates["status"] = "failed"
+	default:
+		updates["next_attempt_at"] = *nextAttemptAt
+	}
+
+	return r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *webhookRepository) endpointModelToEntity(endpoint *models.WebhookEndpoint) *entities.WebhookEndpoint {
+	var eventTypes []string
+	for _, t := range strings.Split(endpoint.EventTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+
+	return &entities.WebhookEndpoint{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		EventTypes:  eventTypes,
+		Secret:      endpoint.Secret,
+		Active:      endpoint.Active,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+func (r *webhookRepository) deliveryModelToEntity(delivery *models.WebhookDelivery) *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:             delivery.ID,
+		EndpointID:     delivery.EndpointID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        []byte(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) repositories.WishlistRepository {
+	return &wishlistRepository{db: db}
+}
+
+func (r *wishlistRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.WishlistItem, error) {
+	var items []models.WishlistItem
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN products ON products.id = user_wishlist.product_id AND products.deleted_at IS NULL").
+		Preload("Product").
+		Where("user_wishlist.user_id = ?", userID).
+		Order("user_wishlist.created_at DESC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.WishlistItem, 0, len(items))
+	for i := range items {
+		result = append(result, r.modelToEntity(&items[i]))
+	}
+
+	return result, nil
+}
+
+func (r *wishlistRepository) Exists(ctx context.Context, userID, productID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.WishlistItem{}).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *wishlistRepository) Add(ctx context.Context, userID, productID uuid.UUID) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.WishlistItem{
+		UserID:    userID,
+		ProductID: productID,
+	}).Error
+}
+
+func (r *wishlistRepository) Remove(ctx context.Context, userID, productID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND product_id = ?", userID, productID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *wishlistRepository) modelToEntity(item *models.WishlistItem) *entities.WishlistItem {
+	wishlistItem := &entities.WishlistItem{
+		ProductID: item.ProductID,
+		InStock:   item.Product.Stock > 0,
+		AddedAt:   item.CreatedAt,
+	}
+
+	if item.Product.ID != uuid.Nil {
+		wishlistItem.Product = &entities.Product{
+			ID:          item.Product.ID,
+			Name:        item.Product.Name,
+			Description: item.Product.Description,
+			Price:       item.Product.Price,
+			Currency:    entities.Currency(item.Product.Currency),
+			Stock:       item.Product.Stock,
+			Image:       item.Product.Image,
+			CategoryID:  item.Product.CategoryID,
+			TaxClassID:  item.Product.TaxClassID,
+			WeightGrams: item.Product.WeightGrams,
+			LengthCm:    item.Product.LengthCm,
+			WidthCm:     item.Product.WidthCm,
+			HeightCm:    item.Product.HeightCm,
+			CreatedAt:   item.Product.CreatedAt,
+			UpdatedAt:   item.Product.UpdatedAt,
+		}
+	}
+
+	return wishlistItem
+}
+
+// recordBackInStock บันทึก event product.back_in_stock เมื่อสต็อกเปลี่ยนจาก 0 เป็นบวก
+// และมีผู้ใช้ที่ยังใช้งานอยู่เก็บสินค้านี้ไว้ในรายการโปรด ต้องเรียกใน transaction เดียวกับการแก้สต็อก
+func recordBackInStock(tx *gorm.DB, productID uuid.UUID, before, after int) error {
+	if before > 0 || after <= 0 {
+		return nil
+	}
+
+	var userIDs []uuid.UUID
+	if err := tx.Model(&models.WishlistItem{}).
+		Joins("JOIN users ON users.id = user_wishlist.user_id AND users.deleted_at IS NULL AND users.active").
+		Where("user_wishlist.product_id = ?", productID).
+		Pluck("user_wishlist.user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var product models.Product
+	if err := tx.Select("id", "name").First(&product, "id = ?", productID).Error; err != nil {
+		return err
+	}
+
+	return recordEvent(tx, entities.EventProductBackInStock, "product", productID, entities.ProductBackInStockPayload{
+		ProductID: productID,
+		Name:      product.Name,
+		Stock:     after,
+		UserIDs:   userIDs,
+	})
+}
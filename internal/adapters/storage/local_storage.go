@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// LocalStorage เก็บไฟล์ไว้บนดิสก์ใต้ dir และให้บริการผ่าน baseURL (เช่น APP_URL/uploads)
+// เหมาะกับการพัฒนาและเครื่องเดียว หากรันหลาย instance ควรใช้ object storage แทน
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+var _ gateways.FileStorage = (*LocalStorage)(nil)
+
+// NewLocalStorage สร้างโฟลเดอร์ dir หากยังไม่มี
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create upload dir: %w", err)
+	}
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+func (s *LocalStorage) Save(ctx context.Context, key, contentType string, r io.Reader) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	// เขียนลงไฟล์ชั่วคราวก่อนแล้วค่อยเปลี่ยนชื่อ เพื่อไม่ให้มีไฟล์ครึ่งๆ กลางๆ หากอัปโหลดล้มเหลว
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, url string) error {
+	key, ok := strings.CutPrefix(url, s.baseURL+"/")
+	if !ok {
+		return nil
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path แปลง key เป็น path ใต้ dir และปฏิเสธ key ที่พยายามออกนอกโฟลเดอร์
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
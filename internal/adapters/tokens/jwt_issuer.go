@@ -0,0 +1,293 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// ขนาดกุญแจ RSA ขั้นต่ำที่ยอมรับ
+const minRSAKeyBits = 2048
+
+// JWTIssuerConfig ไฟล์กุญแจ PEM ของ JWTIssuer
+type JWTIssuerConfig struct {
+	// SigningKeyFile กุญแจส่วนตัว (PKCS#8 หรือ PKCS#1) RSA หรือ Ed25519 ที่ใช้ลงนาม token ใหม่
+	// ว่างไว้จะสร้างกุญแจ Ed25519 ชั่วคราว (token ใช้ไม่ได้หลังรีสตาร์ต เหมาะกับการพัฒนาเท่านั้น)
+	SigningKeyFile string
+	// VerificationKeyFiles กุญแจสาธารณะ (หรือกุญแจส่วนตัว) ของกุญแจเก่าที่ยังรับ token อยู่ระหว่างหมุนกุญแจ
+	VerificationKeyFiles []string
+	// Issuer ค่า iss ของ token ที่ออก และค่าที่ต้องตรงกันเมื่อตรวจสอบ (ว่างคือไม่ตรวจ)
+	Issuer string
+}
+
+// jwtKey กุญแจหนึ่งดอกพร้อม kid (RFC 7638 thumbprint) และอัลกอริทึมตามชนิดกุญแจ
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+	jwk     entities.JWK
+}
+
+// jwtClaims รูปแบบ claims ของ access token ใน JWT
+type jwtClaims struct {
+	UserID       string `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	SessionID    string `json:"sid,omitempty"`
+	TokenVersion int    `json:"ver"`
+	jwt.RegisteredClaims
+}
+
+// JWTIssuer ลงนาม access token ด้วย RS256 หรือ EdDSA พร้อม kid ใน header
+// และตรวจสอบด้วยกุญแจตาม kid จากกุญแจปัจจุบันและกุญแจเก่าที่ยังใช้งานอยู่
+type JWTIssuer struct {
+	issuer  string
+	signing *jwtKey
+	keys    map[string]*jwtKey
+	// ordered กุญแจทั้งหมดเรียงตามลำดับที่กำหนด (กุญแจปัจจุบันก่อน) สำหรับ JWKS
+	ordered []*jwtKey
+}
+
+var _ gateways.TokenIssuer = (*JWTIssuer)(nil)
+
+func NewJWTIssuer(cfg JWTIssuerConfig) (*JWTIssuer, error) {
+	var signing *jwtKey
+	if cfg.SigningKeyFile == "" {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate signing key: %w", err)
+		}
+		if signing, err = newJWTKey(private.Public(), private); err != nil {
+			return nil, err
+		}
+	} else {
+		key, err := loadJWTKey(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.private == nil {
+			return nil, fmt.Errorf("signing key %s: private key required", cfg.SigningKeyFile)
+		}
+		signing = key
+	}
+
+	issuer := &JWTIssuer{
+		issuer:  cfg.Issuer,
+		signing: signing,
+		keys:    map[string]*jwtKey{signing.kid: signing},
+		ordered: []*jwtKey{signing},
+	}
+	for _, file := range cfg.VerificationKeyFiles {
+		key, err := loadJWTKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := issuer.keys[key.kid]; exists {
+			continue
+		}
+		issuer.keys[key.kid] = key
+		issuer.ordered = append(issuer.ordered, key)
+	}
+
+	return issuer, nil
+}
+
+// SigningKeyID kid ของกุญแจที่ใช้ลงนาม token ใหม่
+func (i *JWTIssuer) SigningKeyID() string {
+	return i.signing.kid
+}
+
+func (i *JWTIssuer) Issue(claims entities.AccessToken, ttl time.Duration) (string, *entities.AccessToken, error) {
+	now := time.Now()
+	claims.ID = uuid.NewString()
+	claims.IssuedAt = now.Truncate(time.Second)
+	claims.ExpiresAt = now.Add(ttl).Truncate(time.Second)
+
+	sessionID := ""
+	if claims.SessionID != uuid.Nil {
+		sessionID = claims.SessionID.String()
+	}
+	token := jwt.NewWithClaims(i.signing.method, &jwtClaims{
+		UserID:       claims.UserID.String(),
+		Email:        claims.Email,
+		Role:         claims.Role,
+		SessionID:    sessionID,
+		TokenVersion: claims.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.ID,
+			Issuer:    i.issuer,
+			Subject:   claims.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(claims.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+	})
+	token.Header["kid"] = i.signing.kid
+
+	signed, err := token.SignedString(i.signing.private)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, &claims, nil
+}
+
+func (i *JWTIssuer) Verify(tokenString string) (*entities.AccessToken, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if i.issuer != "" {
+		options = append(options, jwt.WithIssuer(i.issuer))
+	}
+
+	claims := &jwtClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, i.keyFor, options...)
+	if err != nil || !token.Valid {
+		return nil, entities.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, entities.ErrInvalidToken
+	}
+
+	accessToken := &entities.AccessToken{
+		ID:           claims.ID,
+		UserID:       userID,
+		Email:        claims.Email,
+		Role:         claims.Role,
+		TokenVersion: claims.TokenVersion,
+		ExpiresAt:    claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		accessToken.IssuedAt = claims.IssuedAt.Time
+	}
+	// token ที่ออกก่อนมี session จะไม่มี sid
+	if sessionID, err := uuid.Parse(claims.SessionID); err == nil {
+		accessToken.SessionID = sessionID
+	}
+
+	return accessToken, nil
+}
+
+func (i *JWTIssuer) JWKS() entities.JWKS {
+	keys := make([]entities.JWK, 0, len(i.ordered))
+	for _, key := range i.ordered {
+		keys = append(keys, key.jwk)
+	}
+	return entities.JWKS{Keys: keys}
+}
+
+// keyFor เลือกกุญแจตาม kid ใน header และบังคับให้อัลกอริทึมตรงกับชนิดกุญแจ (กัน algorithm confusion)
+func (i *JWTIssuer) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := i.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("algorithm %s does not match key %s", token.Method.Alg(), kid)
+	}
+	return key.public, nil
+}
+
+// loadJWTKey อ่านกุญแจ PEM: PRIVATE KEY (PKCS#8), RSA PRIVATE KEY (PKCS#1), PUBLIC KEY (PKIX) หรือ RSA PUBLIC KEY
+func loadJWTKey(file string) (*jwtKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %s: no PEM block found", file)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt key %s: unsupported PEM type %q", file, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s: %w", file, err)
+	}
+
+	var key *jwtKey
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key, err = newJWTKey(signer.Public(), signer)
+	} else {
+		key, err = newJWTKey(parsed, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s: %w", file, err)
+	}
+	return key, nil
+}
+
+// newJWTKey private เป็น nil สำหรับกุญแจที่ใช้ตรวจสอบอย่างเดียว
+func newJWTKey(public crypto.PublicKey, private crypto.PrivateKey) (*jwtKey, error) {
+	key := &jwtKey{private: private, public: public}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.jwk = entities.JWK{
+			Kty: "RSA",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+		key.jwk = entities.JWK{
+			Kty: "OKP",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}
+	default:
+		return nil, errors.New("unsupported key type: use RSA (RS256) or Ed25519 (EdDSA)")
+	}
+
+	key.kid = thumbprint(key.jwk)
+	key.jwk.Kid = key.kid
+	key.jwk.Use = "sig"
+	return key, nil
+}
+
+// thumbprint JWK thumbprint ตาม RFC 7638 (SHA-256 ของสมาชิกที่จำเป็นเรียงตามชื่อ) ใช้เป็น kid
+func thumbprint(jwk entities.JWK) string {
+	var canonical string
+	if jwk.Kty == "RSA" {
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	} else {
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
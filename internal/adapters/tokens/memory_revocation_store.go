@@ -0,0 +1,93 @@
+package tokens
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// MemoryRevocationStore เก็บ deny-list และรุ่นของ token ไว้ในหน่วยความจำ
+// เหมาะกับการพัฒนาและเครื่องเดียว ข้อมูลหายเมื่อรีสตาร์ตและไม่แชร์ระหว่าง instance หากรันหลาย instance ควรใช้ Postgres
+type MemoryRevocationStore struct {
+	mu       sync.RWMutex
+	denied   map[string]time.Time
+	sessions map[uuid.UUID]time.Time
+	versions map[uuid.UUID]int
+}
+
+var _ gateways.TokenRevocationStore = (*MemoryRevocationStore)(nil)
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		denied:   make(map[string]time.Time),
+		sessions: make(map[uuid.UUID]time.Time),
+		versions: make(map[uuid.UUID]int),
+	}
+}
+
+func (s *MemoryRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// ล้าง token ที่หมดอายุไปแล้ว (token เหล่านั้นถูกปฏิเสธจากวันหมดอายุอยู่แล้ว)
+	for id, expiry := range s.denied {
+		if !expiry.After(now) {
+			delete(s.denied, id)
+		}
+	}
+	if expiresAt.After(now) {
+		s.denied[jti] = expiresAt
+	}
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeSession(ctx context.Context, sessionID uuid.UUID, until time.Time) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, expiry := range s.sessions {
+		if !expiry.After(now) {
+			delete(s.sessions, id)
+		}
+	}
+	if until.After(s.sessions[sessionID]) && until.After(now) {
+		s.sessions[sessionID] = until
+	}
+	return nil
+}
+
+func (s *MemoryRevocationStore) TokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[userID], nil
+}
+
+func (s *MemoryRevocationStore) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[userID]++
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, token entities.AccessToken) (bool, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if expiry, ok := s.denied[token.ID]; ok && expiry.After(now) {
+		return true, nil
+	}
+	if expiry, ok := s.sessions[token.SessionID]; ok && expiry.After(now) {
+		return true, nil
+	}
+	return s.versions[token.UserID] != token.TokenVersion, nil
+}
@@ -0,0 +1,77 @@
+package tokens
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"gorm.io/gorm"
+)
+
+// PostgresRevocationStore เก็บ deny-list (revoked_tokens, revoked_sessions) และรุ่นของ token (user_token_versions) ในฐานข้อมูล
+// ใช้ร่วมกันได้ทุก instance แลกกับการ query หนึ่งครั้งต่อคำขอที่ต้องเข้าสู่ระบบ
+type PostgresRevocationStore struct {
+	db *gorm.DB
+}
+
+var _ gateways.TokenRevocationStore = (*PostgresRevocationStore)(nil)
+
+func NewPostgresRevocationStore(db *gorm.DB) *PostgresRevocationStore {
+	return &PostgresRevocationStore{db: db}
+}
+
+// RevokeToken ล้าง jti ที่หมดอายุไปแล้วในคราวเดียวกัน เพื่อไม่ให้ตารางโตไม่สิ้นสุด
+func (s *PostgresRevocationStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM revoked_tokens WHERE expires_at <= now()").Error; err != nil {
+			return err
+		}
+		return tx.Exec(
+			"INSERT INTO revoked_tokens (jti, expires_at, created_at) VALUES (?, ?, now()) ON CONFLICT (jti) DO NOTHING",
+			jti, expiresAt,
+		).Error
+	})
+}
+
+// RevokeSession ขยายเวลาการปฏิเสธหาก session ถูกยกเลิกซ้ำ และล้าง session ที่พ้นเวลาไปแล้วเช่นเดียวกับ RevokeToken
+func (s *PostgresRevocationStore) RevokeSession(ctx context.Context, sessionID uuid.UUID, until time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM revoked_sessions WHERE expires_at <= now()").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			INSERT INTO revoked_sessions (session_id, expires_at, created_at) VALUES (?, ?, now())
+			ON CONFLICT (session_id) DO UPDATE SET expires_at = GREATEST(revoked_sessions.expires_at, EXCLUDED.expires_at)`,
+			sessionID, until,
+		).Error
+	})
+}
+
+func (s *PostgresRevocationStore) TokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	var version int
+	err := s.db.WithContext(ctx).
+		Raw("SELECT COALESCE((SELECT version FROM user_token_versions WHERE user_id = ?), 0)", userID).
+		Scan(&version).Error
+	return version, err
+}
+
+func (s *PostgresRevocationStore) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	return s.db.WithContext(ctx).Exec(`
+		INSERT INTO user_token_versions (user_id, version, updated_at) VALUES (?, 1, now())
+		ON CONFLICT (user_id) DO UPDATE SET version = user_token_versions.version + 1, updated_at = now()`,
+		userID,
+	).Error
+}
+
+func (s *PostgresRevocationStore) IsRevoked(ctx context.Context, token entities.AccessToken) (bool, error) {
+	var revoked bool
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ? AND expires_at > now())
+			OR EXISTS (SELECT 1 FROM revoked_sessions WHERE session_id = ? AND expires_at > now())
+			OR COALESCE((SELECT version FROM user_token_versions WHERE user_id = ?), 0) <> ?`,
+		token.ID, token.SessionID, token.UserID, token.TokenVersion,
+	).Scan(&revoked).Error
+	return revoked, err
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,12 +22,70 @@ type Config struct {
 	DBUser         string
 	DBPassword     string
 	DBSSLMode      string
-	JWTSecret      string
-	JWTExpiresIn   string
+	JWTExpiresIn   time.Duration
 	AdminEmail     string
 	AdminPassword  string
 	AdminFirstName string
 	AdminLastName  string
+
+	// Sessions (refresh token ต่ออายุทุกครั้งที่รีเฟรช) และการยกเลิก access token (postgres | memory)
+	RefreshTokenTTL      time.Duration
+	TokenRevocationStore string
+
+	// JWT signing keys (PEM: RSA สำหรับ RS256 หรือ Ed25519 สำหรับ EdDSA) กุญแจเก่าใส่ใน verification keys ระหว่างหมุนกุญแจ
+	JWTPrivateKeyFile       string
+	JWTVerificationKeyFiles []string
+	JWTIssuer               string
+
+	// Outbox / domain events
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int
+	EventWebhookURL    string
+	EventLogSink       bool
+	WebhookMaxAttempts int
+
+	// RBAC
+	PermissionCacheTTL time.Duration
+
+	// Inventory holds
+	CartHoldTTL              time.Duration
+	OrderPaymentTimeout      time.Duration
+	DeferredPaymentMethods   []string
+	ReservationSweepInterval time.Duration
+
+	// Multi-currency
+	ExchangeRatesFile string
+
+	// Tax
+	TaxPricesIncludeTax bool
+	TaxDefaultRegion    string
+
+	// Payment gateway
+	PaymentProvider          string
+	MockPaymentWebhookSecret string
+
+	// User profile
+	UploadDir      string
+	AvatarMaxBytes int
+	EmailChangeTTL time.Duration
+
+	// Email verification
+	EmailVerificationRequired string
+	EmailVerificationTTL      time.Duration
+
+	// Mail
+	MailDriver      string
+	MailFrom        string
+	MailShopName    string
+	MailLinkBaseURL string
+	MailFileDir     string
+	MailQueueSize   int
+	MailWorkers     int
+	MailMaxAttempts int
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
 }
 
 func LoadConfig() (*Config, error) {
@@ -46,16 +106,73 @@ func LoadConfig() (*Config, error) {
 		DBPort:       getEnv("DB_PORT", "5432"),
 		DBUser:       getEnv("DB_USER", "postgres"),
 		DBSSLMode:    getEnv("DB_SSL", "disable"),
-		JWTExpiresIn: getEnv("JWT_EXPIRES_IN", "24h"),
+		JWTExpiresIn: getEnvDuration("JWT_EXPIRES_IN", 24*time.Hour),
 
 		// ค่าที่ไม่ปลอดภัยสำหรับ default - ต้องกำหนดใน env
 		DBName:         getEnv("DB_NAME", ""),
 		DBPassword:     getEnv("DB_PASS", ""),
-		JWTSecret:      getEnv("JWT_SECRET", ""),
 		AdminEmail:     getEnv("ADMIN_EMAIL", ""),
 		AdminPassword:  getEnv("ADMIN_PASSWORD", ""),
 		AdminFirstName: getEnv("ADMIN_FIRST_NAME", ""),
 		AdminLastName:  getEnv("ADMIN_LAST_NAME", ""),
+
+		RefreshTokenTTL:      getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		TokenRevocationStore: strings.ToLower(getEnv("TOKEN_REVOCATION_STORE", "postgres")),
+
+		JWTPrivateKeyFile:       getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTVerificationKeyFiles: getEnvList("JWT_VERIFICATION_KEY_FILES", ""),
+		JWTIssuer:               getEnv("JWT_ISSUER", ""),
+
+		OutboxPollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", 2*time.Second),
+		OutboxMaxAttempts:  getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+		EventWebhookURL:    getEnv("EVENT_WEBHOOK_URL", ""),
+		EventLogSink:       getEnvBool("EVENT_LOG_SINK", false),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+
+		PermissionCacheTTL: getEnvDuration("PERMISSION_CACHE_TTL", 5*time.Minute),
+
+		CartHoldTTL:              getEnvDuration("CART_HOLD_TTL", 15*time.Minute),
+		OrderPaymentTimeout:      getEnvDuration("ORDER_PAYMENT_TIMEOUT", 30*time.Minute),
+		DeferredPaymentMethods:   getEnvList("DEFERRED_PAYMENT_METHODS", "cod"),
+		ReservationSweepInterval: getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+
+		ExchangeRatesFile: getEnv("EXCHANGE_RATES_FILE", "configs/exchange_rates.json"),
+
+		TaxPricesIncludeTax: getEnvBool("TAX_PRICES_INCLUDE_TAX", true),
+		TaxDefaultRegion:    getEnv("TAX_DEFAULT_REGION", "TH"),
+
+		PaymentProvider:          getEnv("PAYMENT_PROVIDER", "mock"),
+		MockPaymentWebhookSecret: getEnv("MOCK_PAYMENT_WEBHOOK_SECRET", ""),
+
+		UploadDir:      getEnv("UPLOAD_DIR", "uploads"),
+		AvatarMaxBytes: getEnvInt("AVATAR_MAX_BYTES", 2<<20),
+		EmailChangeTTL: getEnvDuration("EMAIL_CHANGE_TTL", 24*time.Hour),
+
+		EmailVerificationRequired: strings.ToLower(getEnv("EMAIL_VERIFICATION_REQUIRED", "checkout")),
+		EmailVerificationTTL:      getEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+
+		MailDriver:      strings.ToLower(getEnv("MAIL_DRIVER", "console")),
+		MailFrom:        getEnv("MAIL_FROM", "Shop <no-reply@example.com>"),
+		MailShopName:    getEnv("MAIL_SHOP_NAME", "Shop"),
+		MailLinkBaseURL: getEnv("MAIL_LINK_BASE_URL", ""),
+		MailFileDir:     getEnv("MAIL_FILE_DIR", "mails"),
+		MailQueueSize:   getEnvInt("MAIL_QUEUE_SIZE", 1000),
+		MailWorkers:     getEnvInt("MAIL_WORKERS", 2),
+		MailMaxAttempts: getEnvInt("MAIL_MAX_ATTEMPTS", 5),
+		SMTPHost:        getEnv("SMTP_HOST", ""),
+		SMTPPort:        getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+	}
+
+	// ลิงก์ในอีเมลชี้ไปที่ APP_URL หากไม่ได้กำหนด URL ของหน้าเว็บร้าน
+	if config.MailLinkBaseURL == "" {
+		config.MailLinkBaseURL = config.AppURL
+	}
+
+	// ค่า iss ของ access token เป็น APP_URL หากไม่ได้กำหนด
+	if config.JWTIssuer == "" {
+		config.JWTIssuer = config.AppURL
 	}
 
 	// ตรวจสอบค่าที่จำเป็นต้องมี
@@ -73,11 +190,8 @@ func validateConfig(config *Config) error {
 		if config.DBPassword == "" {
 			return fmt.Errorf("DB_PASS is required for production environment")
 		}
-		if config.JWTSecret == "" {
-			return fmt.Errorf("JWT_SECRET is required for production environment")
-		}
-		if len(config.JWTSecret) < 32 {
-			return fmt.Errorf("JWT_SECRET must be at least 32 characters long for production")
+		if config.JWTPrivateKeyFile == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for production environment")
 		}
 		if config.DBSSLMode == "disable" {
 			log.Println("Warning: SSL is disabled for database connection in production")
@@ -94,6 +208,10 @@ func validateConfig(config *Config) error {
 		if config.AdminLastName == "" {
 			return fmt.Errorf("ADMIN_LAST_NAME is required for production environment")
 		}
+		// mock gateway ยืนยันการชำระเงินได้ด้วย callback ที่ใครก็สร้างได้ ห้ามใช้ใน production
+		if config.PaymentProvider == "mock" {
+			return fmt.Errorf("PAYMENT_PROVIDER=mock is not allowed in production environment")
+		}
 	}
 
 	// ตรวจสอบรูปแบบ email (เฉพาะเมื่อมีค่า)
@@ -106,6 +224,36 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("DB_NAME is required")
 	}
 
+	if config.JWTExpiresIn <= 0 {
+		return fmt.Errorf("JWT_EXPIRES_IN must be a positive duration such as 15m or 24h")
+	}
+
+	if config.PaymentProvider == "mock" && config.MockPaymentWebhookSecret == "" {
+		return fmt.Errorf("MOCK_PAYMENT_WEBHOOK_SECRET is required when PAYMENT_PROVIDER is mock")
+	}
+
+	switch config.TokenRevocationStore {
+	case "postgres", "memory":
+	default:
+		return fmt.Errorf("TOKEN_REVOCATION_STORE must be one of postgres, memory")
+	}
+
+	switch config.EmailVerificationRequired {
+	case "off", "checkout", "login":
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION_REQUIRED must be one of off, checkout, login")
+	}
+
+	switch config.MailDriver {
+	case "console", "file", "memory":
+	case "smtp":
+		if config.SMTPHost == "" {
+			return fmt.Errorf("SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+	default:
+		return fmt.Errorf("MAIL_DRIVER must be one of console, file, smtp, memory")
+	}
+
 	return nil
 }
 
@@ -117,6 +265,50 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// ฟังก์ชันช่วยสำหรับดึงค่า duration (เช่น 30s, 5m) หรือค่า default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// ฟังก์ชันช่วยสำหรับดึงค่าตัวเลข หรือค่า default
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("Invalid number for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// ฟังก์ชันช่วยสำหรับดึงค่า true/false หรือค่า default
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		log.Printf("Invalid boolean for %s: %q, using default %t", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// ฟังก์ชันช่วยสำหรับดึงรายการที่คั่นด้วยจุลภาค (ตัดช่องว่างและรายการว่างออก) หรือค่า default
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // ฟังก์ชันตรวจสอบอีเมลว่าถูกต้องหรือไม่
 func inValidEmail(email string) bool {
 	if email == "" {
@@ -5,13 +5,12 @@ import (
 	"log"
 	"os"
 
-	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func SetupDatabase(config *Config) *gorm.DB {
-
+// OpenDatabase เชื่อมต่อฐานข้อมูลอย่างเดียว โดยไม่ migrate หรือ seed
+func OpenDatabase(config *Config) *gorm.DB {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 		config.DBHost, config.DBUser, config.DBPassword, config.DBName, config.DBPort, config.DBSSLMode)
 
@@ -22,6 +21,13 @@ func SetupDatabase(config *Config) *gorm.DB {
 
 	log.Println("Database connected successfully")
 
+	return db
+}
+
+func SetupDatabase(config *Config) *gorm.DB {
+
+	db := OpenDatabase(config)
+
 	// ตรวจสอบว่าต้องการ migrate หรือไม่
 	if shouldRunMigration() {
 
@@ -79,51 +85,14 @@ func shouldRunMigration() bool {
 func runMigration(db *gorm.DB) {
 	log.Println("Starting database migration...")
 
-	// Migrate all models
-	err := db.AutoMigrate(
-		&models.Role{},
-		&models.Permission{},
-		&models.User{},
-		&models.Category{},
-		&models.Product{},
-		&models.ProductImage{},
-		&models.Cart{},
-		&models.CartItem{},
-		&models.Order{},
-		&models.OrderItem{},
-		&models.Transaction{},
-	)
+	migrator, err := NewMigrator(db)
 	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	if err := migrator.Up(0); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
 	log.Println("Database migration completed successfully")
 }
-
-// ฟังก์ชันสำหรับ migrate แบบ manual (สำหรับ CLI)
-func RunMigrationManual(config *Config) error {
-	db := SetupDatabase(config)
-
-	log.Println("Running manual migration...")
-
-	// Migrate all models
-	err := db.AutoMigrate(
-		&models.Role{},
-		&models.Permission{},
-		&models.User{},
-		&models.Category{},
-		&models.Product{},
-		&models.ProductImage{},
-		&models.Cart{},
-		&models.CartItem{},
-		&models.Order{},
-		&models.OrderItem{},
-		&models.Transaction{},
-	)
-	if err != nil {
-		return fmt.Errorf("migration failed: %v", err)
-	}
-
-	log.Println("Manual migration completed successfully")
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ไฟล์ migration ถูกฝังไว้ใน binary เพื่อให้ใช้งานได้ทั้งใน Docker และ CLI
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey คือ key ของ PostgreSQL advisory lock
+// ป้องกันไม่ให้ deploy สองตัวรัน migration พร้อมกัน
+const migrationLockKey int64 = 724501359
+
+// Migration แทน migration หนึ่งเวอร์ชันที่มีทั้งไฟล์ up และ down
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus สถานะของ migration แต่ละเวอร์ชัน
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration ตารางสำหรับติดตามเวอร์ชันที่ถูก apply แล้ว
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator จัดการการรัน migration แบบมีเวอร์ชัน (up/down)
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator สร้าง Migrator จากไฟล์ migration ที่ฝังอยู่ใน binary
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations อ่านไฟล์รูปแบบ <version>_<name>.(up|down).sql แล้วเรียงตามเวอร์ชัน
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %v", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s must end with .up.sql or .down.sql", entry.Name())
+		}
+		base = strings.TrimSuffix(base, "."+direction)
+
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", entry.Name())
+		}
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		} else if m.Name != parts[1] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, parts[1])
+		}
+
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing its up file", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s is missing its down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up รัน migration ที่ยังไม่ได้ apply ตามลำดับ (steps <= 0 หมายถึงทั้งหมด)
+func (m *Migrator) Up(steps int) error {
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		count := 0
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if steps > 0 && count >= steps {
+				break
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+
+		if count == 0 {
+			log.Println("No pending migrations")
+		}
+		return nil
+	})
+}
+
+// Down ย้อน migration ล่าสุดตามจำนวน steps (steps <= 0 หมายถึงทั้งหมด)
+func (m *Migrator) Down(steps int) error {
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		count := 0
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if steps > 0 && count >= steps {
+				break
+			}
+			if err := m.rollback(conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+
+		if count == 0 {
+			log.Println("No migrations to roll back")
+		}
+		return nil
+	})
+}
+
+// To ย้ายฐานข้อมูลไปยังเวอร์ชันที่กำหนด ทั้งขาขึ้นและขาลง (0 หมายถึงย้อนทั้งหมด)
+func (m *Migrator) To(version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		// ย้อน migration ที่ใหม่กว่าเวอร์ชันเป้าหมาย
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				if err := m.rollback(conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		// apply migration ที่ยังขาดจนถึงเวอร์ชันเป้าหมาย
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				if err := m.apply(conn, migration); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status คืนค่าสถานะของ migration ทุกเวอร์ชัน
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.ensureTable(m.db); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []MigrationStatus
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+
+	// เวอร์ชันที่มีในฐานข้อมูลแต่ไม่มีไฟล์ (เช่น deploy binary เก่ากว่า)
+	for version, record := range applied {
+		if m.find(version) == nil {
+			appliedAt := record.AppliedAt
+			result = append(result, MigrationStatus{Version: version, Name: record.Name, Applied: true, AppliedAt: &appliedAt})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// withLock ถือ advisory lock บน connection เดียวตลอดการทำงาน
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		log.Println("Acquiring migration lock...")
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("acquire migration lock: %v", err)
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				log.Printf("Failed to release migration lock: %v", err)
+			}
+		}()
+
+		if err := m.ensureTable(conn); err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       varchar(255),
+		applied_at timestamptz NOT NULL
+	)`).Error
+}
+
+func (m *Migrator) appliedVersions(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var records []schemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// apply รัน up migration พร้อมบันทึกเวอร์ชันใน transaction เดียวกัน
+func (m *Migrator) apply(conn *gorm.DB, migration Migration) error {
+	log.Printf("Applying migration %06d_%s", migration.Version, migration.Name)
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return err
+		}
+		return tx.Create(&schemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %06d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// rollback รัน down migration พร้อมลบเวอร์ชันใน transaction เดียวกัน
+func (m *Migrator) rollback(conn *gorm.DB, migration Migration) error {
+	log.Printf("Rolling back migration %06d_%s", migration.Version, migration.Name)
+
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rollback %06d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
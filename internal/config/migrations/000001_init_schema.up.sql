@@ -0,0 +1,169 @@
+-- โครงสร้างฐานข้อมูลเริ่มต้น (ตรงกับที่ GORM AutoMigrate เคยสร้างไว้)
+-- ใช้ IF NOT EXISTS เพื่อให้ฐานข้อมูลเดิมที่ถูก AutoMigrate ไปแล้วสามารถเริ่มใช้ระบบ migration ได้ทันที
+
+CREATE TABLE IF NOT EXISTS roles (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(100),
+    description text
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(100),
+    description text
+);
+CREATE INDEX IF NOT EXISTS idx_permissions_deleted_at ON permissions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       uuid NOT NULL,
+    permission_id uuid NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    deleted_at         timestamptz,
+    email              varchar(100),
+    password           varchar(100),
+    first_name         varchar(100),
+    last_name          varchar(100),
+    avatar             varchar(255),
+    phone              varchar(20),
+    address            text,
+    active             boolean DEFAULT true,
+    role_id            uuid,
+    refresh_token      text,
+    reset_token        text,
+    reset_token_expiry timestamptz,
+    CONSTRAINT fk_roles_users FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(100),
+    description text,
+    image       varchar(255)
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS products (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(100),
+    description text,
+    price       decimal(10,2),
+    stock       int,
+    image       varchar(255),
+    category_id uuid,
+    CONSTRAINT fk_categories_products FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_wishlist (
+    user_id    uuid NOT NULL,
+    product_id uuid NOT NULL,
+    PRIMARY KEY (user_id, product_id),
+    CONSTRAINT fk_user_wishlist_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_wishlist_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+CREATE TABLE IF NOT EXISTS product_images (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    product_id uuid,
+    image_url  varchar(255),
+    CONSTRAINT fk_products_images FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_images_deleted_at ON product_images (deleted_at);
+
+CREATE TABLE IF NOT EXISTS carts (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    user_id     uuid,
+    total_price decimal(10,2),
+    CONSTRAINT fk_carts_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_carts_deleted_at ON carts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    cart_id    uuid,
+    product_id uuid,
+    quantity   int,
+    price      decimal(10,2),
+    CONSTRAINT fk_carts_cart_items FOREIGN KEY (cart_id) REFERENCES carts (id),
+    CONSTRAINT fk_products_cart_items FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX IF NOT EXISTS idx_cart_items_deleted_at ON cart_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at       timestamptz,
+    updated_at       timestamptz,
+    deleted_at       timestamptz,
+    user_id          uuid,
+    total_price      decimal(10,2),
+    status           varchar(50) DEFAULT 'pending',
+    payment_method   varchar(50),
+    payment_status   varchar(50) DEFAULT 'pending',
+    shipping_method  varchar(50),
+    shipping_status  varchar(50) DEFAULT 'pending',
+    shipping_address text,
+    tracking_number  varchar(100),
+    notes            text,
+    CONSTRAINT fk_users_orders FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    order_id   uuid,
+    product_id uuid,
+    quantity   int,
+    price      decimal(10,2),
+    CONSTRAINT fk_orders_order_items FOREIGN KEY (order_id) REFERENCES orders (id),
+    CONSTRAINT fk_products_order_items FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX IF NOT EXISTS idx_order_items_deleted_at ON order_items (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    deleted_at     timestamptz,
+    order_id       uuid,
+    amount         decimal(10,2),
+    payment_method varchar(50),
+    status         varchar(50) DEFAULT 'pending',
+    transaction_id varchar(100),
+    payment_data   text,
+    CONSTRAINT fk_orders_transactions FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
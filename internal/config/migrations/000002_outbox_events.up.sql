@@ -0,0 +1,20 @@
+CREATE TABLE outbox_events (
+    id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    event_type      varchar(100) NOT NULL,
+    aggregate_type  varchar(50),
+    aggregate_id    uuid,
+    payload         jsonb NOT NULL DEFAULT '{}'::jsonb,
+    occurred_at     timestamptz NOT NULL DEFAULT now(),
+    status          varchar(20) NOT NULL DEFAULT 'pending',
+    attempts        int NOT NULL DEFAULT 0,
+    last_error      text,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    locked_until    timestamptz,
+    dispatched_at   timestamptz
+);
+CREATE INDEX idx_outbox_events_deleted_at ON outbox_events (deleted_at);
+CREATE INDEX idx_outbox_events_event_type ON outbox_events (event_type);
+CREATE INDEX idx_outbox_events_pending ON outbox_events (next_attempt_at) WHERE status = 'pending';
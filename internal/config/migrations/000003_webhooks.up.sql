@@ -0,0 +1,38 @@
+CREATE TABLE webhook_endpoints (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    url         varchar(500) NOT NULL,
+    description text,
+    event_types text NOT NULL DEFAULT '',
+    secret      varchar(100) NOT NULL,
+    active      boolean DEFAULT true
+);
+CREATE INDEX idx_webhook_endpoints_deleted_at ON webhook_endpoints (deleted_at);
+
+CREATE TABLE webhook_deliveries (
+    id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    endpoint_id     uuid NOT NULL,
+    event_id        uuid NOT NULL,
+    event_type      varchar(100) NOT NULL,
+    payload         jsonb NOT NULL,
+    status          varchar(20) NOT NULL DEFAULT 'pending',
+    attempts        int NOT NULL DEFAULT 0,
+    response_status int,
+    response_body   text,
+    last_error      text,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    locked_until    timestamptz,
+    delivered_at    timestamptz,
+    redelivery_of   uuid,
+    CONSTRAINT fk_webhook_deliveries_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints (id)
+);
+CREATE INDEX idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+CREATE INDEX idx_webhook_deliveries_endpoint_id ON webhook_deliveries (endpoint_id);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+-- ป้องกันการสร้าง delivery ซ้ำเมื่อ outbox ส่ง event เดิมซ้ำ (ยกเว้นการ redeliver)
+CREATE UNIQUE INDEX idx_webhook_deliveries_endpoint_event ON webhook_deliveries (endpoint_id, event_id) WHERE redelivery_of IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_provider_ref;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS provider_ref;
+ALTER TABLE transactions DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,6 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS provider varchar(50);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS provider_ref varchar(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_provider_ref
+    ON transactions (provider, provider_ref)
+    WHERE provider_ref IS NOT NULL AND provider_ref <> '';
@@ -0,0 +1,33 @@
+CREATE TABLE refunds (
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    deleted_at     timestamptz,
+    transaction_id uuid NOT NULL,
+    amount         decimal(10,2) NOT NULL,
+    reason         text,
+    status         varchar(50) DEFAULT 'pending',
+    provider_ref   varchar(100),
+    restock        boolean DEFAULT false,
+    created_by     uuid,
+    CONSTRAINT fk_transactions_refunds FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    CONSTRAINT chk_refunds_amount CHECK (amount > 0)
+);
+CREATE INDEX idx_refunds_deleted_at ON refunds (deleted_at);
+CREATE INDEX idx_refunds_transaction_id ON refunds (transaction_id);
+
+CREATE TABLE refund_items (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz,
+    refund_id     uuid NOT NULL,
+    order_item_id uuid NOT NULL,
+    quantity      integer NOT NULL,
+    CONSTRAINT fk_refunds_items FOREIGN KEY (refund_id) REFERENCES refunds (id),
+    CONSTRAINT fk_refund_items_order_item FOREIGN KEY (order_item_id) REFERENCES order_items (id),
+    CONSTRAINT chk_refund_items_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_refund_items_deleted_at ON refund_items (deleted_at);
+CREATE INDEX idx_refund_items_refund_id ON refund_items (refund_id);
+CREATE INDEX idx_refund_items_order_item_id ON refund_items (order_item_id);
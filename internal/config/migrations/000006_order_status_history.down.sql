@@ -0,0 +1,3 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS restocked_quantity;
+
+DROP TABLE IF EXISTS order_status_histories;
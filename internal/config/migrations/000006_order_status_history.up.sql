@@ -0,0 +1,17 @@
+CREATE TABLE order_status_histories (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    order_id    uuid NOT NULL,
+    field       varchar(30) NOT NULL,
+    from_status varchar(50),
+    to_status   varchar(50) NOT NULL,
+    changed_by  uuid,
+    note        text,
+    CONSTRAINT fk_orders_history FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_order_status_histories_deleted_at ON order_status_histories (deleted_at);
+CREATE INDEX idx_order_status_histories_order_id ON order_status_histories (order_id, created_at);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS restocked_quantity integer NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS shipment_items;
+DROP TABLE IF EXISTS shipments;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipped_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS carrier;
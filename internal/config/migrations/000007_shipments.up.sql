@@ -0,0 +1,35 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS carrier varchar(100);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipped_at timestamptz;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivered_at timestamptz;
+
+CREATE TABLE shipments (
+    id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    order_id        uuid NOT NULL,
+    carrier         varchar(100),
+    tracking_number varchar(100),
+    status          varchar(50) DEFAULT 'pending',
+    shipped_at      timestamptz,
+    delivered_at    timestamptz,
+    CONSTRAINT fk_orders_shipments FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_shipments_deleted_at ON shipments (deleted_at);
+CREATE INDEX idx_shipments_order_id ON shipments (order_id);
+
+CREATE TABLE shipment_items (
+    id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz,
+    shipment_id   uuid NOT NULL,
+    order_item_id uuid NOT NULL,
+    quantity      integer NOT NULL,
+    CONSTRAINT fk_shipments_items FOREIGN KEY (shipment_id) REFERENCES shipments (id),
+    CONSTRAINT fk_shipment_items_order_item FOREIGN KEY (order_item_id) REFERENCES order_items (id),
+    CONSTRAINT chk_shipment_items_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_shipment_items_deleted_at ON shipment_items (deleted_at);
+CREATE INDEX idx_shipment_items_shipment_id ON shipment_items (shipment_id);
+CREATE INDEX idx_shipment_items_order_item_id ON shipment_items (order_item_id);
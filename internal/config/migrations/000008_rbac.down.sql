@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_role_permissions_permission_id;
+DROP INDEX IF EXISTS idx_permissions_name;
+DROP INDEX IF EXISTS idx_roles_name;
@@ -0,0 +1,4 @@
+-- ชื่อบทบาทและชื่อสิทธิ์ต้องไม่ซ้ำ (ไม่นับรายการที่ถูกลบแล้ว) เพราะ middleware ตรวจสิทธิ์ด้วยชื่อ
+CREATE UNIQUE INDEX idx_roles_name ON roles (name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_permissions_name ON permissions (name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_role_permissions_permission_id ON role_permissions (permission_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_reservations;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_due_at;
@@ -0,0 +1,24 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_due_at timestamptz;
+
+-- การจองสต็อกชั่วคราวของตะกร้า (ยังไม่ตัดสต็อก) หรือคำสั่งซื้อที่ยังไม่ชำระเงิน (ตัดสต็อกแล้ว)
+CREATE TABLE stock_reservations (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    product_id  uuid NOT NULL,
+    cart_id     uuid,
+    order_id    uuid,
+    quantity    integer NOT NULL,
+    status      varchar(20) NOT NULL DEFAULT 'active',
+    expires_at  timestamptz NOT NULL,
+    CONSTRAINT fk_products_reservations FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT fk_carts_reservations FOREIGN KEY (cart_id) REFERENCES carts (id),
+    CONSTRAINT fk_orders_reservations FOREIGN KEY (order_id) REFERENCES orders (id),
+    CONSTRAINT chk_stock_reservations_owner CHECK ((cart_id IS NULL) <> (order_id IS NULL)),
+    CONSTRAINT chk_stock_reservations_quantity CHECK (quantity > 0)
+);
+CREATE INDEX idx_stock_reservations_deleted_at ON stock_reservations (deleted_at);
+CREATE INDEX idx_stock_reservations_product_active ON stock_reservations (product_id, expires_at) WHERE status = 'active';
+CREATE INDEX idx_stock_reservations_order_id ON stock_reservations (order_id);
+CREATE UNIQUE INDEX idx_stock_reservations_cart_product ON stock_reservations (cart_id, product_id) WHERE cart_id IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS product_prices;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE orders DROP COLUMN IF EXISTS base_currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+ALTER TABLE carts DROP COLUMN IF EXISTS currency;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
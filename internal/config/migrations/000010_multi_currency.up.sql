@@ -0,0 +1,24 @@
+-- ราคาหลักของสินค้าและยอดเงินทั้งหมดก่อนหน้านี้เป็นเงินบาท
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'THB';
+
+-- สกุลเงินและอัตราแลกเปลี่ยนที่ตรึงไว้ตอนสั่งซื้อ (1 หน่วย base_currency = exchange_rate หน่วย currency)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency varchar(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS base_currency varchar(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS exchange_rate decimal(18,6) NOT NULL DEFAULT 1;
+
+-- ราคาที่กำหนดเองของสินค้าแยกตามสกุลเงิน
+CREATE TABLE product_prices (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    product_id  uuid NOT NULL,
+    currency    varchar(3) NOT NULL,
+    price       decimal(10,2) NOT NULL,
+    CONSTRAINT fk_products_prices FOREIGN KEY (product_id) REFERENCES products (id),
+    CONSTRAINT chk_product_prices_price CHECK (price >= 0)
+);
+CREATE INDEX idx_product_prices_deleted_at ON product_prices (deleted_at);
+CREATE UNIQUE INDEX idx_product_prices_product_currency ON product_prices (product_id, currency) WHERE deleted_at IS NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_rate;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_class_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_region;
+ALTER TABLE orders DROP COLUMN IF EXISTS prices_include_tax;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal;
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_tax_classes_products;
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class_id;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS fk_tax_classes_categories;
+ALTER TABLE categories DROP COLUMN IF EXISTS tax_class_id;
+
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_classes;
@@ -0,0 +1,55 @@
+-- ประเภทภาษี (tax class) กำหนดให้หมวดหมู่หรือสินค้า
+CREATE TABLE tax_classes (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(100) NOT NULL,
+    description text,
+    is_default  boolean NOT NULL DEFAULT false
+);
+CREATE INDEX idx_tax_classes_deleted_at ON tax_classes (deleted_at);
+CREATE UNIQUE INDEX idx_tax_classes_name ON tax_classes (name) WHERE deleted_at IS NULL;
+-- ประเภทภาษีเริ่มต้นมีได้เพียงหนึ่งเดียว
+CREATE UNIQUE INDEX idx_tax_classes_default ON tax_classes (is_default) WHERE is_default AND deleted_at IS NULL;
+
+-- อัตราภาษีของแต่ละประเภทแยกตามภูมิภาคที่จัดส่ง (รหัสประเทศ, รหัสเขต เช่น TH-10 หรือ * สำหรับทุกภูมิภาค)
+CREATE TABLE tax_rates (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    tax_class_id uuid NOT NULL,
+    region       varchar(10) NOT NULL,
+    name         varchar(100) NOT NULL,
+    rate         numeric(7,4) NOT NULL DEFAULT 0,
+    CONSTRAINT fk_tax_classes_rates FOREIGN KEY (tax_class_id) REFERENCES tax_classes (id),
+    CONSTRAINT chk_tax_rates_rate CHECK (rate >= 0 AND rate <= 100)
+);
+CREATE INDEX idx_tax_rates_deleted_at ON tax_rates (deleted_at);
+CREATE UNIQUE INDEX idx_tax_rates_class_region ON tax_rates (tax_class_id, region) WHERE deleted_at IS NULL;
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tax_class_id uuid;
+ALTER TABLE categories ADD CONSTRAINT fk_tax_classes_categories FOREIGN KEY (tax_class_id) REFERENCES tax_classes (id);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class_id uuid;
+ALTER TABLE products ADD CONSTRAINT fk_tax_classes_products FOREIGN KEY (tax_class_id) REFERENCES tax_classes (id);
+
+-- ยอดรวมของคำสั่งซื้อแยกเป็นยอดสินค้า ภาษี และยอดสุทธิ (total_price)
+-- คำสั่งซื้อเดิมไม่ได้คิดภาษี ยอดสินค้าจึงเท่ากับยอดสุทธิ
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal decimal(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_amount decimal(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS prices_include_tax boolean NOT NULL DEFAULT true;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_region varchar(10) NOT NULL DEFAULT '';
+UPDATE orders SET subtotal = total_price;
+
+-- อัตราและภาษีที่ตรึงไว้ต่อรายการตอนสั่งซื้อ
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_class_id uuid;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_rate numeric(7,4) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_amount decimal(10,2) NOT NULL DEFAULT 0;
+
+-- ภาษีมูลค่าเพิ่มของไทย 7% เป็นข้อมูลตั้งต้น แก้ไขได้ผ่าน API /tax
+INSERT INTO tax_classes (created_at, updated_at, name, description, is_default) VALUES
+    (now(), now(), 'standard', 'สินค้าทั่วไปที่ต้องเสียภาษีมูลค่าเพิ่ม', true),
+    (now(), now(), 'exempt', 'สินค้าที่ได้รับยกเว้นภาษีมูลค่าเพิ่ม', false);
+INSERT INTO tax_rates (created_at, updated_at, tax_class_id, region, name, rate)
+SELECT now(), now(), id, 'TH', 'VAT 7%', 7.0000 FROM tax_classes WHERE name = 'standard';
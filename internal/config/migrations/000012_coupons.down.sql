@@ -0,0 +1,11 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS coupon_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE carts DROP CONSTRAINT IF EXISTS fk_coupons_carts;
+ALTER TABLE carts DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupon_categories;
+DROP TABLE IF EXISTS coupon_products;
+DROP TABLE IF EXISTS coupons;
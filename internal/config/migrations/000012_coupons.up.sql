@@ -0,0 +1,72 @@
+-- คูปองส่วนลด (กรอกรหัส) และส่วนลดอัตโนมัติ (automatic) จำนวนเงินทั้งหมดเป็นสกุลเงินหลักของร้าน
+CREATE TABLE coupons (
+    id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    deleted_at     timestamptz,
+    code           varchar(50) NOT NULL,
+    description    varchar(255),
+    type           varchar(20) NOT NULL,
+    percentage     numeric(7,4) NOT NULL DEFAULT 0,
+    amount         decimal(10,2) NOT NULL DEFAULT 0,
+    max_discount   decimal(10,2) NOT NULL DEFAULT 0,
+    min_spend      decimal(10,2) NOT NULL DEFAULT 0,
+    usage_limit    int NOT NULL DEFAULT 0,
+    per_user_limit int NOT NULL DEFAULT 0,
+    used_count     int NOT NULL DEFAULT 0,
+    starts_at      timestamptz,
+    ends_at        timestamptz,
+    active         boolean NOT NULL DEFAULT true,
+    automatic      boolean NOT NULL DEFAULT false,
+    CONSTRAINT chk_coupons_type CHECK (type IN ('percentage', 'fixed')),
+    CONSTRAINT chk_coupons_percentage CHECK (percentage >= 0 AND percentage <= 100),
+    -- กันการใช้เกินจำนวนครั้งอีกชั้นหนึ่งที่ระดับฐานข้อมูล (0 = ไม่จำกัด)
+    CONSTRAINT chk_coupons_used_count CHECK (used_count >= 0 AND (usage_limit = 0 OR used_count <= usage_limit))
+);
+CREATE INDEX idx_coupons_deleted_at ON coupons (deleted_at);
+CREATE UNIQUE INDEX idx_coupons_code ON coupons (code) WHERE deleted_at IS NULL;
+
+-- ขอบเขตของคูปอง หากไม่มีทั้งสินค้าและหมวดหมู่ถือว่าใช้ได้กับทุกรายการ
+CREATE TABLE coupon_products (
+    coupon_id  uuid NOT NULL,
+    product_id uuid NOT NULL,
+    PRIMARY KEY (coupon_id, product_id),
+    CONSTRAINT fk_coupons_products FOREIGN KEY (coupon_id) REFERENCES coupons (id) ON DELETE CASCADE,
+    CONSTRAINT fk_products_coupons FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+CREATE TABLE coupon_categories (
+    coupon_id   uuid NOT NULL,
+    category_id uuid NOT NULL,
+    PRIMARY KEY (coupon_id, category_id),
+    CONSTRAINT fk_coupons_categories FOREIGN KEY (coupon_id) REFERENCES coupons (id) ON DELETE CASCADE,
+    CONSTRAINT fk_categories_coupons FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+);
+
+-- การใช้คูปองกับคำสั่งซื้อ released_at ถูกตั้งเมื่อคำสั่งซื้อถูกยกเลิกและคืนสิทธิ์การใช้
+CREATE TABLE coupon_redemptions (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    coupon_id   uuid NOT NULL,
+    code        varchar(50) NOT NULL,
+    user_id     uuid NOT NULL,
+    order_id    uuid NOT NULL,
+    amount      decimal(10,2) NOT NULL,
+    currency    varchar(3) NOT NULL DEFAULT 'THB',
+    released_at timestamptz,
+    CONSTRAINT fk_coupons_redemptions FOREIGN KEY (coupon_id) REFERENCES coupons (id),
+    CONSTRAINT fk_orders_redemptions FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX idx_coupon_redemptions_deleted_at ON coupon_redemptions (deleted_at);
+CREATE INDEX idx_coupon_redemptions_coupon_user ON coupon_redemptions (coupon_id, user_id);
+CREATE INDEX idx_coupon_redemptions_order_id ON coupon_redemptions (order_id);
+
+-- คูปองที่ผู้ใช้กรอกไว้ในตะกร้า
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS coupon_id uuid;
+ALTER TABLE carts ADD CONSTRAINT fk_coupons_carts FOREIGN KEY (coupon_id) REFERENCES coupons (id) ON DELETE SET NULL;
+
+-- ส่วนลดที่ตรึงไว้ในคำสั่งซื้อ total_price = subtotal - discount_amount (+ ภาษีเมื่อราคาไม่รวมภาษี)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_amount decimal(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS coupon_code varchar(50) NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_amount decimal(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_amount;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS fk_shipping_methods_orders;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_method_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS height_cm;
+ALTER TABLE products DROP COLUMN IF EXISTS width_cm;
+ALTER TABLE products DROP COLUMN IF EXISTS length_cm;
+ALTER TABLE products DROP COLUMN IF EXISTS weight_grams;
+
+DROP TABLE IF EXISTS shipping_rates;
+DROP TABLE IF EXISTS shipping_methods;
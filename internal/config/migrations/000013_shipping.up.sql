@@ -0,0 +1,58 @@
+-- วิธีจัดส่งที่ลูกค้าเลือกได้ตอนสั่งซื้อ (อ้างอิงด้วย code)
+CREATE TABLE shipping_methods (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    deleted_at         timestamptz,
+    code               varchar(50) NOT NULL,
+    name               varchar(100) NOT NULL,
+    description        varchar(255),
+    active             boolean NOT NULL DEFAULT true,
+    volumetric_divisor int NOT NULL DEFAULT 0,
+    sort_order         int NOT NULL DEFAULT 0,
+    CONSTRAINT chk_shipping_methods_volumetric_divisor CHECK (volumetric_divisor >= 0)
+);
+CREATE INDEX idx_shipping_methods_deleted_at ON shipping_methods (deleted_at);
+CREATE UNIQUE INDEX idx_shipping_methods_code ON shipping_methods (code) WHERE deleted_at IS NULL;
+
+-- อัตราค่าจัดส่งแยกตามโซน (TH, TH-10 หรือ * สำหรับทุกภูมิภาค) และช่วงน้ำหนัก
+-- flat: amount คงที่, weight: amount + per_kg x กิโลกรัม (ปัดขึ้น), free_over: ส่งฟรีเมื่อยอดสินค้าถึงเกณฑ์
+CREATE TABLE shipping_rates (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    deleted_at         timestamptz,
+    shipping_method_id uuid NOT NULL,
+    zone               varchar(10) NOT NULL,
+    type               varchar(20) NOT NULL,
+    min_weight_grams   int NOT NULL DEFAULT 0,
+    max_weight_grams   int NOT NULL DEFAULT 0,
+    amount             decimal(10,2) NOT NULL DEFAULT 0,
+    per_kg             decimal(10,2) NOT NULL DEFAULT 0,
+    free_over          decimal(10,2) NOT NULL DEFAULT 0,
+    CONSTRAINT fk_shipping_methods_rates FOREIGN KEY (shipping_method_id) REFERENCES shipping_methods (id),
+    CONSTRAINT chk_shipping_rates_type CHECK (type IN ('flat', 'weight')),
+    CONSTRAINT chk_shipping_rates_weight CHECK (min_weight_grams >= 0 AND (max_weight_grams = 0 OR max_weight_grams >= min_weight_grams))
+);
+CREATE INDEX idx_shipping_rates_deleted_at ON shipping_rates (deleted_at);
+CREATE INDEX idx_shipping_rates_method_zone ON shipping_rates (shipping_method_id, zone);
+
+-- น้ำหนัก (กรัม) และขนาด (ซม.) ของสินค้าสำหรับคิดค่าจัดส่ง
+ALTER TABLE products ADD COLUMN IF NOT EXISTS weight_grams int NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS length_cm int NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS width_cm int NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS height_cm int NOT NULL DEFAULT 0;
+
+-- วิธีจัดส่งและค่าจัดส่งที่ตรึงไว้กับคำสั่งซื้อ (คำสั่งซื้อเดิมไม่มีค่าจัดส่ง)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_method_id uuid;
+ALTER TABLE orders ADD CONSTRAINT fk_shipping_methods_orders FOREIGN KEY (shipping_method_id) REFERENCES shipping_methods (id);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_amount decimal(10,2) NOT NULL DEFAULT 0;
+
+-- วิธีจัดส่งตั้งต้น แก้ไขได้ผ่าน API /shipping
+INSERT INTO shipping_methods (created_at, updated_at, code, name, description, volumetric_divisor, sort_order) VALUES
+    (now(), now(), 'standard', 'จัดส่งแบบธรรมดา', 'ได้รับสินค้าภายใน 3-5 วันทำการ', 0, 1),
+    (now(), now(), 'express', 'จัดส่งด่วน', 'ได้รับสินค้าภายใน 1-2 วันทำการ', 5000, 2);
+INSERT INTO shipping_rates (created_at, updated_at, shipping_method_id, zone, type, amount, per_kg, free_over)
+SELECT now(), now(), id, 'TH', 'flat', 50.00, 0, 1000.00 FROM shipping_methods WHERE code = 'standard';
+INSERT INTO shipping_rates (created_at, updated_at, shipping_method_id, zone, type, amount, per_kg, free_over)
+SELECT now(), now(), id, 'TH', 'weight', 60.00, 20.00, 0 FROM shipping_methods WHERE code = 'express';
@@ -0,0 +1,52 @@
+-- สมุดที่อยู่ของผู้ใช้ region_code คือรหัสภูมิภาค ISO 3166-2 (เช่น TH-10) สำหรับภาษีและค่าจัดส่ง
+CREATE TABLE addresses (
+    id                  uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at          timestamptz,
+    updated_at          timestamptz,
+    deleted_at          timestamptz,
+    user_id             uuid NOT NULL,
+    label               varchar(50),
+    recipient           varchar(100) NOT NULL,
+    phone               varchar(20) NOT NULL,
+    line1               varchar(255) NOT NULL,
+    line2               varchar(255),
+    sub_district        varchar(100),
+    district            varchar(100),
+    province            varchar(100) NOT NULL,
+    postal_code         varchar(10) NOT NULL,
+    country             varchar(2) NOT NULL DEFAULT 'TH',
+    region_code         varchar(10),
+    is_default_shipping boolean NOT NULL DEFAULT false,
+    is_default_billing  boolean NOT NULL DEFAULT false,
+    CONSTRAINT fk_users_addresses FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_addresses_deleted_at ON addresses (deleted_at);
+CREATE INDEX idx_addresses_user_id ON addresses (user_id);
+-- ที่อยู่เริ่มต้นมีได้อย่างละหนึ่งรายการต่อผู้ใช้
+CREATE UNIQUE INDEX idx_addresses_default_shipping ON addresses (user_id) WHERE is_default_shipping AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_addresses_default_billing ON addresses (user_id) WHERE is_default_billing AND deleted_at IS NULL;
+
+-- สำเนาที่อยู่ ณ เวลาสั่งซื้อ (แก้ไขสมุดที่อยู่ภายหลังไม่กระทบคำสั่งซื้อ)
+CREATE TABLE order_addresses (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    order_id     uuid NOT NULL,
+    kind         varchar(20) NOT NULL,
+    address_id   uuid,
+    recipient    varchar(100) NOT NULL,
+    phone        varchar(20) NOT NULL,
+    line1        varchar(255) NOT NULL,
+    line2        varchar(255),
+    sub_district varchar(100),
+    district     varchar(100),
+    province     varchar(100) NOT NULL,
+    postal_code  varchar(10) NOT NULL,
+    country      varchar(2) NOT NULL,
+    region_code  varchar(10),
+    CONSTRAINT fk_orders_addresses FOREIGN KEY (order_id) REFERENCES orders (id),
+    CONSTRAINT chk_order_addresses_kind CHECK (kind IN ('shipping', 'billing'))
+);
+CREATE INDEX idx_order_addresses_deleted_at ON order_addresses (deleted_at);
+CREATE UNIQUE INDEX idx_order_addresses_order_kind ON order_addresses (order_id, kind) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email_change_token_expiry;
+ALTER TABLE users DROP COLUMN IF EXISTS email_change_token;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
@@ -0,0 +1,4 @@
+-- การเปลี่ยนอีเมลที่รอยืนยัน: อีเมลใหม่จะมีผลหลังยืนยัน token เท่านั้น
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email varchar(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_token text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_token_expiry timestamptz;
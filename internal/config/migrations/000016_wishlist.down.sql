@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_wishlist_product_id;
+ALTER TABLE user_wishlist DROP COLUMN IF EXISTS created_at;
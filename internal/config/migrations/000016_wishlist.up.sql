@@ -0,0 +1,3 @@
+-- เวลาที่เพิ่มสินค้าในรายการโปรด และ index สำหรับหาผู้ใช้ที่รอสินค้ากลับมามีสต็อก
+ALTER TABLE user_wishlist ADD COLUMN IF NOT EXISTS created_at timestamptz NOT NULL DEFAULT now();
+CREATE INDEX IF NOT EXISTS idx_user_wishlist_product_id ON user_wishlist (product_id);
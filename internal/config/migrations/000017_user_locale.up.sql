@@ -0,0 +1,2 @@
+-- ภาษาที่ผู้ใช้ต้องการรับอีเมล (th หรือ en)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale varchar(5) NOT NULL DEFAULT 'th';
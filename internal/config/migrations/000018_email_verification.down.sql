@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS verification_sent_at;
+ALTER TABLE users DROP COLUMN IF EXISTS verification_token_expiry;
+ALTER TABLE users DROP COLUMN IF EXISTS verification_token;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
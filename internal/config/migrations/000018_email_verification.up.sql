@@ -0,0 +1,8 @@
+-- การยืนยันอีเมลหลังสมัครสมาชิก: token ใช้ได้ครั้งเดียวและมีวันหมดอายุ
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_token text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_token_expiry timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verification_sent_at timestamptz;
+
+-- บัญชีที่มีอยู่ก่อนเปิดใช้การยืนยันอีเมลถือว่ายืนยันแล้ว
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
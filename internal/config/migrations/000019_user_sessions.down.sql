@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS refresh_token text;
+DROP TABLE IF EXISTS session_rotated_tokens;
+DROP TABLE IF EXISTS user_sessions;
@@ -0,0 +1,34 @@
+-- session ต่ออุปกรณ์: เก็บเฉพาะ SHA-256 ของ refresh token ปัจจุบัน และหมุน token ทุกครั้งที่รีเฟรช
+CREATE TABLE user_sessions (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    deleted_at         timestamptz,
+    user_id            uuid NOT NULL,
+    refresh_token_hash varchar(64) NOT NULL,
+    user_agent         varchar(255),
+    ip_address         varchar(45),
+    last_used_at       timestamptz NOT NULL DEFAULT now(),
+    revoked_at         timestamptz,
+    CONSTRAINT fk_users_sessions FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_user_sessions_deleted_at ON user_sessions (deleted_at);
+CREATE UNIQUE INDEX idx_user_sessions_refresh_token_hash ON user_sessions (refresh_token_hash);
+CREATE INDEX idx_user_sessions_user_active ON user_sessions (user_id) WHERE revoked_at IS NULL;
+
+-- refresh token ที่ถูกหมุนไปแล้ว ใช้ตรวจการนำ token เก่ากลับมาใช้ซ้ำ (ยกเลิกทั้ง session)
+CREATE TABLE session_rotated_tokens (
+    token_hash varchar(64) PRIMARY KEY,
+    session_id uuid NOT NULL,
+    rotated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT fk_user_sessions_rotated_tokens FOREIGN KEY (session_id) REFERENCES user_sessions (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_session_rotated_tokens_session_id ON session_rotated_tokens (session_id);
+
+-- ย้าย refresh token เดิม (หนึ่งต่อผู้ใช้) เป็น session แล้วเลิกใช้คอลัมน์ users.refresh_token
+INSERT INTO user_sessions (created_at, updated_at, user_id, refresh_token_hash, user_agent, last_used_at)
+SELECT now(), now(), id, encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex'), 'legacy', COALESCE(updated_at, now())
+FROM users
+WHERE refresh_token IS NOT NULL AND refresh_token <> '' AND deleted_at IS NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS refresh_token;
@@ -0,0 +1,10 @@
+ALTER TABLE user_sessions DROP COLUMN IF EXISTS expires_at;
+
+DROP INDEX IF EXISTS idx_users_verification_token;
+DROP INDEX IF EXISTS idx_users_email_change_token;
+DROP INDEX IF EXISTS idx_users_reset_token;
+
+-- hash ย้อนกลับเป็น token จริงไม่ได้ token ที่ค้างอยู่จึงถูกล้าง (ผู้ใช้ขอลิงก์ใหม่ได้)
+UPDATE users SET reset_token = '', reset_token_expiry = NULL WHERE reset_token <> '';
+UPDATE users SET email_change_token = '', email_change_token_expiry = NULL, pending_email = '' WHERE email_change_token <> '';
+UPDATE users SET verification_token = '', verification_token_expiry = NULL WHERE verification_token <> '';
@@ -0,0 +1,17 @@
+-- token ในอีเมล (รีเซ็ตรหัสผ่าน เปลี่ยนอีเมล ยืนยันอีเมล) เก็บเป็น SHA-256 (hex) แทน token จริง
+-- ลิงก์ที่ส่งไปก่อนหน้ายังใช้ได้จนหมดอายุ เพราะ token จริงจะถูก hash ก่อนค้นหา
+UPDATE users SET reset_token = encode(sha256(convert_to(reset_token, 'UTF8')), 'hex')
+WHERE reset_token IS NOT NULL AND reset_token <> '';
+UPDATE users SET email_change_token = encode(sha256(convert_to(email_change_token, 'UTF8')), 'hex')
+WHERE email_change_token IS NOT NULL AND email_change_token <> '';
+UPDATE users SET verification_token = encode(sha256(convert_to(verification_token, 'UTF8')), 'hex')
+WHERE verification_token IS NOT NULL AND verification_token <> '';
+
+CREATE INDEX IF NOT EXISTS idx_users_reset_token ON users (reset_token) WHERE reset_token <> '';
+CREATE INDEX IF NOT EXISTS idx_users_email_change_token ON users (email_change_token) WHERE email_change_token <> '';
+CREATE INDEX IF NOT EXISTS idx_users_verification_token ON users (verification_token) WHERE verification_token <> '';
+
+-- refresh token มีวันหมดอายุ (ต่ออายุทุกครั้งที่รีเฟรช) session เดิมหมดอายุ 30 วันหลังใช้งานล่าสุด
+ALTER TABLE user_sessions ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+UPDATE user_sessions SET expires_at = last_used_at + interval '30 days' WHERE expires_at IS NULL;
+ALTER TABLE user_sessions ALTER COLUMN expires_at SET NOT NULL;
@@ -0,0 +1,15 @@
+-- access token ที่ถูกยกเลิกก่อนหมดอายุ (deny-list ตาม jti) เก็บไว้ถึงเวลาหมดอายุของ token เท่านั้น
+CREATE TABLE revoked_tokens (
+    jti        varchar(64) PRIMARY KEY,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- รุ่นของ access token ต่อผู้ใช้ เพิ่มขึ้นเมื่อยกเลิกทุก token ของผู้ใช้ (ปิดบัญชี เปลี่ยนบทบาท รีเซ็ตรหัสผ่าน)
+CREATE TABLE user_token_versions (
+    user_id    uuid PRIMARY KEY,
+    version    integer NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT fk_users_token_versions FOREIGN KEY (user_id) REFERENCES users (id)
+);
@@ -0,0 +1,8 @@
+-- session ที่ถูกยกเลิก (ออกจากระบบอุปกรณ์เดียว หรือพบการใช้ refresh token ซ้ำ)
+-- access token ทุกตัวที่มี sid นี้ถูกปฏิเสธจนถึง expires_at (เวลาหมดอายุของ token ล่าสุดของ session)
+CREATE TABLE revoked_sessions (
+    session_id uuid PRIMARY KEY,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_revoked_sessions_expires_at ON revoked_sessions (expires_at);
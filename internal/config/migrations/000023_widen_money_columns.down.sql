@@ -0,0 +1,30 @@
+-- ล้มเหลวหากมีจำนวนเงินที่เกิน decimal(10,2) อยู่แล้ว
+ALTER TABLE products ALTER COLUMN price TYPE decimal(10,2);
+ALTER TABLE product_prices ALTER COLUMN price TYPE decimal(10,2);
+ALTER TABLE carts ALTER COLUMN total_price TYPE decimal(10,2);
+ALTER TABLE cart_items ALTER COLUMN price TYPE decimal(10,2);
+
+ALTER TABLE orders
+    ALTER COLUMN subtotal TYPE decimal(10,2),
+    ALTER COLUMN discount_amount TYPE decimal(10,2),
+    ALTER COLUMN tax_amount TYPE decimal(10,2),
+    ALTER COLUMN shipping_amount TYPE decimal(10,2),
+    ALTER COLUMN total_price TYPE decimal(10,2);
+ALTER TABLE order_items
+    ALTER COLUMN price TYPE decimal(10,2),
+    ALTER COLUMN discount_amount TYPE decimal(10,2),
+    ALTER COLUMN tax_amount TYPE decimal(10,2);
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE decimal(10,2);
+ALTER TABLE refunds ALTER COLUMN amount TYPE decimal(10,2);
+
+ALTER TABLE coupons
+    ALTER COLUMN amount TYPE decimal(10,2),
+    ALTER COLUMN max_discount TYPE decimal(10,2),
+    ALTER COLUMN min_spend TYPE decimal(10,2);
+ALTER TABLE coupon_redemptions ALTER COLUMN amount TYPE decimal(10,2);
+
+ALTER TABLE shipping_rates
+    ALTER COLUMN amount TYPE decimal(10,2),
+    ALTER COLUMN per_kg TYPE decimal(10,2),
+    ALTER COLUMN free_over TYPE decimal(10,2);
@@ -0,0 +1,31 @@
+-- decimal(10,2) รับได้ไม่เกิน 99,999,999.99 ซึ่งไม่พอสำหรับสกุลเงินที่มีค่าต่อหน่วยต่ำ (LAK KHR MMK VND)
+-- ขยายทุกคอลัมน์จำนวนเงินเป็น numeric(18,2)
+ALTER TABLE products ALTER COLUMN price TYPE numeric(18,2);
+ALTER TABLE product_prices ALTER COLUMN price TYPE numeric(18,2);
+ALTER TABLE carts ALTER COLUMN total_price TYPE numeric(18,2);
+ALTER TABLE cart_items ALTER COLUMN price TYPE numeric(18,2);
+
+ALTER TABLE orders
+    ALTER COLUMN subtotal TYPE numeric(18,2),
+    ALTER COLUMN discount_amount TYPE numeric(18,2),
+    ALTER COLUMN tax_amount TYPE numeric(18,2),
+    ALTER COLUMN shipping_amount TYPE numeric(18,2),
+    ALTER COLUMN total_price TYPE numeric(18,2);
+ALTER TABLE order_items
+    ALTER COLUMN price TYPE numeric(18,2),
+    ALTER COLUMN discount_amount TYPE numeric(18,2),
+    ALTER COLUMN tax_amount TYPE numeric(18,2);
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE numeric(18,2);
+ALTER TABLE refunds ALTER COLUMN amount TYPE numeric(18,2);
+
+ALTER TABLE coupons
+    ALTER COLUMN amount TYPE numeric(18,2),
+    ALTER COLUMN max_discount TYPE numeric(18,2),
+    ALTER COLUMN min_spend TYPE numeric(18,2);
+ALTER TABLE coupon_redemptions ALTER COLUMN amount TYPE numeric(18,2);
+
+ALTER TABLE shipping_rates
+    ALTER COLUMN amount TYPE numeric(18,2),
+    ALTER COLUMN per_kg TYPE numeric(18,2),
+    ALTER COLUMN free_over TYPE numeric(18,2);
@@ -0,0 +1,2 @@
+-- sink ที่ส่ง event สำเร็จแล้ว (คั่นด้วยจุลภาค) การ retry จะส่งเฉพาะ sink ที่ยังไม่สำเร็จ
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS delivered_sinks text NOT NULL DEFAULT '';
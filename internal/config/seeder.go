@@ -2,8 +2,10 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/whatup1359/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
 	"gorm.io/gorm"
 )
@@ -17,6 +19,11 @@ func SeedDatabase(db *gorm.DB, config *Config) error {
 		return err
 	}
 
+	// Seed permissions แล้วผูกทุกสิทธิ์ให้ admin
+	if err := seedPermissions(db); err != nil {
+		return err
+	}
+
 	// Seed admin user
 	if err := seedAdminUser(db, config); err != nil {
 		return err
@@ -78,14 +85,16 @@ func seedAdminUser(db *gorm.DB, config *Config) error {
 		return err
 	}
 
-	// สร้าง admin user
+	// สร้าง admin user (ถือว่ายืนยันอีเมลแล้ว)
+	verifiedAt := time.Now()
 	adminUser := &models.User{
-		Email:     config.AdminEmail,
-		Password:  hashedPassword,
-		FirstName: config.AdminFirstName,
-		LastName:  config.AdminLastName,
-		RoleID:    adminRole.ID,
-		Active:    true,
+		Email:           config.AdminEmail,
+		Password:        hashedPassword,
+		FirstName:       config.AdminFirstName,
+		LastName:        config.AdminLastName,
+		RoleID:          adminRole.ID,
+		Active:          true,
+		EmailVerifiedAt: &verifiedAt,
 	}
 
 	if err := db.Create(adminUser).Error; err != nil {
@@ -133,6 +142,45 @@ func seedRoles(db *gorm.DB) error {
 	return nil
 }
 
+// seedPermissions สร้างสิทธิ์ตาม catalogue และกำหนดให้บทบาท admin
+// บทบาทอื่น (เช่น support, warehouse) สร้างและกำหนดสิทธิ์ผ่าน API ได้ภายหลัง
+func seedPermissions(db *gorm.DB) error {
+	var permissions []models.Permission
+	for _, item := range entities.PermissionCatalogue {
+		var permission models.Permission
+		if err := db.Where("name = ?", item.Name).First(&permission).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				log.Printf("❌ Error checking permission %s: %v", item.Name, err)
+				return err
+			}
+
+			permission = models.Permission{
+				Name:        item.Name,
+				Description: item.Description,
+			}
+			if err := db.Create(&permission).Error; err != nil {
+				log.Printf("❌ Error creating permission %s: %v", item.Name, err)
+				return err
+			}
+			log.Printf("✅ Permission created: %s", item.Name)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	var adminRole models.Role
+	if err := db.Where("name = ?", entities.RoleAdmin).First(&adminRole).Error; err != nil {
+		log.Printf("❌ Admin role not found: %v", err)
+		return err
+	}
+
+	if err := db.Omit("Permissions.*").Model(&adminRole).Association("Permissions").Append(permissions); err != nil {
+		log.Printf("❌ Error granting permissions to admin role: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 // seedCategories สร้างหมวดหมู่สินค้าเริ่มต้น
 func seedCategories(db *gorm.DB) error {
 	categories := []models.Category{
@@ -226,7 +274,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "iPhone 15 Pro",
 			Description: "สมาร์ทโฟนรุ่นล่าสุดจาก Apple พร้อมชิป A17 Pro",
-			Price:       39900,
+			Price:       entities.MoneyFromMajor(39900),
 			Stock:       50,
 			CategoryID:  categories[0].ID, // Electronics
 			Images: []models.ProductImage{
@@ -236,7 +284,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "MacBook Air M2",
 			Description: "แล็ปท็อปที่บางและเบาพร้อมชิป M2",
-			Price:       42900,
+			Price:       entities.MoneyFromMajor(42900),
 			Stock:       30,
 			CategoryID:  categories[0].ID,
 			Images: []models.ProductImage{
@@ -247,7 +295,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "เสื้อเชิ้ตผ้าคอตตอน",
 			Description: "เสื้อเชิ้ตผ้าคอตตอน 100% สีขาว คลาสสิค",
-			Price:       1290,
+			Price:       entities.MoneyFromMajor(1290),
 			Stock:       100,
 			CategoryID:  categories[1].ID, // Fashion
 			Images: []models.ProductImage{
@@ -257,7 +305,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "กางเกงยีนส์ Slim Fit",
 			Description: "กางเกงยีนส์ทรง Slim Fit สีน้ำเงินเข้ม",
-			Price:       1890,
+			Price:       entities.MoneyFromMajor(1890),
 			Stock:       75,
 			CategoryID:  categories[1].ID,
 			Images: []models.ProductImage{
@@ -268,7 +316,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "โซฟาผ้า 3 ที่นั่ง",
 			Description: "โซฟาผ้าสีเทา 3 ที่นั่ง สไตล์โมเดิร์น",
-			Price:       15900,
+			Price:       entities.MoneyFromMajor(15900),
 			Stock:       20,
 			CategoryID:  categories[2].ID, // Home & Garden
 			Images: []models.ProductImage{
@@ -278,7 +326,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ชุดเครื่องนอน Cotton",
 			Description: "ชุดเครื่องนอนผ้าคอตตอน 100% ขนาด 6 ฟุต",
-			Price:       2490,
+			Price:       entities.MoneyFromMajor(2490),
 			Stock:       60,
 			CategoryID:  categories[2].ID,
 			Images: []models.ProductImage{
@@ -289,7 +337,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "รองเท้าวิ่ง Nike",
 			Description: "รองเท้าวิ่งเพื่อสุขภาพ เบาสบาย",
-			Price:       3290,
+			Price:       entities.MoneyFromMajor(3290),
 			Stock:       80,
 			CategoryID:  categories[3].ID, // Sports & Outdoors
 			Images: []models.ProductImage{
@@ -299,7 +347,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ดัมเบลปรับน้ำหนักได้",
 			Description: "ดัมเบลปรับน้ำหนักได้ 5-25 กก. คู่ละ",
-			Price:       4590,
+			Price:       entities.MoneyFromMajor(4590),
 			Stock:       25,
 			CategoryID:  categories[3].ID,
 			Images: []models.ProductImage{
@@ -310,7 +358,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "หนังสือ Clean Code",
 			Description: "หนังสือสอนการเขียนโค้ดที่สะอาด",
-			Price:       890,
+			Price:       entities.MoneyFromMajor(890),
 			Stock:       40,
 			CategoryID:  categories[4].ID, // Books & Media
 			Images: []models.ProductImage{
@@ -320,7 +368,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "หนังสือ Design Patterns",
 			Description: "หนังสือเรียนรู้ Design Patterns",
-			Price:       1290,
+			Price:       entities.MoneyFromMajor(1290),
 			Stock:       35,
 			CategoryID:  categories[4].ID,
 			Images: []models.ProductImage{
@@ -331,7 +379,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ครีมบำรุงหน้า Vitamin C",
 			Description: "ครีมบำรุงหน้าสูตร Vitamin C ลดจุดด่างดำ",
-			Price:       1590,
+			Price:       entities.MoneyFromMajor(1590),
 			Stock:       90,
 			CategoryID:  categories[5].ID, // Health & Beauty
 			Images: []models.ProductImage{
@@ -341,7 +389,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "แชมพูสมุนไพร",
 			Description: "แชมพูสมุนไพรสำหรับผมแห้งเสีย",
-			Price:       390,
+			Price:       entities.MoneyFromMajor(390),
 			Stock:       120,
 			CategoryID:  categories[5].ID,
 			Images: []models.ProductImage{
@@ -352,7 +400,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ตัวต่อเลโก้ Creator",
 			Description: "ชุดตัวต่อเลโก้ Creator Expert 2000 ชิ้น",
-			Price:       3990,
+			Price:       entities.MoneyFromMajor(3990),
 			Stock:       30,
 			CategoryID:  categories[6].ID, // Toys & Games
 			Images: []models.ProductImage{
@@ -362,7 +410,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "บอร์ดเกม Monopoly",
 			Description: "เกมโมโนโพลี่ฉบับภาษาไทย",
-			Price:       1290,
+			Price:       entities.MoneyFromMajor(1290),
 			Stock:       45,
 			CategoryID:  categories[6].ID,
 			Images: []models.ProductImage{
@@ -373,7 +421,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "น้ำมันเครื่อง Mobil 1",
 			Description: "น้ำมันเครื่องสังเคราะห์ 100% 5W-30",
-			Price:       890,
+			Price:       entities.MoneyFromMajor(890),
 			Stock:       100,
 			CategoryID:  categories[7].ID, // Automotive
 			Images: []models.ProductImage{
@@ -383,7 +431,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ยางรถยนต์ Michelin",
 			Description: "ยางรถยนต์ Michelin ขนาด 195/65R15",
-			Price:       3290,
+			Price:       entities.MoneyFromMajor(3290),
 			Stock:       40,
 			CategoryID:  categories[7].ID,
 			Images: []models.ProductImage{
@@ -394,7 +442,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "กาแฟอราบิก้า 100%",
 			Description: "เมล็ดกาแฟอราบิก้า 100% คั่วกลาง 250g",
-			Price:       590,
+			Price:       entities.MoneyFromMajor(590),
 			Stock:       80,
 			CategoryID:  categories[8].ID, // Food & Beverages
 			Images: []models.ProductImage{
@@ -404,7 +452,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "ชาเขียวญี่ปุ่น",
 			Description: "ชาเขียวญี่ปุ่นแท้ 100g",
-			Price:       890,
+			Price:       entities.MoneyFromMajor(890),
 			Stock:       60,
 			CategoryID:  categories[8].ID,
 			Images: []models.ProductImage{
@@ -415,7 +463,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "เก้าอี้สำนักงานเออร์โกโนมิก",
 			Description: "เก้าอี้สำนักงานปรับระดับได้ รองรับหลัง",
-			Price:       7990,
+			Price:       entities.MoneyFromMajor(7990),
 			Stock:       15,
 			CategoryID:  categories[9].ID, // Office Supplies
 			Images: []models.ProductImage{
@@ -425,7 +473,7 @@ func seedProducts(db *gorm.DB) error {
 		{
 			Name:        "โต๊ะทำงานไม้โอ๊ค",
 			Description: "โต๊ะทำงานไม้โอ๊คขนาด 120x60 ซม.",
-			Price:       5490,
+			Price:       entities.MoneyFromMajor(5490),
 			Stock:       20,
 			CategoryID:  categories[9].ID,
 			Images: []models.ProductImage{
@@ -443,7 +491,7 @@ func seedProducts(db *gorm.DB) error {
 					log.Printf("❌ Error creating product %s: %v", product.Name, err)
 					return err
 				}
-				log.Printf("✅ Product created: %s (฿%.2f)", product.Name, product.Price)
+				log.Printf("✅ Product created: %s (฿%s)", product.Name, product.Price)
 			} else {
 				log.Printf("❌ Error checking product %s: %v", product.Name, err)
 				return err
@@ -452,4 +500,4 @@ func seedProducts(db *gorm.DB) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// บทบาทที่ระบบสร้างไว้ตั้งแต่เริ่มต้น ห้ามลบหรือเปลี่ยนชื่อ
+const (
+	// RoleAdmin ผู้ดูแลระบบ เข้าถึงข้อมูลของผู้ใช้ทุกคนและมีทุกสิทธิ์
+	RoleAdmin = "admin"
+	// RoleUser บทบาทเริ่มต้นของผู้ใช้ที่สมัครสมาชิกเอง
+	RoleUser = "user"
+)
+
+var (
+	// ErrNotFound ไม่พบข้อมูล หรือข้อมูลเป็นของผู้ใช้อื่น (ตอบกลับเป็น 404 เพื่อไม่เปิดเผยว่ามีข้อมูลอยู่)
+	ErrNotFound = errors.New("ไม่พบข้อมูล")
+	// ErrForbidden มองเห็นข้อมูลได้แต่ไม่มีสิทธิ์ทำรายการนี้ (ตอบกลับเป็น 403)
+	ErrForbidden = errors.New("ไม่มีสิทธิ์ทำรายการนี้")
+)
+
+// Actor ผู้ใช้ที่ทำรายการ ใช้ตรวจสิทธิ์ความเป็นเจ้าของข้อมูลใน service
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+	// Permissions สิทธิ์ของบทบาท (RBAC) ที่ใช้เข้าถึงข้อมูลของผู้อื่น
+	Permissions []string
+}
+
+// Can ตรวจสอบว่าบทบาทมีสิทธิ์ที่กำหนด บทบาท admin มีทุกสิทธิ์เสมอ (เหมือน RBACService.HasPermission)
+func (a Actor) Can(permission string) bool {
+	if a.Role == RoleAdmin {
+		return true
+	}
+	for _, name := range a.Permissions {
+		if name == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess ตรวจสอบว่าเป็นเจ้าของข้อมูล หรือมีสิทธิ์เข้าถึงข้อมูลของผู้อื่น เช่น orders:read
+func (a Actor) CanAccess(ownerID uuid.UUID, permission string) bool {
+	return a.UserID == ownerID || a.Can(permission)
+}
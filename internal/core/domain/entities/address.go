@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ประเภทของที่อยู่ที่บันทึกไว้กับคำสั่งซื้อ
+const (
+	AddressKindShipping = "shipping"
+	AddressKindBilling  = "billing"
+)
+
+// DefaultCountry ประเทศเริ่มต้นของที่อยู่ (ISO 3166-1 alpha-2)
+const DefaultCountry = "TH"
+
+// ErrShippingAddressRequired ไม่ได้ระบุที่อยู่จัดส่งและไม่มีที่อยู่จัดส่งเริ่มต้นในสมุดที่อยู่
+var ErrShippingAddressRequired = errors.New("กรุณาระบุที่อยู่จัดส่งหรือกำหนดที่อยู่จัดส่งเริ่มต้นในสมุดที่อยู่")
+
+// AddressFields ข้อมูลที่อยู่แบบมีโครงสร้าง ใช้ร่วมกันระหว่างสมุดที่อยู่และที่อยู่ของคำสั่งซื้อ
+type AddressFields struct {
+	Recipient   string `json:"recipient"`
+	Phone       string `json:"phone"`
+	Line1       string `json:"line1"`
+	Line2       string `json:"line2"`
+	SubDistrict string `json:"sub_district"`
+	District    string `json:"district"`
+	Province    string `json:"province"`
+	PostalCode  string `json:"postal_code"`
+	Country     string `json:"country"`
+	// RegionCode รหัสภูมิภาคตาม ISO 3166-2 เช่น TH-10 (กรุงเทพฯ) ใช้เลือกอัตราภาษีและค่าจัดส่ง
+	RegionCode string `json:"region_code"`
+}
+
+// Region ภูมิภาคของที่อยู่สำหรับภาษีและค่าจัดส่ง: RegionCode หากระบุ มิฉะนั้นใช้ประเทศ
+func (a AddressFields) Region() string {
+	if strings.TrimSpace(a.RegionCode) != "" {
+		return NormalizeRegion(a.RegionCode)
+	}
+	return NormalizeRegion(a.Country)
+}
+
+// Format ที่อยู่เป็นข้อความบรรทัดเดียว สำหรับฉลากจัดส่งและช่อง shipping_address แบบเดิม
+func (a AddressFields) Format() string {
+	var parts []string
+	for _, part := range []string{a.Recipient, a.Phone, a.Line1, a.Line2, a.SubDistrict, a.District, a.Province, a.PostalCode, a.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Normalize ตัดช่องว่างและกำหนดรูปแบบประเทศ/รหัสภูมิภาคเป็นตัวพิมพ์ใหญ่
+func (a *AddressFields) Normalize() {
+	a.Recipient = strings.TrimSpace(a.Recipient)
+	a.Phone = strings.TrimSpace(a.Phone)
+	a.Line1 = strings.TrimSpace(a.Line1)
+	a.Line2 = strings.TrimSpace(a.Line2)
+	a.SubDistrict = strings.TrimSpace(a.SubDistrict)
+	a.District = strings.TrimSpace(a.District)
+	a.Province = strings.TrimSpace(a.Province)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	if a.Country == "" {
+		a.Country = DefaultCountry
+	}
+	a.RegionCode = strings.ToUpper(strings.TrimSpace(a.RegionCode))
+}
+
+// Address ที่อยู่ในสมุดที่อยู่ของผู้ใช้ แต่ละผู้ใช้มีที่อยู่จัดส่งและที่อยู่ออกใบเสร็จเริ่มต้นได้อย่างละหนึ่งรายการ
+type Address struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// Label ชื่อเรียกที่อยู่ เช่น บ้าน หรือ ที่ทำงาน
+	Label string `json:"label"`
+	AddressFields
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// OrderAddress สำเนาที่อยู่ที่ตรึงไว้กับคำสั่งซื้อ การแก้ไขหรือลบที่อยู่ในสมุดที่อยู่ภายหลังไม่กระทบคำสั่งซื้อ
+type OrderAddress struct {
+	ID      uuid.UUID `json:"id"`
+	OrderID uuid.UUID `json:"order_id"`
+	Kind    string    `json:"kind"`
+	// AddressID ที่อยู่ต้นทางในสมุดที่อยู่ (ว่างหากเป็นที่อยู่แบบข้อความ)
+	AddressID *uuid.UUID `json:"address_id,omitempty"`
+	AddressFields
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateAddressRequest struct {
+	Label             string `json:"label" validate:"max=50"`
+	Recipient         string `json:"recipient" validate:"required,max=100"`
+	Phone             string `json:"phone" validate:"required,max=20"`
+	Line1             string `json:"line1" validate:"required,max=255"`
+	Line2             string `json:"line2" validate:"max=255"`
+	SubDistrict       string `json:"sub_district" validate:"max=100"`
+	District          string `json:"district" validate:"max=100"`
+	Province          string `json:"province" validate:"required,max=100"`
+	PostalCode        string `json:"postal_code" validate:"required,max=10"`
+	Country           string `json:"country" validate:"omitempty,len=2"`
+	RegionCode        string `json:"region_code" validate:"max=10"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+}
+
+type UpdateAddressRequest struct {
+	Label             *string `json:"label" validate:"omitempty,max=50"`
+	Recipient         string  `json:"recipient" validate:"omitempty,max=100"`
+	Phone             string  `json:"phone" validate:"omitempty,max=20"`
+	Line1             string  `json:"line1" validate:"omitempty,max=255"`
+	Line2             *string `json:"line2" validate:"omitempty,max=255"`
+	SubDistrict       *string `json:"sub_district" validate:"omitempty,max=100"`
+	District          *string `json:"district" validate:"omitempty,max=100"`
+	Province          string  `json:"province" validate:"omitempty,max=100"`
+	PostalCode        string  `json:"postal_code" validate:"omitempty,max=10"`
+	Country           string  `json:"country" validate:"omitempty,len=2"`
+	RegionCode        *string `json:"region_code" validate:"omitempty,max=10"`
+	IsDefaultShipping *bool   `json:"is_default_shipping"`
+	IsDefaultBilling  *bool   `json:"is_default_billing"`
+}
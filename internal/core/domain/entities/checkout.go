@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// PaymentDeadlineSettings กำหนดเวลาชำระเงินของคำสั่งซื้อใหม่
+type PaymentDeadlineSettings struct {
+	// Timeout เวลาที่คำสั่งซื้อถือสต็อกไว้รอชำระเงิน ก่อนถูกยกเลิกอัตโนมัติ (0 คือไม่มีกำหนด)
+	Timeout time.Duration
+	// DeferredMethods วิธีชำระเงินแบบเก็บเงินปลายทาง/ออฟไลน์ที่ไม่ถูกยกเลิกอัตโนมัติ
+	DeferredMethods []string
+}
+
+// DueAt เวลาที่ต้องชำระเงินของคำสั่งซื้อที่ใช้วิธีชำระเงินนี้ (zero คือไม่มีกำหนด)
+func (s PaymentDeadlineSettings) DueAt(paymentMethod string, now time.Time) time.Time {
+	if s.Timeout <= 0 {
+		return time.Time{}
+	}
+	for _, method := range s.DeferredMethods {
+		if strings.EqualFold(strings.TrimSpace(method), strings.TrimSpace(paymentMethod)) {
+			return time.Time{}
+		}
+	}
+	return now.Add(s.Timeout)
+}
+
+// Checkout ค่าที่ service กำหนดให้ตอนสร้างคำสั่งซื้อ
+type Checkout struct {
+	// Pricing สกุลเงินและอัตราแลกเปลี่ยนที่ตรึงไว้กับคำสั่งซื้อ
+	Pricing PriceContext
+	// Tax อัตราภาษีของภูมิภาคที่จัดส่งและโหมดราคารวม/ไม่รวมภาษี
+	Tax TaxPolicy
+	// Shipping วิธีจัดส่งที่ลูกค้าเลือกพร้อมอัตราทั้งหมด ค่าจัดส่งคิดจากน้ำหนักและยอดสินค้าตอนสั่งซื้อ
+	Shipping *ShippingMethod
+	// ShippingRegion ภูมิภาคที่จัดส่ง ใช้เลือกโซนของอัตราค่าจัดส่ง
+	ShippingRegion string
+	// ShippingAddress และ BillingAddress ที่อยู่จากสมุดที่อยู่ที่จะบันทึกสำเนาไว้กับคำสั่งซื้อ (nil คือไม่มี)
+	ShippingAddress *Address
+	BillingAddress  *Address
+	// PaymentDueAt เวลาที่ต้องชำระเงินก่อนถูกยกเลิกอัตโนมัติ (zero คือไม่มีกำหนด)
+	PaymentDueAt time.Time
+}
@@ -0,0 +1,333 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ประเภทส่วนลดของคูปอง
+const (
+	CouponTypePercentage = "percentage"
+	CouponTypeFixed      = "fixed"
+)
+
+var (
+	// ErrCouponNotApplicable ใช้คูปองกับตะกร้านี้ไม่ได้ (หมดอายุ ยอดไม่ถึงขั้นต่ำ หรือไม่มีสินค้าที่ร่วมรายการ)
+	ErrCouponNotApplicable = errors.New("ไม่สามารถใช้คูปองนี้ได้")
+	// ErrCouponUsageLimit คูปองถูกใช้ครบจำนวนแล้ว (รวมทั้งหมดหรือต่อผู้ใช้)
+	ErrCouponUsageLimit = errors.New("คูปองนี้ถูกใช้ครบจำนวนแล้ว")
+	// ErrInvalidCoupon ข้อมูลคูปองไม่ถูกต้อง
+	ErrInvalidCoupon = errors.New("ข้อมูลคูปองไม่ถูกต้อง")
+)
+
+// Coupon คูปองส่วนลดหรือส่วนลดอัตโนมัติ
+// Amount, MinSpend และ MaxDiscount เป็นสกุลเงินหลักของร้าน และแปลงตามอัตราแลกเปลี่ยนของตะกร้า
+// ส่วนลดคิดจากสินค้าที่ร่วมรายการเท่านั้น (ProductIDs หรือ CategoryIDs ว่างทั้งคู่คือสินค้าทั้งหมด)
+type Coupon struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Type        string    `json:"type"`
+	// Percentage ใช้กับ type percentage ส่วน Amount ใช้กับ type fixed
+	Percentage Percent `json:"percentage"`
+	Amount     Money   `json:"amount"`
+	// MaxDiscount เพดานส่วนลดของ type percentage (0 คือไม่จำกัด)
+	MaxDiscount Money `json:"max_discount"`
+	// MinSpend ยอดขั้นต่ำของสินค้าที่ร่วมรายการ
+	MinSpend Money `json:"min_spend"`
+	// UsageLimit และ PerUserLimit จำนวนครั้งที่ใช้ได้ทั้งหมดและต่อผู้ใช้ (0 คือไม่จำกัด)
+	UsageLimit   int        `json:"usage_limit"`
+	PerUserLimit int        `json:"per_user_limit"`
+	UsedCount    int        `json:"used_count"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	Active       bool       `json:"active"`
+	// Automatic ส่วนลดที่ใช้กับตะกร้าโดยอัตโนมัติ ลูกค้าไม่ต้องกรอกรหัส
+	Automatic   bool        `json:"automatic"`
+	ProductIDs  []uuid.UUID `json:"product_ids"`
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type CreateCouponRequest struct {
+	Code         string      `json:"code" validate:"required,min=3,max=50"`
+	Description  string      `json:"description" validate:"max=255"`
+	Type         string      `json:"type" validate:"required,oneof=percentage fixed"`
+	Percentage   Percent     `json:"percentage" validate:"min=0,max=1000000"`
+	Amount       Money       `json:"amount" validate:"min=0"`
+	MaxDiscount  Money       `json:"max_discount" validate:"min=0"`
+	MinSpend     Money       `json:"min_spend" validate:"min=0"`
+	UsageLimit   int         `json:"usage_limit" validate:"min=0"`
+	PerUserLimit int         `json:"per_user_limit" validate:"min=0"`
+	StartsAt     *time.Time  `json:"starts_at"`
+	EndsAt       *time.Time  `json:"ends_at"`
+	Active       *bool       `json:"active"`
+	Automatic    bool        `json:"automatic"`
+	ProductIDs   []uuid.UUID `json:"product_ids"`
+	CategoryIDs  []uuid.UUID `json:"category_ids"`
+}
+
+// UpdateCouponRequest ฟิลด์ที่ไม่ส่งจะไม่เปลี่ยน ส่ง product_ids/category_ids เป็นอาร์เรย์ว่างเพื่อใช้กับสินค้าทั้งหมด
+type UpdateCouponRequest struct {
+	Description  *string      `json:"description" validate:"omitempty,max=255"`
+	Type         string       `json:"type" validate:"omitempty,oneof=percentage fixed"`
+	Percentage   *Percent     `json:"percentage" validate:"omitempty,min=0,max=1000000"`
+	Amount       *Money       `json:"amount" validate:"omitempty,min=0"`
+	MaxDiscount  *Money       `json:"max_discount" validate:"omitempty,min=0"`
+	MinSpend     *Money       `json:"min_spend" validate:"omitempty,min=0"`
+	UsageLimit   *int         `json:"usage_limit" validate:"omitempty,min=0"`
+	PerUserLimit *int         `json:"per_user_limit" validate:"omitempty,min=0"`
+	StartsAt     *time.Time   `json:"starts_at"`
+	EndsAt       *time.Time   `json:"ends_at"`
+	Active       *bool        `json:"active"`
+	Automatic    *bool        `json:"automatic"`
+	ProductIDs   *[]uuid.UUID `json:"product_ids"`
+	CategoryIDs  *[]uuid.UUID `json:"category_ids"`
+}
+
+// ApplyCouponRequest ใช้รหัสคูปองกับตะกร้า
+type ApplyCouponRequest struct {
+	Code string `json:"code" validate:"required,max=50"`
+}
+
+// NormalizeCouponCode รหัสคูปองไม่สนตัวพิมพ์เล็กใหญ่และช่องว่างหัวท้าย
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate ตรวจความสอดคล้องของค่าส่วนลดและช่วงเวลา
+func (c *Coupon) Validate() error {
+	switch c.Type {
+	case CouponTypePercentage:
+		if c.Percentage <= 0 || c.Percentage > 100*percentUnit {
+			return fmt.Errorf("%w: percentage ต้องมากกว่า 0 และไม่เกิน 100", ErrInvalidCoupon)
+		}
+	case CouponTypeFixed:
+		if !c.Amount.IsPositive() {
+			return fmt.Errorf("%w: amount ต้องมากกว่า 0", ErrInvalidCoupon)
+		}
+	default:
+		return fmt.Errorf("%w: type ต้องเป็น percentage หรือ fixed", ErrInvalidCoupon)
+	}
+
+	if c.StartsAt != nil && c.EndsAt != nil && !c.EndsAt.After(*c.StartsAt) {
+		return fmt.Errorf("%w: ends_at ต้องอยู่หลัง starts_at", ErrInvalidCoupon)
+	}
+	return nil
+}
+
+// Available ตรวจว่าคูปองเปิดใช้และอยู่ในช่วงเวลาที่ใช้ได้
+func (c *Coupon) Available(now time.Time) error {
+	switch {
+	case !c.Active:
+		return fmt.Errorf("%w: คูปองถูกปิดใช้งาน", ErrCouponNotApplicable)
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		return fmt.Errorf("%w: คูปองยังไม่เริ่มใช้งาน", ErrCouponNotApplicable)
+	case c.EndsAt != nil && !now.Before(*c.EndsAt):
+		return fmt.Errorf("%w: คูปองหมดอายุแล้ว", ErrCouponNotApplicable)
+	}
+	return nil
+}
+
+// CheckUsage ตรวจจำนวนครั้งที่ใช้ไปแล้วทั้งหมดและของผู้ใช้คนนี้
+func (c *Coupon) CheckUsage(usedByUser int) error {
+	if c.UsageLimit > 0 && c.UsedCount >= c.UsageLimit {
+		return ErrCouponUsageLimit
+	}
+	if c.PerUserLimit > 0 && usedByUser >= c.PerUserLimit {
+		return fmt.Errorf("%w: ใช้ได้ %d ครั้งต่อผู้ใช้", ErrCouponUsageLimit, c.PerUserLimit)
+	}
+	return nil
+}
+
+// Covers ตรวจว่าสินค้าร่วมรายการของคูปองหรือไม่
+func (c *Coupon) Covers(productID, categoryID uuid.UUID) bool {
+	if len(c.ProductIDs) == 0 && len(c.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ProductIDs {
+		if id == productID {
+			return true
+		}
+	}
+	for _, id := range c.CategoryIDs {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscountLine รายการสินค้าที่นำมาคิดส่วนลด
+type DiscountLine struct {
+	ItemID     uuid.UUID
+	ProductID  uuid.UUID
+	CategoryID uuid.UUID
+	Total      Money
+}
+
+// LineDiscount ส่วนลดที่แบ่งลงรายการสินค้าหนึ่งบรรทัด
+type LineDiscount struct {
+	ItemID    uuid.UUID `json:"item_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Amount    Money     `json:"amount"`
+}
+
+// AppliedDiscount ส่วนลดจากคูปองหนึ่งใบ พร้อมการแบ่งลงแต่ละรายการ
+type AppliedDiscount struct {
+	CouponID    uuid.UUID      `json:"coupon_id"`
+	Code        string         `json:"code"`
+	Description string         `json:"description"`
+	Automatic   bool           `json:"automatic"`
+	Amount      Money          `json:"amount"`
+	Lines       []LineDiscount `json:"lines"`
+}
+
+// DiscountResult ส่วนลดทั้งหมดของตะกร้าหรือคำสั่งซื้อ
+type DiscountResult struct {
+	Discounts []AppliedDiscount
+	Total     Money
+	// CouponErr เหตุผลที่ใช้คูปองที่ลูกค้ากรอกไม่ได้ (nil คือใช้ได้หรือไม่ได้กรอก)
+	CouponErr error
+}
+
+// ByItem ส่วนลดรวมของแต่ละรายการสินค้า
+func (r DiscountResult) ByItem() map[uuid.UUID]Money {
+	byItem := make(map[uuid.UUID]Money)
+	for _, discount := range r.Discounts {
+		for _, line := range discount.Lines {
+			byItem[line.ItemID] = byItem[line.ItemID].Add(line.Amount)
+		}
+	}
+	return byItem
+}
+
+// Discount คิดส่วนลดของคูปองจากรายการสินค้า คืน error หากใช้กับรายการเหล่านี้ไม่ได้
+func (c *Coupon) Discount(lines []DiscountLine, pricing PriceContext) (AppliedDiscount, error) {
+	applied := AppliedDiscount{
+		CouponID:    c.ID,
+		Code:        c.Code,
+		Description: c.Description,
+		Automatic:   c.Automatic,
+	}
+
+	weights := make([]Money, len(lines))
+	var eligible Money
+	for i, line := range lines {
+		if line.Total.IsPositive() && c.Covers(line.ProductID, line.CategoryID) {
+			weights[i] = line.Total
+			eligible = eligible.Add(line.Total)
+		}
+	}
+	if !eligible.IsPositive() {
+		return applied, fmt.Errorf("%w: ไม่มีสินค้าในตะกร้าที่ร่วมรายการ", ErrCouponNotApplicable)
+	}
+
+	minSpend := pricing.convertBase(c.MinSpend)
+	if eligible < minSpend {
+		return applied, fmt.Errorf("%w: ยอดสินค้าที่ร่วมรายการขั้นต่ำ %s %s", ErrCouponNotApplicable, minSpend, pricing.Currency)
+	}
+
+	var amount Money
+	if c.Type == CouponTypePercentage {
+		amount = eligible.MulRatio(int64(c.Percentage), 100*percentUnit).RoundTo(pricing.Currency)
+		if maxDiscount := pricing.convertBase(c.MaxDiscount); maxDiscount.IsPositive() && amount > maxDiscount {
+			amount = maxDiscount
+		}
+	} else {
+		amount = pricing.convertBase(c.Amount)
+	}
+	if amount > eligible {
+		amount = eligible
+	}
+
+	applied.Amount = amount
+	for i, share := range amount.AllocateIn(pricing.Currency, weights) {
+		if share.IsPositive() {
+			applied.Lines = append(applied.Lines, LineDiscount{
+				ItemID:    lines[i].ItemID,
+				ProductID: lines[i].ProductID,
+				Amount:    share,
+			})
+		}
+	}
+	return applied, nil
+}
+
+// ApplyDiscounts คิดส่วนลดอัตโนมัติที่ดีที่สุดหนึ่งรายการก่อน แล้วจึงคิดคูปองที่ลูกค้ากรอกจากยอดที่เหลือ
+// usedByUser คือจำนวนครั้งที่ผู้ใช้ใช้คูปองแต่ละใบไปแล้ว (คีย์คือ Coupon ID)
+func ApplyDiscounts(lines []DiscountLine, automatic []*Coupon, coupon *Coupon, usedByUser map[uuid.UUID]int, pricing PriceContext, now time.Time) DiscountResult {
+	var result DiscountResult
+	remaining := append([]DiscountLine(nil), lines...)
+
+	var best *AppliedDiscount
+	for _, candidate := range automatic {
+		if candidate.Available(now) != nil || candidate.CheckUsage(usedByUser[candidate.ID]) != nil {
+			continue
+		}
+		applied, err := candidate.Discount(remaining, pricing)
+		if err != nil {
+			continue
+		}
+		if best == nil || applied.Amount > best.Amount {
+			best = &applied
+		}
+	}
+	if best != nil && best.Amount.IsPositive() {
+		result.add(*best, remaining)
+	}
+
+	if coupon != nil {
+		if err := coupon.Available(now); err != nil {
+			result.CouponErr = err
+		} else if err := coupon.CheckUsage(usedByUser[coupon.ID]); err != nil {
+			result.CouponErr = err
+		} else if applied, err := coupon.Discount(remaining, pricing); err != nil {
+			result.CouponErr = err
+		} else {
+			result.add(applied, remaining)
+		}
+	}
+
+	return result
+}
+
+// add บันทึกส่วนลดและหักออกจากยอดของแต่ละรายการ เพื่อให้ส่วนลดถัดไปคิดจากยอดที่เหลือ
+func (r *DiscountResult) add(applied AppliedDiscount, remaining []DiscountLine) {
+	r.Discounts = append(r.Discounts, applied)
+	r.Total = r.Total.Add(applied.Amount)
+	for _, line := range applied.Lines {
+		for i := range remaining {
+			if remaining[i].ItemID == line.ItemID {
+				remaining[i].Total = remaining[i].Total.Sub(line.Amount)
+			}
+		}
+	}
+}
+
+// convertBase แปลงจำนวนเงินในสกุลเงินหลักของร้านเป็นสกุลเงินของ PriceContext
+// แล้วปัดตามหน่วยย่อยของสกุลปลายทาง
+func (pc PriceContext) convertBase(m Money) Money {
+	if pc.Currency == pc.BaseCurrency || pc.ExchangeRate == 0 {
+		return m
+	}
+	return pc.ExchangeRate.Convert(m).RoundTo(pc.Currency)
+}
+
+// CouponRedemption การใช้คูปองกับคำสั่งซื้อ ถูกปล่อยคืนเมื่อคำสั่งซื้อถูกยกเลิก
+type CouponRedemption struct {
+	ID         uuid.UUID  `json:"id"`
+	CouponID   uuid.UUID  `json:"coupon_id"`
+	Code       string     `json:"code"`
+	UserID     uuid.UUID  `json:"user_id"`
+	OrderID    uuid.UUID  `json:"order_id"`
+	Amount     Money      `json:"amount"`
+	Currency   Currency   `json:"currency"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
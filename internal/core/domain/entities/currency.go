@@ -0,0 +1,175 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnsupportedCurrency ไม่มีอัตราแลกเปลี่ยนสำหรับสกุลเงินนี้
+var ErrUnsupportedCurrency = errors.New("ไม่รองรับสกุลเงินนี้")
+
+// ErrCartCurrencyChanged สกุลเงินของตะกร้าเปลี่ยนระหว่างสั่งซื้อ
+var ErrCartCurrencyChanged = errors.New("สกุลเงินของตะกร้าเปลี่ยนระหว่างสั่งซื้อ กรุณาลองใหม่อีกครั้ง")
+
+// ParseCurrency แปลงรหัสสกุลเงินเป็นตัวพิมพ์ใหญ่และตรวจรูปแบบ 3 ตัวอักษร
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrUnsupportedCurrency
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return "", ErrUnsupportedCurrency
+		}
+	}
+	return Currency(code), nil
+}
+
+// ExchangeRateScale จำนวนหลักทศนิยมของอัตราแลกเปลี่ยน ตรงกับคอลัมน์ decimal(18,6)
+const ExchangeRateScale = 6
+
+const rateUnit = 1000000
+
+// ExchangeRate อัตราแลกเปลี่ยน 1 หน่วยสกุลหลักเป็นสกุลปลายทาง เก็บเป็นจำนวนเต็มคูณ 10^6
+type ExchangeRate int64
+
+// IdentityRate อัตราแลกเปลี่ยนของสกุลเงินเดียวกัน
+const IdentityRate ExchangeRate = rateUnit
+
+// ParseExchangeRate แปลงสตริงทศนิยม เช่น "0.027500" เป็น ExchangeRate
+func ParseExchangeRate(s string) (ExchangeRate, error) {
+	value, err := parseDecimal(s, ExchangeRateScale)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("อัตราแลกเปลี่ยนไม่ถูกต้อง: %q", s)
+	}
+	return ExchangeRate(value), nil
+}
+
+// Convert แปลงจำนวนเงินสกุลหลักเป็นสกุลปลายทาง ปัดเศษตามกฎของ Money
+func (r ExchangeRate) Convert(m Money) Money {
+	return Money(roundHalfUp(int64(m), int64(r), rateUnit))
+}
+
+// CrossRate อัตราจากสกุล A เป็นสกุล B เมื่อรู้อัตราของทั้งสองสกุลเทียบกับสกุลอ้างอิงเดียวกัน
+func CrossRate(baseRate, quoteRate ExchangeRate) ExchangeRate {
+	return ExchangeRate(roundHalfUp(int64(quoteRate), rateUnit, int64(baseRate)))
+}
+
+func (r ExchangeRate) String() string {
+	return formatDecimal(int64(r), ExchangeRateScale)
+}
+
+func (r ExchangeRate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *ExchangeRate) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseExchangeRate(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r ExchangeRate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (r *ExchangeRate) Scan(value interface{}) error {
+	if value == nil {
+		*r = IdentityRate
+		return nil
+	}
+
+	parsed, err := scanDecimal(value, ExchangeRateScale)
+	if err != nil {
+		return err
+	}
+	*r = ExchangeRate(parsed)
+	return nil
+}
+
+// PriceContext สกุลเงินและอัตราแลกเปลี่ยนที่ใช้คิดราคาในตะกร้าหรือคำสั่งซื้อ
+type PriceContext struct {
+	Currency     Currency
+	BaseCurrency Currency
+	// ExchangeRate 1 หน่วย BaseCurrency เท่ากับกี่หน่วย Currency
+	ExchangeRate ExchangeRate
+}
+
+// BasePriceContext คิดราคาด้วยสกุลเงินหลักของร้าน
+func BasePriceContext() PriceContext {
+	return PriceContext{
+		Currency:     DefaultCurrency,
+		BaseCurrency: DefaultCurrency,
+		ExchangeRate: IdentityRate,
+	}
+}
+
+// PriceOf ราคาต่อหน่วยของสินค้าในสกุลเงินนี้
+// ใช้ราคาที่ตั้งไว้ในรายการราคาของสกุลนั้นก่อน หากไม่มีจึงแปลงจากราคาหลักด้วยอัตราแลกเปลี่ยน
+func (pc PriceContext) PriceOf(product *Product) Money {
+	for _, price := range product.Prices {
+		if price.Currency == pc.Currency {
+			return price.Price
+		}
+	}
+	// ราคาหลักของสินค้าเป็นสกุลเงินหลักของร้านเสมอ
+	if pc.Currency == pc.BaseCurrency || pc.Currency == product.Currency {
+		return product.Price
+	}
+	return pc.ExchangeRate.Convert(product.Price).RoundTo(pc.Currency)
+}
+
+// ProductPrice ราคาสินค้าที่กำหนดเองสำหรับสกุลเงินหนึ่ง (แทนการแปลงจากอัตราแลกเปลี่ยน)
+type ProductPrice struct {
+	ID        uuid.UUID `json:"id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Currency  Currency  `json:"currency"`
+	Price     Money     `json:"price"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductPriceRequest ราคาของสินค้าในสกุลเงินหนึ่ง
+type ProductPriceRequest struct {
+	Currency Currency `json:"currency" validate:"required,len=3"`
+	Price    Money    `json:"price" validate:"required,gt=0"`
+}
+
+// SetCartCurrencyRequest เปลี่ยนสกุลเงินของตะกร้า ราคาสินค้าทุกรายการจะถูกคิดใหม่
+type SetCartCurrencyRequest struct {
+	Currency Currency `json:"currency" validate:"required,len=3"`
+}
+
+// ErrInvalidPriceList รายการราคาแยกตามสกุลเงินไม่ถูกต้อง
+var ErrInvalidPriceList = errors.New("รายการราคาแยกตามสกุลเงินไม่ถูกต้อง")
+
+// NormalizePrices ตรวจรหัสสกุลเงิน ห้ามซ้ำ และห้ามกำหนดราคาในสกุลเงินหลัก (ใช้ Price แทน)
+func NormalizePrices(prices []ProductPriceRequest) error {
+	seen := make(map[Currency]bool, len(prices))
+	for i := range prices {
+		currency, err := ParseCurrency(string(prices[i].Currency))
+		if err != nil {
+			return fmt.Errorf("%w: สกุลเงิน %q", ErrInvalidPriceList, prices[i].Currency)
+		}
+		if currency == DefaultCurrency {
+			return fmt.Errorf("%w: ราคา %s ให้กำหนดที่ price", ErrInvalidPriceList, currency)
+		}
+		if seen[currency] {
+			return fmt.Errorf("%w: สกุลเงิน %s ซ้ำ", ErrInvalidPriceList, currency)
+		}
+		if prices[i].Price != prices[i].Price.RoundTo(currency) {
+			return fmt.Errorf("%w: ราคา %s ต้องมีทศนิยมไม่เกิน %d ตำแหน่ง", ErrInvalidPriceList, currency, currency.MinorUnits())
+		}
+		seen[currency] = true
+		prices[i].Currency = currency
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ภาษาของอีเมลที่รองรับ
+const (
+	LocaleThai    = "th"
+	LocaleEnglish = "en"
+	DefaultLocale = LocaleThai
+)
+
+// NormalizeLocale ภาษาที่ไม่รองรับจะใช้ภาษาเริ่มต้น เช่น "EN-us" คือ "en"
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	switch locale {
+	case LocaleThai, LocaleEnglish:
+		return locale
+	default:
+		return DefaultLocale
+	}
+}
+
+// ชื่อเทมเพลตอีเมล (แต่ละเทมเพลตมีทั้ง HTML และข้อความล้วนในทุกภาษา)
+const (
+	EmailPasswordReset     = "password_reset"
+	EmailVerification      = "email_verification"
+	EmailOrderConfirmation = "order_confirmation"
+	EmailShippingUpdate    = "shipping_update"
+	EmailPaymentReceipt    = "payment_receipt"
+	EmailBackInStock       = "back_in_stock"
+	EmailChangeNotice      = "email_change_notice"
+)
+
+// PasswordResetTTL อายุของ token รีเซ็ตรหัสผ่าน
+const PasswordResetTTL = 24 * time.Hour
+
+// ErrMailQueueFull คิวส่งอีเมลเต็ม
+var ErrMailQueueFull = errors.New("mail queue is full")
+
+// Email อีเมลที่ต้องการส่ง เนื้อหาสร้างจากเทมเพลต Template ในภาษา Locale ด้วยข้อมูล Data
+type Email struct {
+	To       string
+	Locale   string
+	Template string
+	Data     interface{}
+}
+
+// ข้อมูลสำหรับเทมเพลตอีเมลแต่ละแบบ
+
+type PasswordResetEmail struct {
+	Name           string
+	ResetURL       string
+	ExpiresInHours int
+}
+
+// VerificationEmail ใช้ทั้งยืนยันอีเมลตอนสมัครและยืนยันอีเมลใหม่ (EmailChange)
+type VerificationEmail struct {
+	Name           string
+	Email          string
+	VerifyURL      string
+	ExpiresInHours int
+	EmailChange    bool
+}
+
+// EmailChangeNoticeEmail แจ้งเตือนไปยังอีเมลเดิมเมื่อมีการขอเปลี่ยนอีเมล
+type EmailChangeNoticeEmail struct {
+	Name     string
+	NewEmail string
+}
+
+type OrderEmail struct {
+	Name     string
+	Order    *Order
+	OrderURL string
+}
+
+type ShippingUpdateEmail struct {
+	Name     string
+	Order    *Order
+	Status   string
+	OrderURL string
+}
+
+type PaymentReceiptEmail struct {
+	Name     string
+	Order    *Order
+	Payment  PaymentEventPayload
+	OrderURL string
+	PaidAt   time.Time
+}
+
+type BackInStockEmail struct {
+	Name        string
+	ProductName string
+	ProductURL  string
+}
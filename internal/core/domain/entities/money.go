@@ -0,0 +1,369 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency รหัสสกุลเงินตาม ISO 4217
+type Currency string
+
+const (
+	CurrencyTHB Currency = "THB"
+
+	// DefaultCurrency สกุลเงินหลักของร้าน ใช้กับราคาและคำสั่งซื้อทั้งหมด
+	DefaultCurrency = CurrencyTHB
+)
+
+// zeroDecimalCurrencies สกุลเงินที่ไม่มีหน่วยย่อยในการชำระเงินจริง
+var zeroDecimalCurrencies = map[Currency]bool{
+	"JPY": true,
+	"KHR": true,
+	"KRW": true,
+	"VND": true,
+}
+
+// MinorUnits จำนวนหลักทศนิยมที่ใช้ได้จริงของสกุลเงิน (ไม่เกิน MoneyScale)
+func (c Currency) MinorUnits() int {
+	if zeroDecimalCurrencies[c] {
+		return 0
+	}
+	return MoneyScale
+}
+
+// minorStep จำนวนหน่วยย่อยของ Money ต่อหนึ่งหน่วยย่อยของสกุลเงิน เช่น VND คือ 100
+func (c Currency) minorStep() int64 {
+	step := int64(1)
+	for i := c.MinorUnits(); i < MoneyScale; i++ {
+		step *= 10
+	}
+	return step
+}
+
+// MoneyScale จำนวนหลักทศนิยมของจำนวนเงิน ตรงกับคอลัมน์ numeric(18,2) ในฐานข้อมูล
+// สกุลเงินที่มีหน่วยย่อยน้อยกว่านี้ใช้ RoundTo ปัดให้ตรงกับ MinorUnits ของสกุลนั้น
+const MoneyScale = 2
+
+const minorPerMajor = 100
+
+// ErrInvalidMoney รูปแบบจำนวนเงินไม่ถูกต้อง
+var ErrInvalidMoney = errors.New("รูปแบบจำนวนเงินไม่ถูกต้อง")
+
+// Money จำนวนเงินในหน่วยย่อย (สตางค์) เก็บเป็นจำนวนเต็มเพื่อให้บวกลบได้ตรงทุกสตางค์
+//
+// กฎการปัดเศษมีที่เดียวคือ roundHalfUp: ทศนิยมที่เกิน MoneyScale หลัก
+// (จากฐานข้อมูล JSON หรือการคูณด้วยอัตรา) ปัดครึ่งขึ้นโดยปัดออกจากศูนย์
+// ยอดรวมคำนวณจาก LineTotal ของแต่ละรายการแล้วบวกกันเสมอ จึงตรงกับรายการย่อยทุกครั้ง
+type Money int64
+
+// MoneyFromMajor สร้างจำนวนเงินจากหน่วยหลัก เช่น MoneyFromMajor(100) คือ 100.00 บาท
+func MoneyFromMajor(units int64) Money {
+	return Money(units * minorPerMajor)
+}
+
+// ParseMoney แปลงสตริงทศนิยม เช่น "1290.50" เป็น Money โดยไม่ผ่าน float
+func ParseMoney(s string) (Money, error) {
+	value, err := parseDecimal(s, MoneyScale)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	return Money(value), nil
+}
+
+// parseDecimal แปลงสตริงทศนิยมเป็นจำนวนเต็มที่คูณด้วย 10^scale แล้ว
+// หลักที่เกิน scale ปัดครึ่งขึ้นตามกฎเดียวกับ roundHalfUp
+func parseDecimal(s string, scale int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidMoney
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	// รองรับรูปแบบ exponent จาก JSON number (เช่น 1e3) ด้วยการแปลงเป็นทศนิยมก่อน
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, ErrInvalidMoney
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, ErrInvalidMoney
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidMoney
+	}
+
+	// scale หลักแรกของทศนิยมเก็บไว้ หลักถัดไปใช้ตัดสินการปัดเศษ
+	padded := frac + strings.Repeat("0", scale+1)
+	value, err := strconv.ParseInt(whole+padded[:scale], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidMoney
+	}
+	if padded[scale] >= '5' {
+		value++
+	}
+
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// roundHalfUp คูณ value ด้วย numerator/denominator แล้วปัดครึ่งขึ้น (ออกจากศูนย์)
+// เป็นกฎการปัดเศษเดียวของระบบ ใช้ big.Int เพื่อไม่ให้ผลคูณระหว่างทางล้น int64
+func roundHalfUp(value, numerator, denominator int64) int64 {
+	n := new(big.Int).Mul(big.NewInt(value), big.NewInt(numerator))
+	d := big.NewInt(denominator)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+
+	negative := n.Sign() < 0
+	n.Abs(n)
+
+	// (2n + d) / 2d คือ n/d ปัดครึ่งขึ้น
+	n.Mul(n, big.NewInt(2)).Add(n, d)
+	n.Quo(n, d.Mul(d, big.NewInt(2)))
+	if negative {
+		n.Neg(n)
+	}
+	return n.Int64()
+}
+
+// Add บวกจำนวนเงิน
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Sub ลบจำนวนเงิน
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+// Mul คูณด้วยจำนวนชิ้น
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// MulRatio คูณด้วยอัตราส่วน numerator/denominator แล้วปัดเศษตามกฎของ Money
+// ใช้กับเปอร์เซ็นต์ภาษีหรือส่วนลด เช่น MulRatio(7, 100)
+func (m Money) MulRatio(numerator, denominator int64) Money {
+	if denominator == 0 {
+		return 0
+	}
+	return Money(roundHalfUp(int64(m), numerator, denominator))
+}
+
+// IsZero ตรวจสอบว่าเป็นศูนย์หรือไม่
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// IsPositive ตรวจสอบว่ามากกว่าศูนย์หรือไม่
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+// RoundTo ปัดจำนวนเงินให้เหลือทศนิยมตาม MinorUnits ของสกุลเงิน ด้วยกฎเดียวกับ roundHalfUp
+// เช่น 1234.56 VND เป็น 1235.00
+func (m Money) RoundTo(c Currency) Money {
+	step := c.minorStep()
+	if step == 1 {
+		return m
+	}
+	return Money(roundHalfUp(int64(m), 1, step) * step)
+}
+
+// Minor จำนวนเงินในหน่วยย่อยของสกุลเงิน (สตางค์ เซนต์ หรือดองสำหรับ VND) สำหรับ gateway ที่รับหน่วยย่อย
+func (m Money) Minor(c Currency) int64 {
+	return int64(m.RoundTo(c)) / c.minorStep()
+}
+
+// String แสดงเป็นทศนิยมสองตำแหน่ง เช่น "1290.50"
+func (m Money) String() string {
+	return formatDecimal(int64(m), MoneyScale)
+}
+
+// MarshalJSON ส่งออกเป็น JSON number ทศนิยมสองตำแหน่ง (รูปแบบเดิมของ API)
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON รับได้ทั้ง JSON number และ string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*m = 0
+		return nil
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value บันทึกลงคอลัมน์ decimal เป็นสตริงทศนิยม เพื่อไม่ให้เกิดความคลาดเคลื่อนของ float
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan อ่านค่าจากคอลัมน์ decimal หรือผลรวม/ค่าเฉลี่ยจาก SQL
+func (m *Money) Scan(value interface{}) error {
+	parsed, err := scanDecimal(value, MoneyScale)
+	if err != nil {
+		return err
+	}
+	*m = Money(parsed)
+	return nil
+}
+
+// formatDecimal แสดงจำนวนเต็มที่คูณ 10^scale ไว้เป็นสตริงทศนิยม scale ตำแหน่ง
+func formatDecimal(value int64, scale int) string {
+	unit := int64(1)
+	for i := 0; i < scale; i++ {
+		unit *= 10
+	}
+
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, value/unit, scale, value%unit)
+}
+
+// scanDecimal อ่านค่าทศนิยมจากฐานข้อมูลเป็นจำนวนเต็มที่คูณ 10^scale (NULL เป็น 0)
+func scanDecimal(value interface{}, scale int) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return parseDecimal(v, scale)
+	case []byte:
+		return parseDecimal(string(v), scale)
+	case int64:
+		return parseDecimal(strconv.FormatInt(v, 10), scale)
+	case float64:
+		return parseDecimal(strconv.FormatFloat(v, 'f', -1, 64), scale)
+	default:
+		return 0, fmt.Errorf("ไม่สามารถแปลง %T เป็นทศนิยมได้", value)
+	}
+}
+
+// AllocateIn แบ่งแบบเดียวกับ Allocate แต่แบ่งทีละหน่วยย่อยของสกุลเงิน
+// ใช้กับจำนวนเงินที่ปัดด้วย RoundTo แล้ว เพื่อไม่ให้ส่วนใดมีทศนิยมที่สกุลเงินนั้นไม่มี
+func (m Money) AllocateIn(c Currency, weights []Money) []Money {
+	step := Money(c.minorStep())
+	shares := (m / step).Allocate(weights)
+	for i := range shares {
+		shares[i] *= step
+	}
+	return shares
+}
+
+// LineTotal ราคารวมของรายการสินค้าหนึ่งบรรทัด (ราคาต่อหน่วย x จำนวน)
+func LineTotal(price Money, quantity int) Money {
+	return price.Mul(quantity)
+}
+
+// Allocate แบ่ง m ตามสัดส่วนของ weights โดยผลรวมของส่วนที่แบ่งเท่ากับ m พอดีทุกสตางค์
+// แต่ละส่วนปัดลงก่อน แล้วแจกเศษที่เหลือทีละสตางค์ให้รายการที่เศษมากที่สุด
+// (ใช้แบ่งส่วนลดลงแต่ละรายการ จึงไม่มีส่วนใดเกินน้ำหนักของตัวเองเมื่อ m ไม่เกินผลรวมของ weights)
+func (m Money) Allocate(weights []Money) []Money {
+	shares := make([]Money, len(weights))
+	var total Money
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 || m <= 0 {
+		return shares
+	}
+
+	remainders := make([]*big.Int, len(weights))
+	var allocated Money
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		q, r := new(big.Int).QuoRem(
+			new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(w))),
+			big.NewInt(int64(total)),
+			new(big.Int),
+		)
+		shares[i] = Money(q.Int64())
+		remainders[i] = r
+		allocated += shares[i]
+	}
+
+	for left := m - allocated; left > 0; left-- {
+		best := -1
+		for i, r := range remainders {
+			if r != nil && (best < 0 || r.Cmp(remainders[best]) > 0) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		shares[best]++
+		remainders[best] = nil
+	}
+
+	return shares
+}
+
+// Amount จำนวนเงินพร้อมสกุลเงิน ใช้ส่งข้ามขอบเขตระบบ เช่น payment gateway
+type Amount struct {
+	Value    Money    `json:"value"`
+	Currency Currency `json:"currency"`
+}
+
+// NewAmount สร้าง Amount ในสกุลเงินที่กำหนด
+func NewAmount(value Money, currency Currency) Amount {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Amount{Value: value, Currency: currency}
+}
+
+// Equal ตรวจสอบว่าจำนวนเงินและสกุลเงินตรงกัน
+func (a Amount) Equal(other Amount) bool {
+	return a.Value == other.Value && a.Currency == other.Currency
+}
+
+func (a Amount) String() string {
+	return a.Value.String() + " " + string(a.Currency)
+}
@@ -0,0 +1,196 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// สถานะคำสั่งซื้อ
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusConfirmed  = "confirmed"
+	OrderStatusProcessing = "processing"
+	OrderStatusShipped    = "shipped"
+	OrderStatusDelivered  = "delivered"
+	OrderStatusCancelled  = "cancelled"
+)
+
+// สถานะการชำระเงินของคำสั่งซื้อ
+const (
+	PaymentStatusPending           = "pending"
+	PaymentStatusPaid              = "paid"
+	PaymentStatusFailed            = "failed"
+	PaymentStatusCancelled         = "cancelled"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+	PaymentStatusRefunded          = "refunded"
+)
+
+var (
+	// ErrPaymentNotPending การชำระเงินปิดไปแล้ว (สำเร็จ ล้มเหลว หรือถูกยกเลิก) จึงเปลี่ยนสถานะอีกไม่ได้ (ตอบกลับเป็น 409)
+	ErrPaymentNotPending = errors.New("การชำระเงินนี้ไม่ได้อยู่ระหว่างรอชำระแล้ว")
+	// ErrOrderNotPayable คำสั่งซื้อถูกยกเลิก หมดเวลาชำระ หรือชำระแล้ว จึงเปิดการชำระเงินใหม่ไม่ได้ (ตอบกลับเป็น 409)
+	ErrOrderNotPayable = errors.New("คำสั่งซื้อนี้ไม่อยู่ในสถานะที่ชำระเงินได้")
+)
+
+// Payable คำสั่งซื้อที่ยังรอดำเนินการและยังไม่ได้ชำระ (รอชำระหรือการชำระครั้งก่อนล้มเหลว) เปิดการชำระเงินได้
+func (o *Order) Payable() bool {
+	return o.Status == OrderStatusPending &&
+		(o.PaymentStatus == PaymentStatusPending || o.PaymentStatus == PaymentStatusFailed)
+}
+
+// สถานะการจัดส่ง
+const (
+	ShippingStatusPending          = "pending"
+	ShippingStatusProcessing       = "processing"
+	ShippingStatusPartiallyShipped = "partially_shipped"
+	ShippingStatusShipped          = "shipped"
+	ShippingStatusInTransit        = "in_transit"
+	ShippingStatusDelivered        = "delivered"
+	ShippingStatusReturned         = "returned"
+	ShippingStatusCancelled        = "cancelled"
+)
+
+// ชื่อฟิลด์สถานะที่ใช้ใน state machine และประวัติคำสั่งซื้อ
+const (
+	OrderFieldStatus         = "status"
+	OrderFieldPaymentStatus  = "payment_status"
+	OrderFieldShippingStatus = "shipping_status"
+	OrderFieldShipmentStatus = "shipment_status"
+)
+
+// StateMachine กำหนดสถานะปลายทางที่อนุญาตจากแต่ละสถานะ
+type StateMachine map[string][]string
+
+// Allows ตรวจสอบว่าเปลี่ยนจาก from ไป to ได้หรือไม่
+func (m StateMachine) Allows(from, to string) bool {
+	for _, next := range m[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Has ตรวจสอบว่าเป็นสถานะที่รู้จักหรือไม่
+func (m StateMachine) Has(state string) bool {
+	_, ok := m[state]
+	return ok
+}
+
+var OrderStatusMachine = StateMachine{
+	OrderStatusPending:    {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed:  {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered},
+	OrderStatusDelivered:  {},
+	OrderStatusCancelled:  {},
+}
+
+var PaymentStatusMachine = StateMachine{
+	PaymentStatusPending:           {PaymentStatusPaid, PaymentStatusFailed, PaymentStatusCancelled},
+	PaymentStatusFailed:            {PaymentStatusCancelled},
+	PaymentStatusPaid:              {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusRefunded},
+	PaymentStatusRefunded:          {},
+	PaymentStatusCancelled:         {},
+}
+
+// PaymentRetryMachine การเปิดการชำระเงินที่ล้มเหลวอีกครั้ง ทำได้เฉพาะตอนสร้างการชำระเงินใหม่
+// หรือเมื่อการชำระเงินที่ยังค้างอยู่สำเร็จ จึงแยกออกจาก PaymentStatusMachine ที่ใช้กับการเปลี่ยนสถานะทั่วไป
+var PaymentRetryMachine = StateMachine{
+	PaymentStatusFailed: {PaymentStatusPending},
+}
+
+var ShippingStatusMachine = StateMachine{
+	ShippingStatusPending:          {ShippingStatusProcessing, ShippingStatusPartiallyShipped, ShippingStatusShipped, ShippingStatusCancelled},
+	ShippingStatusProcessing:       {ShippingStatusPartiallyShipped, ShippingStatusShipped, ShippingStatusCancelled},
+	ShippingStatusPartiallyShipped: {ShippingStatusShipped, ShippingStatusDelivered},
+	ShippingStatusShipped:          {ShippingStatusInTransit, ShippingStatusDelivered, ShippingStatusReturned},
+	ShippingStatusInTransit:        {ShippingStatusDelivered, ShippingStatusReturned},
+	ShippingStatusDelivered:        {ShippingStatusReturned},
+	ShippingStatusReturned:         {},
+	ShippingStatusCancelled:        {},
+}
+
+// สถานะของพัสดุแต่ละชิ้น (คำสั่งซื้อหนึ่งแบ่งส่งได้หลายพัสดุ)
+const (
+	ShipmentStatusPending   = "pending"
+	ShipmentStatusShipped   = "shipped"
+	ShipmentStatusInTransit = "in_transit"
+	ShipmentStatusDelivered = "delivered"
+	ShipmentStatusReturned  = "returned"
+	ShipmentStatusCancelled = "cancelled"
+)
+
+var ShipmentStatusMachine = StateMachine{
+	ShipmentStatusPending:   {ShipmentStatusShipped, ShipmentStatusCancelled},
+	ShipmentStatusShipped:   {ShipmentStatusInTransit, ShipmentStatusDelivered, ShipmentStatusReturned},
+	ShipmentStatusInTransit: {ShipmentStatusDelivered, ShipmentStatusReturned},
+	ShipmentStatusDelivered: {ShipmentStatusReturned},
+	ShipmentStatusReturned:  {},
+	ShipmentStatusCancelled: {},
+}
+
+// OrderStateMachines state machine ของแต่ละฟิลด์สถานะ
+var OrderStateMachines = map[string]StateMachine{
+	OrderFieldStatus:         OrderStatusMachine,
+	OrderFieldPaymentStatus:  PaymentStatusMachine,
+	OrderFieldShippingStatus: ShippingStatusMachine,
+	OrderFieldShipmentStatus: ShipmentStatusMachine,
+}
+
+// ErrInvalidTransition ใช้กับ errors.Is เพื่อตรวจว่าเป็นการเปลี่ยนสถานะที่ไม่อนุญาต
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// InvalidTransitionError การเปลี่ยนสถานะที่ไม่อนุญาต (ตอบกลับเป็น 409)
+type InvalidTransitionError struct {
+	Field   string   `json:"field"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Allowed []string `json:"allowed"`
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("ไม่สามารถเปลี่ยน %s จาก %s เป็น %s ได้", e.Field, e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// ValidatePaymentRetry ตรวจสอบการเปิดการชำระเงินอีกครั้งตาม PaymentRetryMachine
+func ValidatePaymentRetry(from, to string) error {
+	if !PaymentRetryMachine.Allows(from, to) {
+		return &InvalidTransitionError{
+			Field:   OrderFieldPaymentStatus,
+			From:    from,
+			To:      to,
+			Allowed: append([]string{}, PaymentRetryMachine[from]...),
+		}
+	}
+	return nil
+}
+
+// ValidateOrderTransition ตรวจสอบการเปลี่ยนสถานะของฟิลด์ที่กำหนด
+// สถานะเดิมกับสถานะใหม่ที่เหมือนกันถือว่าไม่มีการเปลี่ยนแปลงและผ่านเสมอ
+func ValidateOrderTransition(field, from, to string) error {
+	machine, ok := OrderStateMachines[field]
+	if !ok {
+		return fmt.Errorf("unknown order status field %s", field)
+	}
+
+	if from == to && machine.Has(to) {
+		return nil
+	}
+
+	if !machine.Allows(from, to) {
+		return &InvalidTransitionError{
+			Field:   field,
+			From:    from,
+			To:      to,
+			Allowed: append([]string{}, machine[from]...),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package entities
+
+import "github.com/google/uuid"
+
+// สิทธิ์ที่ระบบรู้จัก ใช้กับ RequirePermission และ seed ลงตาราง permissions
+const (
+	PermUsersRead       = "users:read"
+	PermUsersWrite      = "users:write"
+	PermUsersDelete     = "users:delete"
+	PermRolesManage     = "roles:manage"
+	PermCategoriesWrite = "categories:write"
+	PermProductsWrite   = "products:write"
+	PermOrdersRead      = "orders:read"
+	PermOrdersUpdate    = "orders:update"
+	PermShipmentsWrite  = "shipments:write"
+	PermPaymentsRead    = "payments:read"
+	PermPaymentsRefund  = "payments:refund"
+	PermStatsRead       = "stats:read"
+	PermWebhooksManage  = "webhooks:manage"
+	PermTaxManage       = "tax:manage"
+	PermCouponsManage   = "coupons:manage"
+	PermShippingManage  = "shipping:manage"
+	PermCartsManage     = "carts:manage"
+)
+
+// PermissionCatalogue รายการสิทธิ์ทั้งหมดพร้อมคำอธิบาย สำหรับ seed ข้อมูลเริ่มต้น
+var PermissionCatalogue = []Permission{
+	{Name: PermUsersRead, Description: "ดูข้อมูลผู้ใช้ทั้งหมด"},
+	{Name: PermUsersWrite, Description: "แก้ไขข้อมูลผู้ใช้"},
+	{Name: PermUsersDelete, Description: "ลบผู้ใช้"},
+	{Name: PermRolesManage, Description: "จัดการบทบาท สิทธิ์ และการกำหนดบทบาทให้ผู้ใช้"},
+	{Name: PermCategoriesWrite, Description: "สร้าง แก้ไข และลบหมวดหมู่สินค้า"},
+	{Name: PermProductsWrite, Description: "สร้าง แก้ไข และลบสินค้า"},
+	{Name: PermOrdersRead, Description: "ดูคำสั่งซื้อของผู้ใช้ทุกคน"},
+	{Name: PermOrdersUpdate, Description: "เปลี่ยนสถานะคำสั่งซื้อ การชำระเงิน และการจัดส่ง"},
+	{Name: PermShipmentsWrite, Description: "สร้างและอัพเดทพัสดุ"},
+	{Name: PermPaymentsRead, Description: "ดูและตรวจสอบการชำระเงินของผู้ใช้ทุกคน"},
+	{Name: PermPaymentsRefund, Description: "คืนเงินให้ลูกค้า"},
+	{Name: PermStatsRead, Description: "ดูสถิติยอดขาย สินค้า และผู้ใช้"},
+	{Name: PermWebhooksManage, Description: "จัดการ webhook endpoint และการส่งซ้ำ"},
+	{Name: PermTaxManage, Description: "จัดการประเภทภาษีและอัตราภาษีแยกตามภูมิภาค"},
+	{Name: PermCouponsManage, Description: "จัดการคูปองและส่วนลดอัตโนมัติ"},
+	{Name: PermShippingManage, Description: "จัดการวิธีจัดส่งและอัตราค่าจัดส่ง"},
+	{Name: PermCartsManage, Description: "แก้ไขและลบสินค้าในตะกร้าของผู้ใช้ทุกคน"},
+}
+
+type CreateRoleRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description"`
+}
+
+type UpdateRoleRequest struct {
+	Name        string `json:"name" validate:"omitempty,min=2,max=100"`
+	Description string `json:"description"`
+}
+
+// SetRolePermissionsRequest กำหนดสิทธิ์ทั้งหมดของบทบาท (แทนที่ของเดิม)
+type SetRolePermissionsRequest struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+type CreatePermissionRequest struct {
+	Name        string `json:"name" validate:"required,min=3,max=100"`
+	Description string `json:"description"`
+}
+
+type UpdatePermissionRequest struct {
+	Description string `json:"description"`
+}
+
+// AssignRoleRequest กำหนดบทบาทให้ผู้ใช้
+type AssignRoleRequest struct {
+	RoleID uuid.UUID `json:"role_id" validate:"required"`
+}
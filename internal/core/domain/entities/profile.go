@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"errors"
+	"io"
+)
+
+// ขนาดสูงสุดเริ่มต้นของรูปโปรไฟล์ (2 MB)
+const DefaultAvatarMaxBytes = 2 << 20
+
+// AvatarContentTypes ชนิดไฟล์รูปโปรไฟล์ที่รับ และนามสกุลไฟล์ที่ใช้บันทึก
+var AvatarContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var (
+	// ErrEmailTaken อีเมลนี้ถูกใช้โดยบัญชีอื่นแล้ว
+	ErrEmailTaken = errors.New("อีเมลนี้มีอยู่ในระบบแล้ว")
+	// ErrIncorrectPassword รหัสผ่านปัจจุบันที่ใช้ยืนยันการทำรายการไม่ถูกต้อง
+	ErrIncorrectPassword = errors.New("รหัสผ่านไม่ถูกต้อง")
+	// ErrInvalidEmailChangeToken token เปลี่ยนอีเมลไม่ถูกต้องหรือหมดอายุแล้ว
+	ErrInvalidEmailChangeToken = errors.New("token เปลี่ยนอีเมลไม่ถูกต้องหรือหมดอายุแล้ว")
+	// ErrInvalidAvatar ไฟล์รูปโปรไฟล์ไม่ใช่ JPEG, PNG หรือ WebP
+	ErrInvalidAvatar = errors.New("รูปโปรไฟล์ต้องเป็นไฟล์ JPEG, PNG หรือ WebP")
+	// ErrAvatarTooLarge ไฟล์รูปโปรไฟล์ใหญ่เกินขนาดที่กำหนด
+	ErrAvatarTooLarge = errors.New("ไฟล์รูปโปรไฟล์มีขนาดใหญ่เกินไป")
+)
+
+// UpdateProfileRequest แก้ไขข้อมูลส่วนตัวของผู้ใช้เอง ฟิลด์ที่ไม่ส่งมาจะไม่ถูกเปลี่ยน
+// อีเมลเปลี่ยนผ่าน /me/email และรูปโปรไฟล์ผ่าน /me/avatar เท่านั้น
+type UpdateProfileRequest struct {
+	FirstName *string `json:"first_name" validate:"omitempty,min=1,max=100"`
+	LastName  *string `json:"last_name" validate:"omitempty,min=1,max=100"`
+	Phone     *string `json:"phone" validate:"omitempty,max=20"`
+	Address   *string `json:"address" validate:"omitempty,max=1000"`
+	Locale    *string `json:"locale" validate:"omitempty,oneof=th en"`
+}
+
+// ChangeEmailRequest ขอเปลี่ยนอีเมล ต้องยืนยันด้วยรหัสผ่านปัจจุบัน
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequest ยืนยันการเปลี่ยนอีเมลด้วย token ที่ส่งไปยังอีเมลใหม่
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// DeleteAccountRequest ลบบัญชีของตัวเอง ต้องยืนยันด้วยรหัสผ่านปัจจุบัน
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// AvatarUpload ไฟล์รูปโปรไฟล์ที่อัปโหลด Size คือขนาดที่ client แจ้ง ซึ่งจะถูกตรวจซ้ำระหว่างอ่านไฟล์
+type AvatarUpload struct {
+	Size   int64
+	Reader io.Reader
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultAccessTokenTTL อายุเริ่มต้นของ access token (JWT)
+	DefaultAccessTokenTTL = 24 * time.Hour
+	// DefaultRefreshTokenTTL อายุเริ่มต้นของ refresh token นับจากการใช้งานล่าสุด
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// ErrRefreshTokenReused refresh token ที่ถูกหมุนไปแล้วถูกนำกลับมาใช้ซ้ำ (อาจถูกขโมย) session ทั้งตระกูลถูกยกเลิก
+var ErrRefreshTokenReused = errors.New("refresh token นี้ถูกใช้ไปแล้ว ระบบได้ยกเลิก session นี้เพื่อความปลอดภัย กรุณาเข้าสู่ระบบใหม่")
+
+// RefreshTokenReusedError ErrRefreshTokenReused พร้อม session ที่ถูกยกเลิก ใช้ยกเลิก access token ของ session นั้นต่อ
+type RefreshTokenReusedError struct {
+	SessionID uuid.UUID
+}
+
+func (e *RefreshTokenReusedError) Error() string {
+	return ErrRefreshTokenReused.Error()
+}
+
+func (e *RefreshTokenReusedError) Is(target error) bool {
+	return target == ErrRefreshTokenReused
+}
+
+// ErrTokenRevoked access token ถูกยกเลิกก่อนหมดอายุ (ออกจากระบบ ปิดบัญชี เปลี่ยนบทบาท หรือรีเซ็ตรหัสผ่าน)
+var ErrTokenRevoked = errors.New("token ถูกยกเลิกแล้ว กรุณาเข้าสู่ระบบใหม่")
+
+// Session การเข้าสู่ระบบหนึ่งครั้งบนอุปกรณ์หนึ่ง refresh token ของ session ถูกหมุนและต่ออายุถึง ExpiresAt ทุกครั้งที่รีเฟรช
+// token ที่หมุนไปแล้วทั้งหมดอยู่ในตระกูลเดียวกัน การใช้ token เก่าซ้ำจะยกเลิกทั้ง session
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Current session ที่ใช้ทำรายการนี้อยู่
+	Current bool `json:"current"`
+}
+
+// TokenSettings อายุของ access token และ refresh token ที่ออกตอนเข้าสู่ระบบและรีเฟรช
+type TokenSettings struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// ClientInfo ข้อมูลอุปกรณ์ที่เข้าสู่ระบบหรือรีเฟรช token
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// RevokeSessionsResponse ผลการออกจากระบบทุกอุปกรณ์ของผู้ใช้
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
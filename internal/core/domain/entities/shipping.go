@@ -0,0 +1,217 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ประเภทของอัตราค่าจัดส่ง
+const (
+	// ShippingRateFlat ค่าจัดส่งคงที่ต่อคำสั่งซื้อ
+	ShippingRateFlat = "flat"
+	// ShippingRateWeight ค่าจัดส่งตามน้ำหนัก: amount + per_kg x จำนวนกิโลกรัม (ปัดขึ้น)
+	ShippingRateWeight = "weight"
+)
+
+const gramsPerKg = 1000
+
+var (
+	// ErrShippingMethodUnavailable ไม่พบวิธีจัดส่งที่เลือกหรือวิธีจัดส่งถูกปิดใช้งาน
+	ErrShippingMethodUnavailable = errors.New("ไม่พบวิธีจัดส่งนี้หรือวิธีจัดส่งถูกปิดใช้งาน")
+	// ErrShippingUnavailable วิธีจัดส่งไม่มีอัตราสำหรับภูมิภาคหรือน้ำหนักของตะกร้า
+	ErrShippingUnavailable = errors.New("วิธีจัดส่งนี้ไม่รองรับการจัดส่งไปยังภูมิภาคนี้หรือน้ำหนักนี้")
+	// ErrInvalidShippingRate ข้อมูลอัตราค่าจัดส่งไม่ถูกต้อง
+	ErrInvalidShippingRate = errors.New("อัตราค่าจัดส่งไม่ถูกต้อง")
+)
+
+// ShippingMethod วิธีจัดส่งที่ลูกค้าเลือกได้ เช่น standard หรือ express
+type ShippingMethod struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	// VolumetricDivisor ตัวหารน้ำหนักตามปริมาตร (ซม.³ ต่อ กก. เช่น 5000) 0 คือคิดจากน้ำหนักจริงเท่านั้น
+	VolumetricDivisor int            `json:"volumetric_divisor"`
+	SortOrder         int            `json:"sort_order"`
+	Rates             []ShippingRate `json:"rates"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// ShippingRate อัตราค่าจัดส่งของวิธีจัดส่งในโซนหนึ่ง สำหรับช่วงน้ำหนักที่กำหนด
+// Zone ใช้รูปแบบเดียวกับภูมิภาคของภาษี (TH, TH-10 หรือ "*" สำหรับทุกภูมิภาค)
+// จำนวนเงินทั้งหมดเป็นสกุลเงินหลักของร้าน
+type ShippingRate struct {
+	ID               uuid.UUID `json:"id"`
+	ShippingMethodID uuid.UUID `json:"shipping_method_id"`
+	Zone             string    `json:"zone"`
+	Type             string    `json:"type"`
+	// MinWeightGrams และ MaxWeightGrams ช่วงน้ำหนักที่ใช้อัตรานี้ (รวมค่าขอบ, Max 0 คือไม่จำกัด)
+	MinWeightGrams int   `json:"min_weight_grams"`
+	MaxWeightGrams int   `json:"max_weight_grams"`
+	Amount         Money `json:"amount"`
+	PerKg          Money `json:"per_kg"`
+	// FreeOver ส่งฟรีเมื่อยอดสินค้าหลังหักส่วนลดถึงเกณฑ์ (0 คือไม่มีส่งฟรี)
+	FreeOver  Money     `json:"free_over"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateShippingMethodRequest struct {
+	Code              string `json:"code" validate:"required,min=2,max=50"`
+	Name              string `json:"name" validate:"required,max=100"`
+	Description       string `json:"description" validate:"max=255"`
+	Active            *bool  `json:"active"`
+	VolumetricDivisor int    `json:"volumetric_divisor" validate:"min=0"`
+	SortOrder         int    `json:"sort_order"`
+}
+
+type UpdateShippingMethodRequest struct {
+	Name              string `json:"name" validate:"omitempty,max=100"`
+	Description       string `json:"description" validate:"omitempty,max=255"`
+	Active            *bool  `json:"active"`
+	VolumetricDivisor *int   `json:"volumetric_divisor" validate:"omitempty,min=0"`
+	SortOrder         *int   `json:"sort_order"`
+}
+
+type CreateShippingRateRequest struct {
+	ShippingMethodID uuid.UUID `json:"shipping_method_id" validate:"required"`
+	Zone             string    `json:"zone" validate:"required,max=10"`
+	Type             string    `json:"type" validate:"required,oneof=flat weight"`
+	MinWeightGrams   int       `json:"min_weight_grams" validate:"min=0"`
+	MaxWeightGrams   int       `json:"max_weight_grams" validate:"min=0"`
+	Amount           Money     `json:"amount" validate:"min=0"`
+	PerKg            Money     `json:"per_kg" validate:"min=0"`
+	FreeOver         Money     `json:"free_over" validate:"min=0"`
+}
+
+type UpdateShippingRateRequest struct {
+	Zone           string `json:"zone" validate:"omitempty,max=10"`
+	Type           string `json:"type" validate:"omitempty,oneof=flat weight"`
+	MinWeightGrams *int   `json:"min_weight_grams" validate:"omitempty,min=0"`
+	MaxWeightGrams *int   `json:"max_weight_grams" validate:"omitempty,min=0"`
+	Amount         *Money `json:"amount" validate:"omitempty,min=0"`
+	PerKg          *Money `json:"per_kg" validate:"omitempty,min=0"`
+	FreeOver       *Money `json:"free_over" validate:"omitempty,min=0"`
+}
+
+// NormalizeShippingCode รหัสวิธีจัดส่งเป็นตัวพิมพ์เล็กเสมอ เช่น "Express " คือ "express"
+func NormalizeShippingCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Validate ตรวจประเภทและช่วงน้ำหนักของอัตรา
+func (r *ShippingRate) Validate() error {
+	if r.Type != ShippingRateFlat && r.Type != ShippingRateWeight {
+		return fmt.Errorf("%w: type ต้องเป็น flat หรือ weight", ErrInvalidShippingRate)
+	}
+	if r.MaxWeightGrams > 0 && r.MaxWeightGrams < r.MinWeightGrams {
+		return fmt.Errorf("%w: max_weight_grams ต้องไม่น้อยกว่า min_weight_grams", ErrInvalidShippingRate)
+	}
+	if r.Amount < 0 || r.PerKg < 0 || r.FreeOver < 0 {
+		return fmt.Errorf("%w: จำนวนเงินต้องไม่ติดลบ", ErrInvalidShippingRate)
+	}
+	return nil
+}
+
+func (r *ShippingRate) covers(weightGrams int) bool {
+	return weightGrams >= r.MinWeightGrams && (r.MaxWeightGrams == 0 || weightGrams <= r.MaxWeightGrams)
+}
+
+// ShippingItem สินค้าหนึ่งรายการที่นำมาคิดน้ำหนักจัดส่ง
+type ShippingItem struct {
+	WeightGrams int
+	LengthCm    int
+	WidthCm     int
+	HeightCm    int
+	Quantity    int
+}
+
+// ShippingQuote ค่าจัดส่งของวิธีจัดส่งหนึ่งสำหรับตะกร้า ในสกุลเงินของตะกร้า
+type ShippingQuote struct {
+	ShippingMethodID uuid.UUID `json:"shipping_method_id"`
+	Code             string    `json:"code"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Zone             string    `json:"zone"`
+	WeightGrams      int       `json:"weight_grams"`
+	Amount           Money     `json:"amount"`
+	Currency         Currency  `json:"currency"`
+	Free             bool      `json:"free"`
+}
+
+// ChargeableWeight น้ำหนักที่ใช้คิดค่าจัดส่ง: น้ำหนักจริงหรือน้ำหนักตามปริมาตรต่อชิ้น แล้วแต่ค่าใดมากกว่า
+func (m *ShippingMethod) ChargeableWeight(items []ShippingItem) int {
+	total := 0
+	for _, item := range items {
+		weight := item.WeightGrams
+		if m.VolumetricDivisor > 0 {
+			volumetric := item.LengthCm * item.WidthCm * item.HeightCm * gramsPerKg / m.VolumetricDivisor
+			if volumetric > weight {
+				weight = volumetric
+			}
+		}
+		total += weight * item.Quantity
+	}
+	return total
+}
+
+// Quote คิดค่าจัดส่งจากอัตราของโซนที่เฉพาะเจาะจงที่สุด (TH-10 > TH > *) ที่ครอบคลุมน้ำหนักของตะกร้า
+// merchandise คือยอดสินค้าหลังหักส่วนลดในสกุลเงินของ pricing ใช้ตัดสินการส่งฟรี
+// คืน ErrShippingUnavailable หากไม่มีอัตราที่ใช้ได้
+func (m *ShippingMethod) Quote(items []ShippingItem, merchandise Money, region string, pricing PriceContext) (ShippingQuote, error) {
+	weight := m.ChargeableWeight(items)
+	quote := ShippingQuote{
+		ShippingMethodID: m.ID,
+		Code:             m.Code,
+		Name:             m.Name,
+		Description:      m.Description,
+		WeightGrams:      weight,
+		Currency:         pricing.Currency,
+	}
+
+	rate := m.rateFor(region, weight)
+	if rate == nil {
+		return quote, ErrShippingUnavailable
+	}
+	quote.Zone = rate.Zone
+
+	if freeOver := pricing.convertBase(rate.FreeOver); freeOver.IsPositive() && merchandise >= freeOver {
+		quote.Free = true
+		return quote, nil
+	}
+
+	amount := rate.Amount
+	if rate.Type == ShippingRateWeight {
+		kilograms := (weight + gramsPerKg - 1) / gramsPerKg
+		amount = amount.Add(rate.PerKg.Mul(kilograms))
+	}
+	quote.Amount = pricing.convertBase(amount)
+	return quote, nil
+}
+
+// rateFor อัตราของโซนที่เฉพาะเจาะจงที่สุดที่ครอบคลุมน้ำหนัก หากหลายช่วงซ้อนกันใช้ช่วงที่เริ่มสูงกว่า
+func (m *ShippingMethod) rateFor(region string, weightGrams int) *ShippingRate {
+	for _, zone := range RegionCandidates(region) {
+		var matched []*ShippingRate
+		for i := range m.Rates {
+			rate := &m.Rates[i]
+			if rate.Zone == zone && rate.covers(weightGrams) {
+				matched = append(matched, rate)
+			}
+		}
+		if len(matched) > 0 {
+			sort.Slice(matched, func(i, j int) bool {
+				return matched[i].MinWeightGrams > matched[j].MinWeightGrams
+			})
+			return matched[0]
+		}
+	}
+	return nil
+}
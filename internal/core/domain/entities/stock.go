@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// สถานะการจองสต็อก
+const (
+	// ReservationActive ยังถือสต็อกอยู่จนถึง expires_at
+	ReservationActive = "active"
+	// ReservationCommitted คำสั่งซื้อชำระเงินหรือยืนยันแล้ว สต็อกถูกขายไปแล้ว
+	ReservationCommitted = "committed"
+	// ReservationReleased คำสั่งซื้อถูกยกเลิกหรือหมดเวลา สต็อกถูกคืนแล้ว
+	ReservationReleased = "released"
+)
+
+// StockLevel สต็อกของสินค้าแยกตามการจอง
+// OnHand = สต็อกที่ยังไม่ถูกขายขาด (รวมที่คำสั่งซื้อค้างชำระถืออยู่)
+// Sellable = สต็อกที่ขายได้ทันที (หักที่ตะกร้าอื่นถืออยู่แล้ว)
+type StockLevel struct {
+	OnHand       int `json:"on_hand"`
+	HeldByOrders int `json:"held_by_orders"`
+	HeldByCarts  int `json:"held_by_carts"`
+	Sellable     int `json:"sellable"`
+}
+
+// ErrInsufficientStock ใช้กับ errors.Is เพื่อตรวจว่าสต็อกไม่พอสำหรับคำสั่งซื้อ
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// InsufficientStockItem สินค้าที่สต็อกไม่พอ พร้อมจำนวนที่ขอและจำนวนที่มีอยู่
+type InsufficientStockItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`
+	Requested int       `json:"requested"`
+	Available int       `json:"available"`
+}
+
+// InsufficientStockError สต็อกไม่พอสำหรับสินค้าอย่างน้อยหนึ่งรายการ (ตอบกลับเป็น 409)
+type InsufficientStockError struct {
+	Items []InsufficientStockItem `json:"items"`
+}
+
+func (e *InsufficientStockError) Error() string {
+	names := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		names = append(names, fmt.Sprintf("%s (ต้องการ %d, เหลือ %d)", item.Name, item.Requested, item.Available))
+	}
+	return "สินค้าในสต็อกไม่เพียงพอ: " + strings.Join(names, ", ")
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
@@ -0,0 +1,228 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WildcardRegion ภูมิภาคของอัตราภาษีที่ใช้เมื่อไม่มีอัตราเฉพาะของภูมิภาคที่จัดส่ง
+const WildcardRegion = "*"
+
+var (
+	// ErrTaxClassInUse ลบประเภทภาษีที่ยังผูกกับสินค้าหรือหมวดหมู่ไม่ได้
+	ErrTaxClassInUse = errors.New("ประเภทภาษีนี้ยังถูกใช้งานโดยสินค้าหรือหมวดหมู่")
+	// ErrInvalidTaxRate ข้อมูลอัตราภาษีไม่ถูกต้อง
+	ErrInvalidTaxRate = errors.New("อัตราภาษีไม่ถูกต้อง")
+)
+
+// PercentScale จำนวนหลักทศนิยมของเปอร์เซ็นต์ ตรงกับคอลัมน์ numeric(7,4)
+const PercentScale = 4
+
+const percentUnit = 10000
+
+// Percent เปอร์เซ็นต์เก็บเป็นจำนวนเต็มคูณ 10^4 เช่น 7% คือ 70000
+type Percent int64
+
+// ParsePercent แปลงสตริงทศนิยม เช่น "7" หรือ "7.25" เป็น Percent
+func ParsePercent(s string) (Percent, error) {
+	value, err := parseDecimal(s, PercentScale)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTaxRate, s)
+	}
+	return Percent(value), nil
+}
+
+func (p Percent) String() string {
+	return formatDecimal(int64(p), PercentScale)
+}
+
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	parsed, err := ParsePercent(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func (p Percent) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+func (p *Percent) Scan(value interface{}) error {
+	parsed, err := scanDecimal(value, PercentScale)
+	if err != nil {
+		return err
+	}
+	*p = Percent(parsed)
+	return nil
+}
+
+// TaxClass ประเภทภาษีที่กำหนดให้หมวดหมู่หรือสินค้า เช่น standard, reduced, exempt
+type TaxClass struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// IsDefault ใช้กับสินค้าที่ทั้งสินค้าและหมวดหมู่ไม่ได้ระบุประเภทภาษี
+	IsDefault bool      `json:"is_default"`
+	Rates     []TaxRate `json:"rates,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaxRate อัตราภาษีของประเภทภาษีหนึ่งในภูมิภาคที่จัดส่ง
+// Region เป็นรหัสประเทศ (TH), รหัสเขตย่อย (TH-10) หรือ "*" สำหรับทุกภูมิภาค
+type TaxRate struct {
+	ID         uuid.UUID `json:"id"`
+	TaxClassID uuid.UUID `json:"tax_class_id"`
+	Region     string    `json:"region"`
+	Name       string    `json:"name"`
+	Rate       Percent   `json:"rate"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CreateTaxClassRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+type UpdateTaxClassRequest struct {
+	Name        string `json:"name" validate:"omitempty,min=2,max=100"`
+	Description string `json:"description"`
+	IsDefault   *bool  `json:"is_default"`
+}
+
+type CreateTaxRateRequest struct {
+	TaxClassID uuid.UUID `json:"tax_class_id" validate:"required"`
+	Region     string    `json:"region" validate:"required,max=10"`
+	Name       string    `json:"name" validate:"required,max=100"`
+	Rate       Percent   `json:"rate" validate:"min=0,max=1000000"`
+}
+
+type UpdateTaxRateRequest struct {
+	Region string   `json:"region" validate:"omitempty,max=10"`
+	Name   string   `json:"name" validate:"omitempty,max=100"`
+	Rate   *Percent `json:"rate" validate:"omitempty,min=0,max=1000000"`
+}
+
+// NormalizeRegion แปลงรหัสภูมิภาคเป็นตัวพิมพ์ใหญ่ ค่าว่างคือ "*"
+func NormalizeRegion(region string) string {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		return WildcardRegion
+	}
+	return region
+}
+
+// RegionCandidates ภูมิภาคที่ใช้ค้นหาอัตราภาษี เรียงจากเฉพาะเจาะจงที่สุด เช่น TH-10, TH, *
+func RegionCandidates(region string) []string {
+	region = NormalizeRegion(region)
+	if region == WildcardRegion {
+		return []string{WildcardRegion}
+	}
+
+	candidates := []string{region}
+	if country, _, found := strings.Cut(region, "-"); found && country != "" {
+		candidates = append(candidates, country)
+	}
+	return append(candidates, WildcardRegion)
+}
+
+// TaxPolicy อัตราภาษีที่ใช้กับคำสั่งซื้อหนึ่ง แยกตามประเภทภาษี
+type TaxPolicy struct {
+	Region string
+	// PricesIncludeTax ราคาสินค้ารวมภาษีแล้ว (ภาษีถูกถอดออกจากราคา) หรือยังไม่รวม (บวกภาษีเพิ่ม)
+	PricesIncludeTax bool
+	DefaultClassID   *uuid.UUID
+	// Rates อัตราภาษีของภูมิภาคนี้ คีย์คือ TaxClass ID
+	Rates map[uuid.UUID]TaxRate
+}
+
+// ClassFor ประเภทภาษีของสินค้า: สินค้า > หมวดหมู่ > ประเภทเริ่มต้น
+func (p TaxPolicy) ClassFor(productClassID, categoryClassID *uuid.UUID) *uuid.UUID {
+	switch {
+	case productClassID != nil:
+		return productClassID
+	case categoryClassID != nil:
+		return categoryClassID
+	default:
+		return p.DefaultClassID
+	}
+}
+
+// RateFor อัตราภาษีของประเภทภาษี (0 หากไม่มีอัตราในภูมิภาคนี้)
+func (p TaxPolicy) RateFor(classID *uuid.UUID) Percent {
+	if classID == nil {
+		return 0
+	}
+	return p.Rates[*classID].Rate
+}
+
+// LineTax ภาษีของรายการหนึ่งบรรทัด ปัดเศษต่อบรรทัดตามกฎของ Money
+// ราคารวมภาษี: ภาษี = ราคา x rate / (100 + rate), ราคาไม่รวมภาษี: ภาษี = ราคา x rate / 100
+func (p TaxPolicy) LineTax(lineTotal Money, rate Percent) Money {
+	if rate == 0 {
+		return 0
+	}
+	if p.PricesIncludeTax {
+		return lineTotal.MulRatio(int64(rate), 100*percentUnit+int64(rate))
+	}
+	return lineTotal.MulRatio(int64(rate), 100*percentUnit)
+}
+
+// GrandTotal ยอดสุทธิจากยอดรวมรายการและภาษีรวม
+func (p TaxPolicy) GrandTotal(subtotal, tax Money) Money {
+	if p.PricesIncludeTax {
+		return subtotal
+	}
+	return subtotal.Add(tax)
+}
+
+// TaxSettings การตั้งค่าภาษีของร้าน
+type TaxSettings struct {
+	// PricesIncludeTax ราคาสินค้าที่ตั้งไว้รวมภาษีแล้วหรือไม่
+	PricesIncludeTax bool
+	// DefaultRegion ภูมิภาคที่ใช้เมื่อคำสั่งซื้อไม่ได้ระบุภูมิภาคที่จัดส่ง
+	DefaultRegion string
+}
+
+// BuildTaxPolicy เลือกอัตราภาษีของแต่ละประเภทสำหรับภูมิภาคที่จัดส่ง
+// โดยใช้อัตราของภูมิภาคที่เฉพาะเจาะจงที่สุดก่อน (TH-10 > TH > *)
+func BuildTaxPolicy(classes []*TaxClass, region string, pricesIncludeTax bool) TaxPolicy {
+	policy := TaxPolicy{
+		Region:           NormalizeRegion(region),
+		PricesIncludeTax: pricesIncludeTax,
+		Rates:            make(map[uuid.UUID]TaxRate),
+	}
+
+	candidates := RegionCandidates(region)
+	for _, class := range classes {
+		if class.IsDefault {
+			id := class.ID
+			policy.DefaultClassID = &id
+		}
+
+		best := len(candidates)
+		for _, rate := range class.Rates {
+			for i, candidate := range candidates {
+				if NormalizeRegion(rate.Region) == candidate && i < best {
+					best = i
+					policy.Rates[class.ID] = rate
+				}
+			}
+		}
+	}
+
+	return policy
+}
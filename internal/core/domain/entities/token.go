@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken access token ไม่ถูกต้อง ลายเซ็นไม่ตรงกับกุญแจที่รู้จัก หรือหมดอายุแล้ว
+var ErrInvalidToken = errors.New("token ไม่ถูกต้องหรือหมดอายุ")
+
+// AccessToken claims ของ access token (JWT) ที่ออกตอนเข้าสู่ระบบและรีเฟรช
+type AccessToken struct {
+	// ID คือ jti ของ token
+	ID        string
+	UserID    uuid.UUID
+	Email     string
+	Role      string
+	SessionID uuid.UUID
+	// TokenVersion รุ่นของ token ของผู้ใช้ขณะออก token (ดู TokenRevocationStore)
+	TokenVersion int
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
+}
+
+// JWK กุญแจสาธารณะหนึ่งดอกในรูปแบบ JSON Web Key (RFC 7517) สำหรับตรวจสอบลายเซ็นของ access token
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N และ E สำหรับกุญแจ RSA (RS256)
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv และ X สำหรับกุญแจ Ed25519 (EdDSA)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS ชุดกุญแจสาธารณะที่ตรวจสอบ access token ได้ (/.well-known/jwks.json)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +20,7 @@ type RegisterRequest struct {
 	LastName  string `json:"last_name" validate:"required"`
 	Phone     string `json:"phone"`
 	Address   string `json:"address"`
+	Locale    string `json:"locale" validate:"omitempty,oneof=th en"`
 }
 
 type AdminRegisterRequest struct {
@@ -63,12 +65,20 @@ type User struct {
 	LastName  string    `json:"last_name"`
 	Avatar    string    `json:"avatar"`
 	Phone     string    `json:"phone"`
-	Address   string    `json:"address"`
-	Active    bool      `json:"active"`
-	RoleID    uuid.UUID `json:"role_id"`
-	Role      *Role     `json:"role,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// Address ที่อยู่แบบข้อความเดิม ที่อยู่จัดส่งและออกใบเสร็จใช้สมุดที่อยู่ (/me/addresses)
+	Address         string     `json:"address"`
+	Active          bool       `json:"active"`
+	Locale          string     `json:"locale"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	RoleID          uuid.UUID  `json:"role_id"`
+	Role            *Role      `json:"role,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// EmailVerified ตรวจสอบว่ายืนยันอีเมลแล้วหรือไม่
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 type UpdateUserRequest struct {
@@ -77,6 +87,7 @@ type UpdateUserRequest struct {
 	Avatar    string `json:"avatar"`
 	Phone     string `json:"phone"`
 	Address   string `json:"address"`
+	Active    *bool  `json:"active"`
 }
 
 // Role Entity
@@ -99,40 +110,57 @@ type Permission struct {
 }
 
 // Category Entity
+// TaxClassID ประเภทภาษีของสินค้าในหมวดหมู่ที่ไม่ได้ระบุประเภทภาษีเอง
 type Category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Image       string     `json:"image"`
+	TaxClassID  *uuid.UUID `json:"tax_class_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 type CreateCategoryRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
-	Image       string `json:"image"`
+	Name        string     `json:"name" validate:"required"`
+	Description string     `json:"description"`
+	Image       string     `json:"image"`
+	TaxClassID  *uuid.UUID `json:"tax_class_id"`
 }
 
+// UpdateCategoryRequest ส่ง tax_class_id เป็น UUID ศูนย์เพื่อกลับไปใช้ประเภทภาษีเริ่มต้น
 type UpdateCategoryRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Image       string `json:"image"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Image       string     `json:"image"`
+	TaxClassID  *uuid.UUID `json:"tax_class_id"`
 }
 
 // Product Entity
+// Price เป็นสกุลเงินหลักของร้าน ส่วน Prices คือราคาที่กำหนดเองแยกตามสกุลเงิน
+// สกุลที่ไม่มีใน Prices จะแปลงจาก Price ด้วยอัตราแลกเปลี่ยน
 type Product struct {
 	ID          uuid.UUID      `json:"id"`
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
-	Price       float64        `json:"price"`
+	Price       Money          `json:"price"`
+	Currency    Currency       `json:"currency"`
+	Prices      []ProductPrice `json:"prices,omitempty"`
 	Stock       int            `json:"stock"`
 	Image       string         `json:"image"`
 	Images      []ProductImage `json:"images,omitempty"`
 	CategoryID  uuid.UUID      `json:"category_id"`
 	Category    *Category      `json:"category,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	TaxClassID  *uuid.UUID     `json:"tax_class_id"`
+	// WeightGrams และขนาด (ซม.) ใช้คิดค่าจัดส่ง
+	WeightGrams int `json:"weight_grams"`
+	LengthCm    int `json:"length_cm"`
+	WidthCm     int `json:"width_cm"`
+	HeightCm    int `json:"height_cm"`
+	// Availability แสดงเฉพาะตอนดูสินค้ารายตัว
+	Availability *StockLevel `json:"availability,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
 type ProductImage struct {
@@ -146,40 +174,66 @@ type ProductImage struct {
 type CreateProductRequest struct {
 	Name        string    `json:"name" validate:"required"`
 	Description string    `json:"description"`
-	Price       float64   `json:"price" validate:"required,min=0"`
+	Price       Money     `json:"price" validate:"required,min=0"`
 	Stock       int       `json:"stock" validate:"min=0"`
 	Image       string    `json:"image"`
 	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
 	Images      []string  `json:"images"`
+	// TaxClassID ไม่ระบุหมายถึงใช้ประเภทภาษีของหมวดหมู่
+	TaxClassID *uuid.UUID `json:"tax_class_id"`
+	// WeightGrams และขนาด (ซม.) ใช้คิดค่าจัดส่ง
+	WeightGrams int `json:"weight_grams" validate:"min=0"`
+	LengthCm    int `json:"length_cm" validate:"min=0"`
+	WidthCm     int `json:"width_cm" validate:"min=0"`
+	HeightCm    int `json:"height_cm" validate:"min=0"`
+	// Prices ราคาในสกุลเงินอื่น (ราคาหลักเป็นสกุลเงินหลักของร้าน)
+	Prices []ProductPriceRequest `json:"prices" validate:"omitempty,dive"`
 }
 
 type UpdateProductRequest struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Price       float64   `json:"price" validate:"min=0"`
+	Price       Money     `json:"price" validate:"min=0"`
 	Stock       int       `json:"stock" validate:"min=0"`
 	Image       string    `json:"image"`
 	CategoryID  uuid.UUID `json:"category_id"`
 	Images      []string  `json:"images"`
+	// TaxClassID ส่ง UUID ศูนย์เพื่อกลับไปใช้ประเภทภาษีของหมวดหมู่
+	TaxClassID  *uuid.UUID `json:"tax_class_id"`
+	WeightGrams *int       `json:"weight_grams" validate:"omitempty,min=0"`
+	LengthCm    *int       `json:"length_cm" validate:"omitempty,min=0"`
+	WidthCm     *int       `json:"width_cm" validate:"omitempty,min=0"`
+	HeightCm    *int       `json:"height_cm" validate:"omitempty,min=0"`
+	// Prices แทนที่รายการราคาทั้งหมด (ไม่ส่งหมายถึงไม่เปลี่ยน ส่งอาร์เรย์ว่างเพื่อลบทั้งหมด)
+	Prices *[]ProductPriceRequest `json:"prices" validate:"omitempty,dive"`
 }
 
 type ProductSearchRequest struct {
 	Query      string    `json:"query"`
 	CategoryID uuid.UUID `json:"category_id"`
-	MinPrice   float64   `json:"min_price"`
-	MaxPrice   float64   `json:"max_price"`
+	MinPrice   Money     `json:"min_price"`
+	MaxPrice   Money     `json:"max_price"`
 	Page       int       `json:"page"`
 	Limit      int       `json:"limit"`
 }
 
 // Cart Entity
+// Subtotal คือยอดรวมรายการสินค้า TotalPrice คือยอดหลังหักส่วนลด (DiscountTotal) แล้ว
 type Cart struct {
-	ID         uuid.UUID  `json:"id"`
-	UserID     uuid.UUID  `json:"user_id"`
-	CartItems  []CartItem `json:"cart_items"`
-	TotalPrice float64    `json:"total_price"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	ID            uuid.UUID         `json:"id"`
+	UserID        uuid.UUID         `json:"user_id"`
+	CartItems     []CartItem        `json:"cart_items"`
+	Subtotal      Money             `json:"subtotal"`
+	Discounts     []AppliedDiscount `json:"discounts"`
+	DiscountTotal Money             `json:"discount_total"`
+	TotalPrice    Money             `json:"total_price"`
+	Currency      Currency          `json:"currency"`
+	// CouponCode รหัสคูปองที่ลูกค้ากรอกไว้ หากใช้ไม่ได้แล้ว CouponError จะบอกเหตุผล
+	CouponID    *uuid.UUID `json:"coupon_id,omitempty"`
+	CouponCode  string     `json:"coupon_code,omitempty"`
+	CouponError string     `json:"coupon_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 type CartItem struct {
@@ -188,7 +242,7 @@ type CartItem struct {
 	ProductID uuid.UUID `json:"product_id"`
 	Product   *Product  `json:"product,omitempty"`
 	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
+	Price     Money     `json:"price"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -203,23 +257,58 @@ type UpdateCartItemRequest struct {
 }
 
 // Order Entity
+// BaseCurrency และ ExchangeRate คืออัตราที่ตรึงไว้ตอนสั่งซื้อ (1 หน่วย BaseCurrency = ExchangeRate หน่วย Currency)
+// Subtotal คือยอดรวมรายการสินค้า DiscountAmount คือส่วนลดรวม TaxAmount คือภาษีรวม และ TotalPrice คือยอดสุทธิที่ต้องชำระ
+// หาก PricesIncludeTax ราคาสินค้ารวมภาษีแล้ว TotalPrice คือ Subtotal - DiscountAmount + ShippingAmount มิฉะนั้นบวก TaxAmount เพิ่ม
+// ภาษีคิดจากยอดหลังหักส่วนลดของแต่ละรายการ ShippingMethodID/ShippingMethod (รหัส) และ ShippingAmount ตรึงไว้ตอนสั่งซื้อ
+// Addresses คือสำเนาที่อยู่จัดส่ง/ออกใบเสร็จ ณ เวลาสั่งซื้อ ShippingAddress คือที่อยู่จัดส่งในรูปข้อความ
 type Order struct {
-	ID              uuid.UUID     `json:"id"`
-	UserID          uuid.UUID     `json:"user_id"`
-	User            *User         `json:"user,omitempty"`
-	OrderItems      []OrderItem   `json:"order_items"`
-	TotalPrice      float64       `json:"total_price"`
-	Status          string        `json:"status"`
-	PaymentMethod   string        `json:"payment_method"`
-	PaymentStatus   string        `json:"payment_status"`
-	ShippingMethod  string        `json:"shipping_method"`
-	ShippingStatus  string        `json:"shipping_status"`
-	ShippingAddress string        `json:"shipping_address"`
-	TrackingNumber  string        `json:"tracking_number"`
-	Notes           string        `json:"notes"`
-	Transactions    []Transaction `json:"transactions,omitempty"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID               uuid.UUID            `json:"id"`
+	UserID           uuid.UUID            `json:"user_id"`
+	User             *User                `json:"user,omitempty"`
+	OrderItems       []OrderItem          `json:"order_items"`
+	Subtotal         Money                `json:"subtotal"`
+	DiscountAmount   Money                `json:"discount_amount"`
+	TaxAmount        Money                `json:"tax_amount"`
+	TotalPrice       Money                `json:"total_price"`
+	PricesIncludeTax bool                 `json:"prices_include_tax"`
+	TaxRegion        string               `json:"tax_region"`
+	CouponCode       string               `json:"coupon_code,omitempty"`
+	Discounts        []CouponRedemption   `json:"discounts,omitempty"`
+	Currency         Currency             `json:"currency"`
+	BaseCurrency     Currency             `json:"base_currency"`
+	ExchangeRate     ExchangeRate         `json:"exchange_rate"`
+	Status           string               `json:"status"`
+	PaymentMethod    string               `json:"payment_method"`
+	PaymentStatus    string               `json:"payment_status"`
+	ShippingMethodID *uuid.UUID           `json:"shipping_method_id,omitempty"`
+	ShippingMethod   string               `json:"shipping_method"`
+	ShippingAmount   Money                `json:"shipping_amount"`
+	ShippingStatus   string               `json:"shipping_status"`
+	ShippingAddress  string               `json:"shipping_address"`
+	Addresses        []OrderAddress       `json:"addresses,omitempty"`
+	TrackingNumber   string               `json:"tracking_number"`
+	Carrier          string               `json:"carrier"`
+	ShippedAt        *time.Time           `json:"shipped_at,omitempty"`
+	DeliveredAt      *time.Time           `json:"delivered_at,omitempty"`
+	PaymentDueAt     *time.Time           `json:"payment_due_at,omitempty"`
+	Notes            string               `json:"notes"`
+	Transactions     []Transaction        `json:"transactions,omitempty"`
+	Shipments        []Shipment           `json:"shipments,omitempty"`
+	History          []OrderStatusHistory `json:"history,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// OrderStatusHistory ประวัติการเปลี่ยนสถานะคำสั่งซื้อ (timeline)
+type OrderStatusHistory struct {
+	ID        uuid.UUID  `json:"id"`
+	Field     string     `json:"field"`
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	ChangedBy *uuid.UUID `json:"changed_by,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
 }
 
 type OrderItem struct {
@@ -228,42 +317,138 @@ type OrderItem struct {
 	ProductID uuid.UUID `json:"product_id"`
 	Product   *Product  `json:"product,omitempty"`
 	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Price     Money     `json:"price"`
+	// DiscountAmount ส่วนลดที่แบ่งลงรายการนี้ TaxRate และ TaxAmount คืออัตราและภาษีของรายการนี้ ณ เวลาสั่งซื้อ
+	DiscountAmount Money      `json:"discount_amount"`
+	TaxClassID     *uuid.UUID `json:"tax_class_id,omitempty"`
+	TaxRate        Percent    `json:"tax_rate"`
+	TaxAmount      Money      `json:"tax_amount"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 type CreateOrderRequest struct {
-	PaymentMethod   string `json:"payment_method" validate:"required"`
-	ShippingMethod  string `json:"shipping_method" validate:"required"`
-	ShippingAddress string `json:"shipping_address" validate:"required"`
-	Notes           string `json:"notes"`
+	PaymentMethod string `json:"payment_method" validate:"required"`
+	// ShippingMethod รหัสวิธีจัดส่ง เช่น standard (ดูตัวเลือกและค่าจัดส่งได้จาก GET /cart/shipping-options)
+	ShippingMethod string `json:"shipping_method" validate:"required"`
+	// ShippingAddressID ที่อยู่จัดส่งจากสมุดที่อยู่ ไม่ระบุใช้ ShippingAddress แบบข้อความ หรือที่อยู่จัดส่งเริ่มต้น
+	ShippingAddressID *uuid.UUID `json:"shipping_address_id"`
+	ShippingAddress   string     `json:"shipping_address"`
+	// BillingAddressID ที่อยู่ออกใบเสร็จจากสมุดที่อยู่ ไม่ระบุใช้ที่อยู่ออกใบเสร็จเริ่มต้น
+	BillingAddressID *uuid.UUID `json:"billing_address_id"`
+	// ShippingRegion ภูมิภาคที่จัดส่ง เช่น TH หรือ TH-10 ใช้เลือกอัตราภาษีและค่าจัดส่ง
+	// (ไม่ระบุใช้ภูมิภาคของที่อยู่จัดส่ง หรือค่าเริ่มต้นของร้าน)
+	ShippingRegion string `json:"shipping_region" validate:"omitempty,max=10"`
+	Notes          string `json:"notes"`
 }
 
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" validate:"required"`
+	Status string `json:"status" validate:"required,oneof=pending confirmed processing shipped delivered cancelled"`
+	Note   string `json:"note" validate:"max=500"`
 }
 
 type UpdatePaymentStatusRequest struct {
-	PaymentStatus string `json:"payment_status" validate:"required"`
+	PaymentStatus string `json:"payment_status" validate:"required,oneof=pending paid failed cancelled partially_refunded refunded"`
+	Note          string `json:"note" validate:"max=500"`
 }
 
 type UpdateShippingStatusRequest struct {
-	ShippingStatus string `json:"shipping_status" validate:"required"`
-	TrackingNumber string `json:"tracking_number"`
+	ShippingStatus string `json:"shipping_status" validate:"required,oneof=pending processing partially_shipped shipped in_transit delivered returned cancelled"`
+	Carrier        string `json:"carrier" validate:"max=100"`
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+	Note           string `json:"note" validate:"max=500"`
+}
+
+// Shipment พัสดุที่จัดส่งสำหรับคำสั่งซื้อ (คำสั่งซื้อหนึ่งแบ่งส่งได้หลายพัสดุ)
+type Shipment struct {
+	ID             uuid.UUID      `json:"id"`
+	OrderID        uuid.UUID      `json:"order_id"`
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	Status         string         `json:"status"`
+	ShippedAt      *time.Time     `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	Items          []ShipmentItem `json:"items"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type ShipmentItem struct {
+	OrderItemID uuid.UUID `json:"order_item_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+type CreateShipmentRequest struct {
+	Carrier        string `json:"carrier" validate:"required,max=100"`
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+	// Items ว่างหมายถึงสินค้าทุกรายการที่ยังไม่ได้จัดส่ง
+	Items []ShipmentItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+type ShipmentItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
+}
+
+type UpdateShipmentRequest struct {
+	Status         string `json:"status" validate:"omitempty,oneof=pending shipped in_transit delivered returned cancelled"`
+	Carrier        string `json:"carrier" validate:"max=100"`
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+	Note           string `json:"note" validate:"max=500"`
 }
 
 // Transaction Entity
 type Transaction struct {
-	ID            uuid.UUID `json:"id"`
-	OrderID       uuid.UUID `json:"order_id"`
-	Amount        float64   `json:"amount"`
-	PaymentMethod string    `json:"payment_method"`
-	Status        string    `json:"status"`
-	TransactionID string    `json:"transaction_id"`
-	PaymentData   string    `json:"payment_data"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             uuid.UUID `json:"id"`
+	OrderID        uuid.UUID `json:"order_id"`
+	Amount         Money     `json:"amount"`
+	Currency       Currency  `json:"currency"`
+	PaymentMethod  string    `json:"payment_method"`
+	Status         string    `json:"status"`
+	TransactionID  string    `json:"transaction_id"`
+	PaymentData    string    `json:"payment_data"`
+	Provider       string    `json:"provider"`
+	ProviderRef    string    `json:"provider_ref,omitempty"`
+	ClientSecret   string    `json:"client_secret,omitempty"`
+	RefundedAmount Money     `json:"refunded_amount"`
+	Refunds        []Refund  `json:"refunds,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Refund การคืนเงินที่ผูกกับ Transaction ต้นทาง
+type Refund struct {
+	ID            uuid.UUID    `json:"id"`
+	TransactionID uuid.UUID    `json:"transaction_id"`
+	Amount        Money        `json:"amount"`
+	Reason        string       `json:"reason"`
+	Status        string       `json:"status"`
+	ProviderRef   string       `json:"provider_ref,omitempty"`
+	Restock       bool         `json:"restock"`
+	Items         []RefundItem `json:"items,omitempty"`
+	CreatedBy     *uuid.UUID   `json:"created_by,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// RefundItem จำนวนสินค้าที่คืนเข้าสต็อกจากการคืนเงิน
+type RefundItem struct {
+	OrderItemID uuid.UUID `json:"order_item_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+type CreateRefundRequest struct {
+	// Amount เป็น 0 หมายถึงคืนยอดคงเหลือทั้งหมด
+	Amount  Money               `json:"amount" validate:"omitempty,gt=0"`
+	Reason  string              `json:"reason" validate:"max=500"`
+	Restock bool                `json:"restock"`
+	Items   []RefundItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+// RefundItemRequest ระบุสินค้าที่จะคืนสต็อก หากไม่ระบุและ restock เป็น true จะคืนสต็อกทุกรายการที่เหลือ
+type RefundItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
 }
 
 type CreatePaymentRequest struct {
@@ -277,16 +462,64 @@ type VerifyPaymentRequest struct {
 	PaymentData   string `json:"payment_data"`
 }
 
+// Payment Gateway
+const (
+	PaymentWebhookSucceeded = "succeeded"
+	PaymentWebhookFailed    = "failed"
+)
+
+// PaymentIntentRequest ข้อมูลที่ส่งให้ payment gateway เพื่อสร้างรายการชำระเงิน
+type PaymentIntentRequest struct {
+	PaymentID     uuid.UUID
+	OrderID       uuid.UUID
+	Amount        Money
+	Currency      Currency
+	PaymentMethod string
+}
+
+// PaymentIntent ผลการสร้างรายการชำระเงินฝั่ง gateway
+type PaymentIntent struct {
+	ProviderRef  string
+	ClientSecret string
+	Status       string
+}
+
+// GatewayCapture ผลการเรียกเก็บเงินจาก gateway
+type GatewayCapture struct {
+	ProviderRef string
+	Amount      Money
+	Status      string
+}
+
+// GatewayRefund ผลการคืนเงินจาก gateway
+type GatewayRefund struct {
+	RefundRef string
+	Amount    Money
+	Status    string
+}
+
+// PaymentWebhookEvent callback จาก gateway ที่ผ่านการตรวจลายเซ็นแล้ว
+type PaymentWebhookEvent struct {
+	Provider    string   `json:"provider"`
+	EventID     string   `json:"event_id"`
+	ProviderRef string   `json:"provider_ref"`
+	Status      string   `json:"status"`
+	Amount      Money    `json:"amount"`
+	Currency    Currency `json:"currency"`
+}
+
 // Stats Entity
+// SalesStats ยอดขายแปลงเป็นสกุลเงินหลักด้วยอัตราที่ตรึงไว้ในแต่ละคำสั่งซื้อ
 type SalesStats struct {
-	TotalSales    float64 `json:"total_sales"`
-	TotalOrders   int     `json:"total_orders"`
-	TodaySales    float64 `json:"today_sales"`
-	TodayOrders   int     `json:"today_orders"`
-	MonthlySales  float64 `json:"monthly_sales"`
-	MonthlyOrders int     `json:"monthly_orders"`
-	YearlySales   float64 `json:"yearly_sales"`
-	YearlyOrders  int     `json:"yearly_orders"`
+	Currency      Currency `json:"currency"`
+	TotalSales    Money    `json:"total_sales"`
+	TotalOrders   int      `json:"total_orders"`
+	TodaySales    Money    `json:"today_sales"`
+	TodayOrders   int      `json:"today_orders"`
+	MonthlySales  Money    `json:"monthly_sales"`
+	MonthlyOrders int      `json:"monthly_orders"`
+	YearlySales   Money    `json:"yearly_sales"`
+	YearlyOrders  int      `json:"yearly_orders"`
 }
 
 type ProductStats struct {
@@ -303,6 +536,203 @@ type UserStats struct {
 	NewUsers      int `json:"new_users"`
 }
 
+// Domain Event Entity
+const (
+	EventUserRegistered     = "user.registered"
+	EventUserDeleted        = "user.deleted"
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	// EventOrderShippingStatusChanged ใช้ OrderStatusChangedPayload กับสถานะการจัดส่ง
+	EventOrderShippingStatusChanged = "order.shipping_status_changed"
+	EventPaymentCompleted           = "payment.completed"
+	EventPaymentFailed              = "payment.failed"
+	EventPaymentRefunded            = "payment.refunded"
+	EventProductLowStock            = "product.low_stock"
+	EventProductBackInStock         = "product.back_in_stock"
+)
+
+type DomainEvent struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// NewDomainEvent สร้าง event ใหม่พร้อมแปลง payload เป็น JSON
+func NewDomainEvent(eventType, aggregateType string, aggregateID uuid.UUID, payload interface{}) (DomainEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return DomainEvent{}, err
+	}
+
+	return DomainEvent{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    time.Now(),
+	}, nil
+}
+
+// OutboxMessage event ที่รอส่งออกจากตาราง outbox
+type OutboxMessage struct {
+	Event         DomainEvent `json:"event"`
+	Status        string      `json:"status"`
+	Attempts      int         `json:"attempts"`
+	LastError     string      `json:"last_error"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+	// DeliveredSinks sink ที่ส่งสำเร็จแล้วในรอบก่อน ๆ จะไม่ถูกส่งซ้ำเมื่อ retry
+	DeliveredSinks []string `json:"delivered_sinks"`
+}
+
+type UserRegisteredPayload struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+}
+
+// UserDeletedPayload ผู้ใช้ลบบัญชีของตัวเอง ข้อมูลส่วนตัวถูกลบแล้วจึงส่งเฉพาะ ID
+type UserDeletedPayload struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type OrderEventItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	Price     Money     `json:"price"`
+}
+
+type OrderCreatedPayload struct {
+	OrderID       uuid.UUID        `json:"order_id"`
+	UserID        uuid.UUID        `json:"user_id"`
+	Subtotal      Money            `json:"subtotal"`
+	Discount      Money            `json:"discount"`
+	Shipping      Money            `json:"shipping"`
+	TaxAmount     Money            `json:"tax_amount"`
+	TotalPrice    Money            `json:"total_price"`
+	Currency      Currency         `json:"currency"`
+	PaymentMethod string           `json:"payment_method"`
+	Items         []OrderEventItem `json:"items"`
+}
+
+type OrderStatusChangedPayload struct {
+	OrderID uuid.UUID `json:"order_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+}
+
+type ProductLowStockPayload struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`
+	Stock     int       `json:"stock"`
+	Threshold int       `json:"threshold"`
+}
+
+// ProductBackInStockPayload สินค้ากลับมามีสต็อก (จาก 0) พร้อมผู้ใช้ที่มีสินค้านี้ในรายการโปรด
+// เป็น event ภายในสำหรับแจ้งเตือนผู้ใช้ จึงไม่เปิดให้ webhook ภายนอกสมัครรับ
+type ProductBackInStockPayload struct {
+	ProductID uuid.UUID   `json:"product_id"`
+	Name      string      `json:"name"`
+	Stock     int         `json:"stock"`
+	UserIDs   []uuid.UUID `json:"user_ids"`
+}
+
+type PaymentEventPayload struct {
+	PaymentID     uuid.UUID `json:"payment_id"`
+	OrderID       uuid.UUID `json:"order_id"`
+	TransactionID string    `json:"transaction_id"`
+	Amount        Money     `json:"amount"`
+	Currency      Currency  `json:"currency"`
+	PaymentMethod string    `json:"payment_method"`
+	Status        string    `json:"status"`
+}
+
+type PaymentRefundedPayload struct {
+	PaymentID      uuid.UUID `json:"payment_id"`
+	OrderID        uuid.UUID `json:"order_id"`
+	RefundID       uuid.UUID `json:"refund_id"`
+	Amount         Money     `json:"amount"`
+	RefundedAmount Money     `json:"refunded_amount"`
+	Currency       Currency  `json:"currency"`
+	Status         string    `json:"status"`
+}
+
+// Webhook Entity
+// WebhookEventTypes ชนิด event ที่ partner สามารถสมัครรับได้
+var WebhookEventTypes = []string{
+	EventOrderCreated,
+	EventOrderStatusChanged,
+	EventOrderShippingStatusChanged,
+	EventPaymentCompleted,
+	EventPaymentFailed,
+	EventPaymentRefunded,
+	EventProductLowStock,
+}
+
+type WebhookEndpoint struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"event_types"`
+	Secret      string    `json:"secret,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscribes ตรวจสอบว่า endpoint สมัครรับ event ชนิดนี้หรือไม่
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, t := range e.EventTypes {
+		if t == eventType || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `json:"response_body"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	RedeliveryOf   *uuid.UUID      `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// WebhookDeliveryResult ผลการส่ง webhook หนึ่งครั้ง
+type WebhookDeliveryResult struct {
+	ResponseStatus int
+	ResponseBody   string
+	Error          string
+}
+
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1"`
+}
+
+type UpdateWebhookEndpointRequest struct {
+	URL         string   `json:"url" validate:"omitempty,url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	Active      *bool    `json:"active"`
+}
+
 // Common Response Types
 type PaginationResponse struct {
 	Page       int `json:"page"`
@@ -322,4 +752,4 @@ type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 	Error   string `json:"error,omitempty"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistItem สินค้าในรายการโปรดของผู้ใช้
+type WishlistItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Product   *Product  `json:"product,omitempty"`
+	// InStock สินค้ามีสต็อกพร้อมย้ายลงตะกร้าหรือไม่
+	InStock bool      `json:"in_stock"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type AddToWishlistRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+}
+
+// MoveToCartRequest ย้ายสินค้าจากรายการโปรดลงตะกร้า จำนวนเริ่มต้นคือ 1
+type MoveToCartRequest struct {
+	Quantity int `json:"quantity" validate:"omitempty,min=1"`
+}
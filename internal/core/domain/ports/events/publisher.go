@@ -0,0 +1,19 @@
+package events
+
+import (
+	"context"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// Publisher interface สำหรับส่ง domain event เข้าสู่ระบบ (ผ่าน outbox)
+type Publisher interface {
+	Publish(ctx context.Context, events ...entities.DomainEvent) error
+}
+
+// Sink interface ปลายทางที่ dispatcher จะส่ง event ไปให้
+// การส่งเป็นแบบ at-least-once ดังนั้น sink ควรใช้ event ID ป้องกันการประมวลผลซ้ำ
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event entities.DomainEvent) error
+}
@@ -0,0 +1,13 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// ExchangeRateProvider interface สำหรับแหล่งอัตราแลกเปลี่ยน (ไฟล์, ธนาคาร, API ภายนอก)
+type ExchangeRateProvider interface {
+	// Rate อัตราแลกเปลี่ยน 1 หน่วย base เป็น quote คืน entities.ErrUnsupportedCurrency หากไม่มีอัตรา
+	Rate(ctx context.Context, base, quote entities.Currency) (entities.ExchangeRate, error)
+}
@@ -0,0 +1,14 @@
+package gateways
+
+import (
+	"context"
+	"io"
+)
+
+// FileStorage interface สำหรับที่เก็บไฟล์ที่อัปโหลด (ดิสก์ในเครื่อง, S3, GCS)
+type FileStorage interface {
+	// Save บันทึกไฟล์ตาม key (เช่น avatars/<user>/<name>.png) และคืน URL สาธารณะของไฟล์
+	Save(ctx context.Context, key, contentType string, r io.Reader) (string, error)
+	// Delete ลบไฟล์ตาม URL ที่ได้จาก Save ไม่ถือเป็นข้อผิดพลาดหากไม่พบไฟล์หรือเป็น URL ภายนอก
+	Delete(ctx context.Context, url string) error
+}
@@ -0,0 +1,13 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// Mailer interface สำหรับส่งอีเมลตามเทมเพลต (SMTP, ไฟล์, console)
+type Mailer interface {
+	// Send ส่งอีเมล อาจส่งแบบ asynchronous โดยคืนค่าทันทีหลังรับงานเข้าคิว
+	Send(ctx context.Context, email entities.Email) error
+}
@@ -0,0 +1,27 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+var (
+	// ErrInvalidSignature callback ไม่ผ่านการตรวจลายเซ็น
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnknownProvider ไม่มี gateway ตามชื่อที่ระบุ
+	ErrUnknownProvider = errors.New("unknown payment provider")
+)
+
+// PaymentGateway interface สำหรับผู้ให้บริการรับชำระเงินภายนอก
+type PaymentGateway interface {
+	// Name ชื่อ provider ที่ใช้ใน path /payments/webhook/:provider
+	Name() string
+	CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error)
+	Capture(ctx context.Context, providerRef string, amount entities.Money) (*entities.GatewayCapture, error)
+	Refund(ctx context.Context, providerRef string, amount entities.Money, reason string) (*entities.GatewayRefund, error)
+	// ParseWebhook ตรวจลายเซ็นแล้วแปลง callback เป็น event คืน ErrInvalidSignature หากไม่ผ่าน
+	ParseWebhook(ctx context.Context, headers http.Header, body []byte) (*entities.PaymentWebhookEvent, error)
+}
@@ -0,0 +1,18 @@
+package gateways
+
+import (
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// TokenIssuer interface สำหรับลงนามและตรวจสอบ access token (JWT แบบกุญแจอสมมาตร)
+// ลงนามด้วยกุญแจปัจจุบันหนึ่งดอก และตรวจสอบได้ด้วยทุกกุญแจที่ยังใช้งานอยู่เพื่อรองรับการหมุนกุญแจ
+type TokenIssuer interface {
+	// Issue ลงนาม access token อายุ ttl ค่า ID (jti), IssuedAt และ ExpiresAt ของ claims ถูกกำหนดให้ใหม่
+	Issue(claims entities.AccessToken, ttl time.Duration) (string, *entities.AccessToken, error)
+	// Verify ตรวจสอบลายเซ็นด้วยกุญแจตาม kid และวันหมดอายุ คืน entities.ErrInvalidToken หากไม่ถูกต้อง
+	Verify(token string) (*entities.AccessToken, error)
+	// JWKS กุญแจสาธารณะทั้งหมดที่ใช้ตรวจสอบ access token ได้
+	JWKS() entities.JWKS
+}
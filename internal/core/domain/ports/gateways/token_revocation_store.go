@@ -0,0 +1,25 @@
+package gateways
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// TokenRevocationStore interface สำหรับยกเลิก access token ก่อนหมดอายุ (in-memory, Postgres)
+// ยกเลิกได้สามแบบ: ทีละ token ด้วย jti (deny-list), ทุก token ของ session ด้วย sid
+// หรือทุก token ของผู้ใช้ด้วยการเพิ่มรุ่นของ token
+type TokenRevocationStore interface {
+	// RevokeToken ปฏิเสธ access token ที่มี jti นี้จนถึง expiresAt (เวลาหมดอายุของ token)
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeSession ปฏิเสธ access token ทุกตัวที่มี sid นี้จนถึง until (token ล่าสุดของ session หมดอายุ)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, until time.Time) error
+	// TokenVersion รุ่นปัจจุบันของ token ของผู้ใช้ (0 หากยังไม่เคยถูกยกเลิก) ใส่ไว้ใน token ที่ออกใหม่
+	TokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+	// RevokeUserTokens เพิ่มรุ่นของ token ของผู้ใช้ access token ที่ออกก่อนหน้าทั้งหมดใช้ไม่ได้อีก
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID) error
+	// IsRevoked token ถูกยกเลิกหาก jti หรือ sid อยู่ใน deny-list หรือ version ไม่ตรงกับรุ่นปัจจุบันของผู้ใช้
+	IsRevoked(ctx context.Context, token entities.AccessToken) (bool, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
@@ -16,12 +17,26 @@ type UserRepository interface {
 	Update(ctx context.Context, id uuid.UUID, user *entities.UpdateUserRequest) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
-	SetRefreshToken(ctx context.Context, id uuid.UUID, token string) error
-	GetByRefreshToken(ctx context.Context, token string) (*entities.User, error)
-	SetResetToken(ctx context.Context, email string, token string) error
-	GetByResetToken(ctx context.Context, token string) (*entities.User, error)
+	// token ทั้งหมดในอีเมล (รีเซ็ตรหัสผ่าน เปลี่ยนอีเมล ยืนยันอีเมล) ถูกส่งเข้ามาเป็น hash (utils.HashToken)
+	SetResetToken(ctx context.Context, email string, tokenHash string) error
+	GetByResetToken(ctx context.Context, tokenHash string) (*entities.User, error)
 	ClearResetToken(ctx context.Context, id uuid.UUID) error
 	GetPasswordHash(ctx context.Context, id uuid.UUID) (string, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, roleID uuid.UUID) error
+	UpdateProfile(ctx context.Context, id uuid.UUID, req *entities.UpdateProfileRequest) error
+	SetAvatar(ctx context.Context, id uuid.UUID, url string) error
+	// SetEmailChange บันทึกอีเมลใหม่ที่รอยืนยันพร้อม token (แทนที่คำขอเดิมที่ยังไม่ยืนยัน)
+	SetEmailChange(ctx context.Context, id uuid.UUID, email, tokenHash string, expiry time.Time) error
+	// GetByEmailChangeToken คืนผู้ใช้และอีเมลที่รอยืนยันของ token ที่ยังไม่หมดอายุ
+	GetByEmailChangeToken(ctx context.Context, tokenHash string) (*entities.User, string, error)
+	// ConfirmEmailChange เปลี่ยนอีเมล ล้างคำขอที่รอยืนยันและยกเลิกทุก session ของผู้ใช้ อีเมลใหม่ถือว่ายืนยันแล้ว
+	ConfirmEmailChange(ctx context.Context, id uuid.UUID, email string) error
+	// SetVerificationToken บันทึก token ยืนยันอีเมล (แทนที่ token เดิม) คืน entities.ErrVerificationRecentlySent หากเพิ่งส่งไป
+	SetVerificationToken(ctx context.Context, id uuid.UUID, tokenHash string, expiry time.Time) error
+	// VerifyEmail ยืนยันอีเมลด้วย token ที่ยังไม่หมดอายุและยังไม่ถูกใช้ แล้วคืนผู้ใช้
+	VerifyEmail(ctx context.Context, tokenHash string) (*entities.User, error)
+	// Anonymize ลบข้อมูลส่วนตัวของผู้ใช้และ soft delete บัญชี คำสั่งซื้อยังคงอยู่เพื่อการบัญชี
+	Anonymize(ctx context.Context, id uuid.UUID) error
 }
 
 // RoleRepository interface สำหรับการจัดการบทบาท
@@ -32,6 +47,10 @@ type RoleRepository interface {
 	GetAll(ctx context.Context) ([]*entities.Role, error)
 	Update(ctx context.Context, id uuid.UUID, role *entities.Role) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	SetPermissions(ctx context.Context, id uuid.UUID, permissionIDs []uuid.UUID) error
+	GetPermissionNames(ctx context.Context, roleName string) ([]string, error)
+	CountUsers(ctx context.Context, id uuid.UUID) (int64, error)
+	GetUserIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
 }
 
 // PermissionRepository interface สำหรับการจัดการสิทธิ์
@@ -39,6 +58,7 @@ type PermissionRepository interface {
 	Create(ctx context.Context, permission *entities.Permission) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Permission, error)
 	GetByName(ctx context.Context, name string) (*entities.Permission, error)
+	GetByNames(ctx context.Context, names []string) ([]*entities.Permission, error)
 	GetAll(ctx context.Context) ([]*entities.Permission, error)
 	Update(ctx context.Context, id uuid.UUID, permission *entities.Permission) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -69,23 +89,44 @@ type ProductRepository interface {
 // CartRepository interface สำหรับการจัดการตะกร้าสินค้า
 type CartRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
-	AddItem(ctx context.Context, userID uuid.UUID, item *entities.AddToCartRequest) error
-	UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int) error
+	// AddItem และ UpdateItem จองสต็อกให้ตะกร้าจนถึง holdUntil (ค่า zero คือไม่จอง)
+	// AddItem คิดราคาตาม pricing ซึ่งต้องเป็นสกุลเงินเดียวกับตะกร้า (ไม่เช่นนั้นคืน entities.ErrCartCurrencyChanged)
+	AddItem(ctx context.Context, userID uuid.UUID, item *entities.AddToCartRequest, pricing entities.PriceContext, holdUntil time.Time) error
+	UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int, holdUntil time.Time) error
 	RemoveItem(ctx context.Context, cartItemID uuid.UUID) error
 	ClearCart(ctx context.Context, userID uuid.UUID) error
 	GetCartItem(ctx context.Context, cartItemID uuid.UUID) (*entities.CartItem, error)
+	// SetCurrency เปลี่ยนสกุลเงินของตะกร้าและคิดราคาทุกรายการใหม่ตาม pricing
+	SetCurrency(ctx context.Context, userID uuid.UUID, pricing entities.PriceContext) (*entities.Cart, error)
+	// SetCoupon กำหนดคูปองที่ลูกค้ากรอกให้ตะกร้า (nil คือเอาคูปองออก)
+	SetCoupon(ctx context.Context, userID uuid.UUID, couponID *uuid.UUID) error
 }
 
 // OrderRepository interface สำหรับการจัดการคำสั่งซื้อ
 type OrderRepository interface {
-	Create(ctx context.Context, userID uuid.UUID, order *entities.CreateOrderRequest) (*entities.Order, error)
+	// Create ตัดสต็อกและจองไว้ให้คำสั่งซื้อจนถึง checkout.PaymentDueAt
+	// ราคาทุกรายการคิดใหม่ด้วย checkout.Pricing และตรึงสกุลเงินกับอัตราแลกเปลี่ยนไว้กับคำสั่งซื้อ
+	// ส่วนลดคิดใหม่และบันทึกการใช้คูปองใน transaction เดียวกัน คืน entities.ErrCouponNotApplicable
+	// หรือ entities.ErrCouponUsageLimit เมื่อคูปองในตะกร้าใช้ไม่ได้แล้ว
+	Create(ctx context.Context, userID uuid.UUID, order *entities.CreateOrderRequest, checkout *entities.Checkout) (*entities.Order, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Order, int, error)
 	GetAll(ctx context.Context, page, limit int) ([]*entities.Order, int, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error
-	UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus, trackingNumber string) error
-	Cancel(ctx context.Context, id uuid.UUID) error
+	// UpdateStatus, UpdatePaymentStatus, UpdateShippingStatus และ Cancel ตรวจสอบ state machine
+	// คืน *entities.InvalidTransitionError เมื่อเปลี่ยนสถานะไม่ได้ และบันทึกประวัติโดย changedBy (nil คือระบบ)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, changedBy *uuid.UUID, note string) error
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string, changedBy *uuid.UUID, note string) error
+	UpdateShippingStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingStatusRequest, changedBy *uuid.UUID) error
+	Cancel(ctx context.Context, id uuid.UUID, changedBy *uuid.UUID) error
+	CreateShipment(ctx context.Context, orderID uuid.UUID, req *entities.CreateShipmentRequest, changedBy *uuid.UUID) (*entities.Shipment, error)
+	UpdateShipment(ctx context.Context, orderID, shipmentID uuid.UUID, req *entities.UpdateShipmentRequest, changedBy *uuid.UUID) (*entities.Shipment, error)
+}
+
+// InventoryRepository interface สำหรับการจองสต็อกและคืนสต็อกของคำสั่งซื้อที่หมดเวลาชำระเงิน
+type InventoryRepository interface {
+	GetStockLevel(ctx context.Context, productID uuid.UUID) (*entities.StockLevel, error)
+	ExpireUnpaidOrders(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	PurgeExpiredCartHolds(ctx context.Context, now time.Time) (int64, error)
 }
 
 // TransactionRepository interface สำหรับการจัดการธุรกรรม
@@ -94,8 +135,15 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Transaction, error)
 	GetByTransactionID(ctx context.Context, transactionID string) (*entities.Transaction, error)
+	GetByProviderRef(ctx context.Context, provider, providerRef string) (*entities.Transaction, error)
+	SetProviderRef(ctx context.Context, id uuid.UUID, provider, providerRef string) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	Cancel(ctx context.Context, id uuid.UUID) error
+	// CreateRefund จองยอดคืนเงินสถานะ pending โดยตรวจไม่ให้เกินยอดที่ชำระแล้ว
+	CreateRefund(ctx context.Context, transactionID uuid.UUID, req *entities.CreateRefundRequest, createdBy uuid.UUID) (*entities.Refund, error)
+	// CompleteRefund ยืนยันการคืนเงิน คืนสต็อก และอัพเดทสถานะการชำระเงินของคำสั่งซื้อ
+	CompleteRefund(ctx context.Context, refundID uuid.UUID, providerRef string) (*entities.Refund, error)
+	FailRefund(ctx context.Context, refundID uuid.UUID) error
 }
 
 // StatsRepository interface สำหรับสถิติ
@@ -103,4 +151,107 @@ type StatsRepository interface {
 	GetSalesStats(ctx context.Context) (*entities.SalesStats, error)
 	GetProductStats(ctx context.Context) (*entities.ProductStats, error)
 	GetUserStats(ctx context.Context) (*entities.UserStats, error)
-}
\ No newline at end of file
+}
+
+// OutboxRepository interface สำหรับจัดการตาราง outbox ของ domain event
+type OutboxRepository interface {
+	Publish(ctx context.Context, events ...entities.DomainEvent) error
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]*entities.OutboxMessage, error)
+	MarkDispatched(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt *time.Time, deliveredSinks []string) error
+}
+
+// WebhookRepository interface สำหรับจัดการ webhook endpoint และประวัติการส่ง
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *entities.WebhookEndpoint) error
+	GetEndpointByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, page, limit int) ([]*entities.WebhookEndpoint, int, error)
+	GetActiveEndpoints(ctx context.Context) ([]*entities.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id uuid.UUID, req *entities.UpdateWebhookEndpointRequest) error
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+	CreateDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entities.WebhookDelivery, error)
+	GetDeliveriesByEndpoint(ctx context.Context, endpointID uuid.UUID, page, limit int) ([]*entities.WebhookDelivery, int, error)
+	ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*entities.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, id uuid.UUID, attempts int, result *entities.WebhookDeliveryResult, delivered bool, nextAttemptAt *time.Time) error
+}
+
+// TaxRepository interface สำหรับจัดการประเภทภาษีและอัตราภาษีแยกตามภูมิภาค
+type TaxRepository interface {
+	CreateClass(ctx context.Context, class *entities.TaxClass) error
+	GetClassByID(ctx context.Context, id uuid.UUID) (*entities.TaxClass, error)
+	GetClassByName(ctx context.Context, name string) (*entities.TaxClass, error)
+	GetClasses(ctx context.Context) ([]*entities.TaxClass, error)
+	UpdateClass(ctx context.Context, id uuid.UUID, class *entities.TaxClass) error
+	DeleteClass(ctx context.Context, id uuid.UUID) error
+	CountClassUsage(ctx context.Context, id uuid.UUID) (int64, error)
+	CreateRate(ctx context.Context, rate *entities.TaxRate) error
+	GetRateByID(ctx context.Context, id uuid.UUID) (*entities.TaxRate, error)
+	UpdateRate(ctx context.Context, id uuid.UUID, rate *entities.TaxRate) error
+	DeleteRate(ctx context.Context, id uuid.UUID) error
+}
+
+// CouponRepository interface สำหรับจัดการคูปองและส่วนลดอัตโนมัติ
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *entities.Coupon) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Coupon, error)
+	GetByCode(ctx context.Context, code string) (*entities.Coupon, error)
+	GetAll(ctx context.Context, page, limit int) ([]*entities.Coupon, int, error)
+	// GetAutomatic ส่วนลดอัตโนมัติที่เปิดใช้งานอยู่ทั้งหมด
+	GetAutomatic(ctx context.Context) ([]*entities.Coupon, error)
+	Update(ctx context.Context, id uuid.UUID, coupon *entities.Coupon) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// CountUserRedemptions จำนวนครั้งที่ผู้ใช้ใช้คูปองแต่ละใบ (ไม่นับคำสั่งซื้อที่ถูกยกเลิก)
+	CountUserRedemptions(ctx context.Context, userID uuid.UUID, couponIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// ShippingRepository interface สำหรับจัดการวิธีจัดส่งและอัตราค่าจัดส่ง
+type ShippingRepository interface {
+	CreateMethod(ctx context.Context, method *entities.ShippingMethod) error
+	GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error)
+	GetMethodByCode(ctx context.Context, code string) (*entities.ShippingMethod, error)
+	// GetMethods วิธีจัดส่งทั้งหมดพร้อมอัตรา เรียงตาม sort_order (activeOnly เฉพาะที่เปิดใช้งาน)
+	GetMethods(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error)
+	UpdateMethod(ctx context.Context, id uuid.UUID, method *entities.ShippingMethod) error
+	DeleteMethod(ctx context.Context, id uuid.UUID) error
+	CreateRate(ctx context.Context, rate *entities.ShippingRate) error
+	GetRateByID(ctx context.Context, id uuid.UUID) (*entities.ShippingRate, error)
+	UpdateRate(ctx context.Context, id uuid.UUID, rate *entities.ShippingRate) error
+	DeleteRate(ctx context.Context, id uuid.UUID) error
+}
+
+// AddressRepository interface สำหรับจัดการสมุดที่อยู่ของผู้ใช้
+type AddressRepository interface {
+	// Create และ Update ยกเลิกค่าเริ่มต้นของที่อยู่อื่นของผู้ใช้เมื่อที่อยู่นี้เป็นค่าเริ่มต้น
+	Create(ctx context.Context, address *entities.Address) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Address, error)
+	// GetByUserID ที่อยู่ทั้งหมดของผู้ใช้ ที่อยู่เริ่มต้นอยู่ก่อน
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error)
+	Update(ctx context.Context, id uuid.UUID, address *entities.Address) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// WishlistRepository interface สำหรับจัดการรายการโปรดของผู้ใช้ (ตาราง user_wishlist)
+type WishlistRepository interface {
+	// GetByUserID สินค้าในรายการโปรดที่ยังไม่ถูกลบ เพิ่มล่าสุดอยู่ก่อน
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.WishlistItem, error)
+	Exists(ctx context.Context, userID, productID uuid.UUID) (bool, error)
+	// Add ไม่ถือเป็นข้อผิดพลาดหากสินค้าอยู่ในรายการโปรดอยู่แล้ว
+	Add(ctx context.Context, userID, productID uuid.UUID) error
+	Remove(ctx context.Context, userID, productID uuid.UUID) error
+}
+
+// SessionRepository interface สำหรับจัดการ session ต่ออุปกรณ์ของผู้ใช้ (ตาราง user_sessions)
+// refresh token ถูกส่งเข้ามาเป็น hash เสมอ ไม่มีการเก็บ token จริง
+type SessionRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, client entities.ClientInfo, expiresAt time.Time) (*entities.Session, error)
+	// Rotate แทนที่ refresh token ของ session ที่ยังไม่ถูกยกเลิกและยังไม่หมดอายุด้วย token ใหม่ ต่ออายุถึง expiresAt และจำ token เก่าไว้
+	// หาก oldHash เป็น token ที่ถูกหมุนไปแล้ว session นั้นถูกยกเลิกและคืน entities.RefreshTokenReusedError (errors.Is ErrRefreshTokenReused)
+	Rotate(ctx context.Context, oldHash, newHash string, client entities.ClientInfo, expiresAt time.Time) (*entities.Session, error)
+	// GetActiveByUserID session ที่ยังไม่ถูกยกเลิกและยังไม่หมดอายุของผู้ใช้ ใช้งานล่าสุดอยู่ก่อน
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Session, error)
+	// Revoke คืน entities.ErrNotFound หากไม่พบ session ที่ยังใช้งานได้ของผู้ใช้
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	// RevokeAll ยกเลิกทุก session ของผู้ใช้ คืนจำนวน session ที่ถูกยกเลิก
+	RevokeAll(ctx context.Context, userID uuid.UUID) (int, error)
+}
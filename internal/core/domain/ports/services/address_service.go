@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// AddressService interface สำหรับจัดการสมุดที่อยู่ของผู้ใช้
+type AddressService interface {
+	GetAddresses(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error)
+	GetAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error)
+	CreateAddress(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error)
+	UpdateAddress(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) (*entities.Address, error)
+	DeleteAddress(ctx context.Context, userID, id uuid.UUID) error
+}
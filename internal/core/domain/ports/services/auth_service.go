@@ -11,11 +11,23 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, req *entities.RegisterRequest) (*entities.User, error)
 	AdminRegister(ctx context.Context, req *entities.AdminRegisterRequest) (*entities.User, error)
-	Login(ctx context.Context, req *entities.LoginRequest) (*entities.LoginResponse, error)
-	RefreshToken(ctx context.Context, req *entities.RefreshTokenRequest) (*entities.LoginResponse, error)
-	Logout(ctx context.Context, userID uuid.UUID) error
+	// Login สร้าง session ใหม่สำหรับอุปกรณ์ที่เข้าสู่ระบบ
+	Login(ctx context.Context, req *entities.LoginRequest, client entities.ClientInfo) (*entities.LoginResponse, error)
+	// RefreshToken หมุน refresh token ของ session การใช้ token ที่ถูกหมุนไปแล้วซ้ำจะยกเลิก session นั้น
+	RefreshToken(ctx context.Context, req *entities.RefreshTokenRequest, client entities.ClientInfo) (*entities.LoginResponse, error)
+	// Logout ยกเลิก session และ access token ที่ใช้ออกจากระบบ หรือทุก session และ token ของผู้ใช้หาก token ไม่มี session
+	Logout(ctx context.Context, token entities.AccessToken) error
 	ChangePassword(ctx context.Context, userID uuid.UUID, req *entities.ChangePasswordRequest) error
 	ForgotPassword(ctx context.Context, req *entities.ForgotPasswordRequest) error
 	ResetPassword(ctx context.Context, req *entities.ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, req *entities.VerifyEmailRequest) (*entities.User, error)
+	ResendVerification(ctx context.Context, req *entities.ResendVerificationRequest) error
 	ValidateToken(ctx context.Context, token string) (*entities.User, error)
-}
\ No newline at end of file
+	// JWKS กุญแจสาธารณะที่ใช้ตรวจสอบ access token (กุญแจปัจจุบันและกุญแจเก่าระหว่างหมุนกุญแจ)
+	JWKS() entities.JWKS
+	// ListSessions session ที่ยังใช้งานได้ของผู้ใช้ โดยระบุ session ปัจจุบัน
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entities.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	// RevokeAllSessions ออกจากระบบทุกอุปกรณ์ของผู้ใช้ (สำหรับผู้ดูแล) รวมถึง access token ที่ออกไปแล้ว คืนจำนวน session ที่ถูกยกเลิก
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) (int, error)
+}
@@ -11,7 +11,11 @@ import (
 type CartService interface {
 	GetCart(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
 	AddToCart(ctx context.Context, userID uuid.UUID, req *entities.AddToCartRequest) error
-	UpdateCartItem(ctx context.Context, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error
-	RemoveFromCart(ctx context.Context, cartItemID uuid.UUID) error
+	UpdateCartItem(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error
+	RemoveFromCart(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID) error
 	ClearCart(ctx context.Context, userID uuid.UUID) error
-}
\ No newline at end of file
+	SetCurrency(ctx context.Context, userID uuid.UUID, req *entities.SetCartCurrencyRequest) (*entities.Cart, error)
+	ApplyCoupon(ctx context.Context, userID uuid.UUID, req *entities.ApplyCouponRequest) (*entities.Cart, error)
+	RemoveCoupon(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
+	ShippingOptions(ctx context.Context, userID uuid.UUID, region string) ([]entities.ShippingQuote, error)
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// CouponService interface สำหรับจัดการคูปองและส่วนลดอัตโนมัติ
+type CouponService interface {
+	GetCoupons(ctx context.Context, page, limit int) ([]*entities.Coupon, *entities.PaginationResponse, error)
+	GetCouponByID(ctx context.Context, id uuid.UUID) (*entities.Coupon, error)
+	CreateCoupon(ctx context.Context, req *entities.CreateCouponRequest) (*entities.Coupon, error)
+	UpdateCoupon(ctx context.Context, id uuid.UUID, req *entities.UpdateCouponRequest) (*entities.Coupon, error)
+	DeleteCoupon(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// NotificationService interface สำหรับส่งอีเมลแจ้งเตือนผู้ใช้
+type NotificationService interface {
+	SendPasswordReset(ctx context.Context, user *entities.User, token string, ttl time.Duration) error
+	SendEmailVerification(ctx context.Context, user *entities.User, token string, ttl time.Duration) error
+	SendEmailChange(ctx context.Context, user *entities.User, newEmail, token string, ttl time.Duration) error
+	// HandleEvent ส่งอีเมลตาม domain event (คำสั่งซื้อ การจัดส่ง การชำระเงิน สินค้ากลับมามีสต็อก)
+	HandleEvent(ctx context.Context, event entities.DomainEvent) error
+}
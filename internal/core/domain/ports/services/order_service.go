@@ -11,10 +11,12 @@ import (
 type OrderService interface {
 	CreateOrder(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (*entities.Order, error)
 	GetOrders(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error)
-	GetOrderByID(ctx context.Context, id uuid.UUID) (*entities.Order, error)
-	CancelOrder(ctx context.Context, id uuid.UUID) error
+	GetOrderByID(ctx context.Context, actor entities.Actor, id uuid.UUID) (*entities.Order, error)
+	CancelOrder(ctx context.Context, actor entities.Actor, id uuid.UUID) error
 	GetAllOrders(ctx context.Context, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error)
-	UpdateOrderStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateOrderStatusRequest) error
-	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, req *entities.UpdatePaymentStatusRequest) error
-	UpdateShippingStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingStatusRequest) error
-}
\ No newline at end of file
+	UpdateOrderStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdateOrderStatusRequest) error
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdatePaymentStatusRequest) error
+	UpdateShippingStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdateShippingStatusRequest) error
+	CreateShipment(ctx context.Context, orderID uuid.UUID, changedBy uuid.UUID, req *entities.CreateShipmentRequest) (*entities.Shipment, error)
+	UpdateShipment(ctx context.Context, orderID, shipmentID uuid.UUID, changedBy uuid.UUID, req *entities.UpdateShipmentRequest) (*entities.Shipment, error)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
@@ -9,8 +10,10 @@ import (
 
 // PaymentService interface สำหรับการจัดการการชำระเงิน
 type PaymentService interface {
-	CreatePayment(ctx context.Context, req *entities.CreatePaymentRequest) (*entities.Transaction, error)
+	CreatePayment(ctx context.Context, actor entities.Actor, req *entities.CreatePaymentRequest) (*entities.Transaction, error)
 	GetPaymentByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
-	VerifyPayment(ctx context.Context, id uuid.UUID, req *entities.VerifyPaymentRequest) error
-	CancelPayment(ctx context.Context, id uuid.UUID) error
-}
\ No newline at end of file
+	VerifyPayment(ctx context.Context, actor entities.Actor, id uuid.UUID, req *entities.VerifyPaymentRequest) (*entities.Transaction, error)
+	CancelPayment(ctx context.Context, actor entities.Actor, id uuid.UUID) error
+	HandleWebhook(ctx context.Context, provider string, headers http.Header, body []byte) error
+	RefundPayment(ctx context.Context, id uuid.UUID, adminID uuid.UUID, req *entities.CreateRefundRequest) (*entities.Refund, error)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// RBACService interface สำหรับตรวจสิทธิ์และจัดการบทบาท/สิทธิ์
+type RBACService interface {
+	HasPermission(ctx context.Context, roleName, permission string) (bool, error)
+	// RolePermissions ชื่อสิทธิ์ทั้งหมดของบทบาท สำหรับตรวจสิทธิ์ข้ามความเป็นเจ้าของใน service (entities.Actor)
+	RolePermissions(ctx context.Context, roleName string) ([]string, error)
+	InvalidateCache()
+
+	GetRoles(ctx context.Context) ([]*entities.Role, error)
+	GetRoleByID(ctx context.Context, id uuid.UUID) (*entities.Role, error)
+	CreateRole(ctx context.Context, req *entities.CreateRoleRequest) (*entities.Role, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, req *entities.UpdateRoleRequest) error
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	SetRolePermissions(ctx context.Context, id uuid.UUID, req *entities.SetRolePermissionsRequest) (*entities.Role, error)
+
+	GetPermissions(ctx context.Context) ([]*entities.Permission, error)
+	CreatePermission(ctx context.Context, req *entities.CreatePermissionRequest) (*entities.Permission, error)
+	UpdatePermission(ctx context.Context, id uuid.UUID, req *entities.UpdatePermissionRequest) error
+	DeletePermission(ctx context.Context, id uuid.UUID) error
+
+	AssignUserRole(ctx context.Context, userID uuid.UUID, req *entities.AssignRoleRequest) error
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// ShippingService interface สำหรับจัดการวิธีจัดส่งและอัตราค่าจัดส่ง
+type ShippingService interface {
+	GetMethods(ctx context.Context) ([]*entities.ShippingMethod, error)
+	GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error)
+	CreateMethod(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error)
+	UpdateMethod(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) error
+	DeleteMethod(ctx context.Context, id uuid.UUID) error
+
+	CreateRate(ctx context.Context, req *entities.CreateShippingRateRequest) (*entities.ShippingRate, error)
+	UpdateRate(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingRateRequest) error
+	DeleteRate(ctx context.Context, id uuid.UUID) error
+}
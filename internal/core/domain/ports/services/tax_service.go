@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// TaxService interface สำหรับจัดการประเภทภาษีและอัตราภาษี
+type TaxService interface {
+	GetClasses(ctx context.Context) ([]*entities.TaxClass, error)
+	GetClassByID(ctx context.Context, id uuid.UUID) (*entities.TaxClass, error)
+	CreateClass(ctx context.Context, req *entities.CreateTaxClassRequest) (*entities.TaxClass, error)
+	UpdateClass(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxClassRequest) error
+	DeleteClass(ctx context.Context, id uuid.UUID) error
+
+	CreateRate(ctx context.Context, req *entities.CreateTaxRateRequest) (*entities.TaxRate, error)
+	UpdateRate(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRateRequest) error
+	DeleteRate(ctx context.Context, id uuid.UUID) error
+}
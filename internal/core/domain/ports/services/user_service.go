@@ -13,4 +13,11 @@ type UserService interface {
 	GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	UpdateUser(ctx context.Context, id uuid.UUID, req *entities.UpdateUserRequest) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
-}
\ No newline at end of file
+
+	// บริการสำหรับผู้ใช้จัดการบัญชีของตัวเอง (/me)
+	UpdateProfile(ctx context.Context, id uuid.UUID, req *entities.UpdateProfileRequest) (*entities.User, error)
+	UploadAvatar(ctx context.Context, id uuid.UUID, upload *entities.AvatarUpload) (*entities.User, error)
+	RequestEmailChange(ctx context.Context, id uuid.UUID, req *entities.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, id uuid.UUID, req *entities.ConfirmEmailChangeRequest) (*entities.User, error)
+	DeleteAccount(ctx context.Context, id uuid.UUID, req *entities.DeleteAccountRequest) error
+}
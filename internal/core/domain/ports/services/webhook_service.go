@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// WebhookService interface สำหรับจัดการ webhook ของ partner
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, req *entities.CreateWebhookEndpointRequest) (*entities.WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, page, limit int) ([]*entities.WebhookEndpoint, *entities.PaginationResponse, error)
+	GetEndpointByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, id uuid.UUID, req *entities.UpdateWebhookEndpointRequest) error
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, endpointID uuid.UUID, page, limit int) ([]*entities.WebhookDelivery, *entities.PaginationResponse, error)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entities.WebhookDelivery, error)
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// WishlistService interface สำหรับจัดการรายการโปรดของผู้ใช้
+type WishlistService interface {
+	GetWishlist(ctx context.Context, userID uuid.UUID) ([]*entities.WishlistItem, error)
+	AddToWishlist(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) error
+	RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error
+	MoveToCart(ctx context.Context, userID, productID uuid.UUID, req *entities.MoveToCartRequest) error
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type addressService struct {
+	addressRepo repositories.AddressRepository
+}
+
+func NewAddressService(addressRepo repositories.AddressRepository) services.AddressService {
+	return &addressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func (s *addressService) GetAddresses(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error) {
+	return s.addressRepo.GetByUserID(ctx, userID)
+}
+
+func (s *addressService) GetAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error) {
+	return s.getOwnAddress(ctx, userID, id)
+}
+
+// CreateAddress ที่อยู่แรกของผู้ใช้ (หรือเมื่อยังไม่มีที่อยู่เริ่มต้น) จะเป็นที่อยู่เริ่มต้นโดยอัตโนมัติ
+func (s *addressService) CreateAddress(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error) {
+	existing, err := s.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &entities.Address{
+		UserID: userID,
+		Label:  strings.TrimSpace(req.Label),
+		AddressFields: entities.AddressFields{
+			Recipient:   req.Recipient,
+			Phone:       req.Phone,
+			Line1:       req.Line1,
+			Line2:       req.Line2,
+			SubDistrict: req.SubDistrict,
+			District:    req.District,
+			Province:    req.Province,
+			PostalCode:  req.PostalCode,
+			Country:     req.Country,
+			RegionCode:  req.RegionCode,
+		},
+		IsDefaultShipping: req.IsDefaultShipping || defaultAddress(existing, entities.AddressKindShipping) == nil,
+		IsDefaultBilling:  req.IsDefaultBilling || defaultAddress(existing, entities.AddressKindBilling) == nil,
+	}
+	address.Normalize()
+
+	if err := s.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// UpdateAddress แก้ไขที่อยู่ในสมุดที่อยู่ คำสั่งซื้อเดิมเก็บสำเนาไว้แล้วจึงไม่เปลี่ยนตาม
+func (s *addressService) UpdateAddress(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) (*entities.Address, error) {
+	address, err := s.getOwnAddress(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		address.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.Recipient != "" {
+		address.Recipient = req.Recipient
+	}
+	if req.Phone != "" {
+		address.Phone = req.Phone
+	}
+	if req.Line1 != "" {
+		address.Line1 = req.Line1
+	}
+	if req.Line2 != nil {
+		address.Line2 = *req.Line2
+	}
+	if req.SubDistrict != nil {
+		address.SubDistrict = *req.SubDistrict
+	}
+	if req.District != nil {
+		address.District = *req.District
+	}
+	if req.Province != "" {
+		address.Province = req.Province
+	}
+	if req.PostalCode != "" {
+		address.PostalCode = req.PostalCode
+	}
+	if req.Country != "" {
+		address.Country = req.Country
+	}
+	if req.RegionCode != nil {
+		address.RegionCode = *req.RegionCode
+	}
+	if req.IsDefaultShipping != nil {
+		address.IsDefaultShipping = *req.IsDefaultShipping
+	}
+	if req.IsDefaultBilling != nil {
+		address.IsDefaultBilling = *req.IsDefaultBilling
+	}
+	address.Normalize()
+
+	if err := s.addressRepo.Update(ctx, id, address); err != nil {
+		return nil, err
+	}
+
+	return s.addressRepo.GetByID(ctx, id)
+}
+
+func (s *addressService) DeleteAddress(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.getOwnAddress(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return s.addressRepo.Delete(ctx, id)
+}
+
+// getOwnAddress ที่อยู่ของผู้อื่นได้ ErrNotFound เหมือนไม่มีอยู่ สมุดที่อยู่เป็นข้อมูลส่วนตัวจึงไม่มีข้อยกเว้นสำหรับ admin
+func (s *addressService) getOwnAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error) {
+	address, err := s.addressRepo.GetByID(ctx, id)
+	if err != nil || address.UserID != userID {
+		return nil, entities.ErrNotFound
+	}
+
+	return address, nil
+}
+
+// defaultAddress ที่อยู่เริ่มต้นของประเภท kind (nil หากไม่มี)
+func defaultAddress(addresses []*entities.Address, kind string) *entities.Address {
+	for _, address := range addresses {
+		if (kind == entities.AddressKindShipping && address.IsDefaultShipping) ||
+			(kind == entities.AddressKindBilling && address.IsDefaultBilling) {
+			return address
+		}
+	}
+	return nil
+}
+
+// findAddress ที่อยู่ตาม id ในสมุดที่อยู่ของผู้ใช้ คืน ErrNotFound หากไม่ใช่ที่อยู่ของผู้ใช้
+func findAddress(addresses []*entities.Address, id uuid.UUID) (*entities.Address, error) {
+	for _, address := range addresses {
+		if address.ID == id {
+			return address, nil
+		}
+	}
+	return nil, entities.ErrNotFound
+}
@@ -5,23 +5,47 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
 )
 
 type authService struct {
-	userRepo repositories.UserRepository
-	roleRepo repositories.RoleRepository
+	userRepo      repositories.UserRepository
+	roleRepo      repositories.RoleRepository
+	sessionRepo   repositories.SessionRepository
+	revocations   gateways.TokenRevocationStore
+	issuer        gateways.TokenIssuer
+	notifications services.NotificationService
+	verification  entities.EmailVerificationSettings
+	tokens        entities.TokenSettings
 }
 
-func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) services.AuthService {
+func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, sessionRepo repositories.SessionRepository, revocations gateways.TokenRevocationStore, issuer gateways.TokenIssuer, notifications services.NotificationService, verification entities.EmailVerificationSettings, tokens entities.TokenSettings) services.AuthService {
+	if verification.TokenTTL <= 0 {
+		verification.TokenTTL = 24 * time.Hour
+	}
+	if tokens.AccessTokenTTL <= 0 {
+		tokens.AccessTokenTTL = entities.DefaultAccessTokenTTL
+	}
+	if tokens.RefreshTokenTTL <= 0 {
+		tokens.RefreshTokenTTL = entities.DefaultRefreshTokenTTL
+	}
 	return &authService{
-		userRepo: userRepo,
-		roleRepo: roleRepo,
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		sessionRepo:   sessionRepo,
+		revocations:   revocations,
+		issuer:        issuer,
+		notifications: notifications,
+		verification:  verification,
+		tokens:        tokens,
 	}
 }
 
@@ -55,6 +79,7 @@ func (s *authService) Register(ctx context.Context, req *entities.RegisterReques
 		Phone:     req.Phone,
 		Address:   req.Address,
 		Active:    true,
+		Locale:    req.Locale,
 		RoleID:    userRole.ID,
 	}
 
@@ -62,6 +87,11 @@ func (s *authService) Register(ctx context.Context, req *entities.RegisterReques
 		return nil, err
 	}
 
+	// ส่งอีเมลยืนยัน หากส่งไม่สำเร็จผู้ใช้ขอส่งใหม่ได้ที่ /auth/resend-verification
+	if err := s.sendVerification(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
 	// ดึงข้อมูลผู้ใช้พร้อม role
 	return s.userRepo.GetByID(ctx, user.ID)
 }
@@ -103,6 +133,9 @@ func (s *authService) AdminRegister(ctx context.Context, req *entities.AdminRegi
 		Active:    true,
 		RoleID:    roleID,
 	}
+	// บัญชีที่ผู้ดูแลสร้างให้ถือว่ายืนยันอีเมลแล้ว
+	now := time.Now()
+	user.EmailVerifiedAt = &now
 
 	if err := s.userRepo.Create(ctx, user, hashedPassword); err != nil {
 		return nil, err
@@ -112,7 +145,7 @@ func (s *authService) AdminRegister(ctx context.Context, req *entities.AdminRegi
 	return s.userRepo.GetByID(ctx, user.ID)
 }
 
-func (s *authService) Login(ctx context.Context, req *entities.LoginRequest) (*entities.LoginResponse, error) {
+func (s *authService) Login(ctx context.Context, req *entities.LoginRequest, client entities.ClientInfo) (*entities.LoginResponse, error) {
 	// ค้นหาผู้ใช้ตามอีเมล
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
@@ -135,33 +168,45 @@ func (s *authService) Login(ctx context.Context, req *entities.LoginRequest) (*e
 		return nil, errors.New("อีเมลหรือรหัสผ่านไม่ถูกต้อง")
 	}
 
-	// สร้าง JWT token
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email, user.Role.Name)
-	if err != nil {
-		return nil, err
+	// ตรวจสอบการยืนยันอีเมล (เมื่อบังคับตั้งแต่เข้าสู่ระบบ)
+	if s.verification.BlocksLogin() && !user.EmailVerified() {
+		return nil, entities.ErrEmailNotVerified
 	}
 
-	// สร้าง refresh token
+	// สร้าง refresh token และ session ใหม่สำหรับอุปกรณ์นี้ (เก็บเฉพาะ hash ของ token)
 	refreshToken, err := s.generateRefreshToken()
 	if err != nil {
 		return nil, err
 	}
-
-	// บันทึก refresh token
-	if err := s.userRepo.SetRefreshToken(ctx, user.ID, refreshToken); err != nil {
+	session, err := s.sessionRepo.Create(ctx, user.ID, utils.HashToken(refreshToken), client, time.Now().Add(s.tokens.RefreshTokenTTL))
+	if err != nil {
 		return nil, err
 	}
 
-	return &entities.LoginResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         *user,
-	}, nil
+	return s.loginResponse(ctx, user, session, refreshToken)
 }
 
-func (s *authService) RefreshToken(ctx context.Context, req *entities.RefreshTokenRequest) (*entities.LoginResponse, error) {
-	// ค้นหาผู้ใช้ตาม refresh token
-	user, err := s.userRepo.GetByRefreshToken(ctx, req.RefreshToken)
+func (s *authService) RefreshToken(ctx context.Context, req *entities.RefreshTokenRequest, client entities.ClientInfo) (*entities.LoginResponse, error) {
+	// สร้าง refresh token ใหม่แล้วหมุนแทน token เดิมของ session
+	refreshToken, err := s.generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.sessionRepo.Rotate(ctx, utils.HashToken(req.RefreshToken), utils.HashToken(refreshToken), client, time.Now().Add(s.tokens.RefreshTokenTTL))
+	var reused *entities.RefreshTokenReusedError
+	if errors.As(err, &reused) {
+		log.Printf("Refresh token reuse detected, session %s revoked", reused.SessionID)
+		// access token ที่ออกให้ session นี้อาจอยู่ในมือผู้ขโมย token ด้วย
+		if revokeErr := s.revokeSessionTokens(ctx, reused.SessionID); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.New("refresh token ไม่ถูกต้อง")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return nil, errors.New("refresh token ไม่ถูกต้อง")
 	}
@@ -170,21 +215,28 @@ func (s *authService) RefreshToken(ctx context.Context, req *entities.RefreshTok
 	if !user.Active {
 		return nil, errors.New("บัญชีผู้ใช้ถูกระงับ")
 	}
-
-	// สร้าง JWT token ใหม่
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email, user.Role.Name)
-	if err != nil {
-		return nil, err
+	if s.verification.BlocksLogin() && !user.EmailVerified() {
+		return nil, entities.ErrEmailNotVerified
 	}
 
-	// สร้าง refresh token ใหม่
-	refreshToken, err := s.generateRefreshToken()
+	return s.loginResponse(ctx, user, session, refreshToken)
+}
+
+// loginResponse สร้าง JWT token ที่ผูกกับ session และรุ่นของ token ปัจจุบันของผู้ใช้ พร้อม refresh token ของ session
+func (s *authService) loginResponse(ctx context.Context, user *entities.User, session *entities.Session, refreshToken string) (*entities.LoginResponse, error) {
+	version, err := s.revocations.TokenVersion(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// บันทึก refresh token ใหม่
-	if err := s.userRepo.SetRefreshToken(ctx, user.ID, refreshToken); err != nil {
+	token, _, err := s.issuer.Issue(entities.AccessToken{
+		UserID:       user.ID,
+		Email:        user.Email,
+		Role:         user.Role.Name,
+		SessionID:    session.ID,
+		TokenVersion: version,
+	}, s.tokens.AccessTokenTTL)
+	if err != nil {
 		return nil, err
 	}
 
@@ -195,9 +247,66 @@ func (s *authService) RefreshToken(ctx context.Context, req *entities.RefreshTok
 	}, nil
 }
 
-func (s *authService) Logout(ctx context.Context, userID uuid.UUID) error {
-	// ลบ refresh token
-	return s.userRepo.SetRefreshToken(ctx, userID, "")
+func (s *authService) Logout(ctx context.Context, token entities.AccessToken) error {
+	// token ที่ไม่มี session (ออกก่อนมีระบบ session) ออกจากระบบทุกอุปกรณ์
+	if token.SessionID == uuid.Nil {
+		if _, err := s.sessionRepo.RevokeAll(ctx, token.UserID); err != nil {
+			return err
+		}
+		return s.revocations.RevokeUserTokens(ctx, token.UserID)
+	}
+
+	// session ที่ถูกยกเลิกไปแล้วถือว่าออกจากระบบสำเร็จ
+	if err := s.sessionRepo.Revoke(ctx, token.UserID, token.SessionID); err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return err
+	}
+	if err := s.revokeSessionTokens(ctx, token.SessionID); err != nil {
+		return err
+	}
+
+	// access token ที่ใช้ออกจากระบบใช้ไม่ได้อีกแม้ยังไม่หมดอายุ
+	if token.ID == "" {
+		return nil
+	}
+	return s.revocations.RevokeToken(ctx, token.ID, token.ExpiresAt)
+}
+
+// ListSessions session ที่ยังใช้งานได้ของผู้ใช้ ระบุ session ที่ใช้ทำรายการอยู่ด้วย Current
+func (s *authService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entities.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession ออกจากระบบอุปกรณ์หนึ่ง ผู้ใช้ยกเลิกได้เฉพาะ session ของตนเอง
+// access token ที่ออกให้ session นั้นใช้ไม่ได้ทันที
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.Revoke(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	return s.revokeSessionTokens(ctx, sessionID)
+}
+
+// revokeSessionTokens ปฏิเสธ access token ทุกตัวของ session จนกว่า token ล่าสุดที่อาจออกให้ session นั้นจะหมดอายุ
+func (s *authService) revokeSessionTokens(ctx context.Context, sessionID uuid.UUID) error {
+	return s.revocations.RevokeSession(ctx, sessionID, time.Now().Add(s.tokens.AccessTokenTTL))
+}
+
+func (s *authService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) (int, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return 0, entities.ErrNotFound
+	}
+
+	revoked, err := s.sessionRepo.RevokeAll(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return revoked, s.revocations.RevokeUserTokens(ctx, userID)
 }
 
 func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, req *entities.ChangePasswordRequest) error {
@@ -224,12 +333,21 @@ func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, req
 	}
 
 	// อัพเดทรหัสผ่าน
-	return s.userRepo.UpdatePassword(ctx, userID, newHashedPassword)
+	if err := s.userRepo.UpdatePassword(ctx, userID, newHashedPassword); err != nil {
+		return err
+	}
+
+	// ออกจากระบบทุกอุปกรณ์เช่นเดียวกับ ResetPassword ผู้ที่อาจถือ token เดิมต้องเข้าสู่ระบบด้วยรหัสผ่านใหม่
+	if _, err := s.sessionRepo.RevokeAll(ctx, userID); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUserTokens(ctx, userID)
 }
 
 func (s *authService) ForgotPassword(ctx context.Context, req *entities.ForgotPasswordRequest) error {
 	// ตรวจสอบว่าอีเมลมีอยู่ในระบบหรือไม่
-	if _, err := s.userRepo.GetByEmail(ctx, req.Email); err != nil {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
 		return errors.New("ไม่พบอีเมลในระบบ")
 	}
 
@@ -239,20 +357,18 @@ func (s *authService) ForgotPassword(ctx context.Context, req *entities.ForgotPa
 		return err
 	}
 
-	// บันทึก reset token
-	if err := s.userRepo.SetResetToken(ctx, req.Email, resetToken); err != nil {
+	// บันทึก reset token (เก็บเฉพาะ hash ส่ง token จริงทางอีเมลเท่านั้น)
+	if err := s.userRepo.SetResetToken(ctx, req.Email, utils.HashToken(resetToken)); err != nil {
 		return err
 	}
 
-	// TODO: ส่งอีเมลพร้อม reset token ให้ผู้ใช้
-	// ในการใช้งานจริงควรส่งอีเมลแทนการ return token
-
-	return nil
+	// ส่งอีเมลพร้อมลิงก์รีเซ็ตรหัสผ่านให้ผู้ใช้
+	return s.notifications.SendPasswordReset(ctx, user, resetToken, entities.PasswordResetTTL)
 }
 
 func (s *authService) ResetPassword(ctx context.Context, req *entities.ResetPasswordRequest) error {
 	// ค้นหาผู้ใช้ตาม reset token
-	user, err := s.userRepo.GetByResetToken(ctx, req.Token)
+	user, err := s.userRepo.GetByResetToken(ctx, utils.HashToken(req.Token))
 	if err != nil {
 		return errors.New("token ไม่ถูกต้องหรือหมดอายุแล้ว")
 	}
@@ -274,24 +390,66 @@ func (s *authService) ResetPassword(ctx context.Context, req *entities.ResetPass
 	}
 
 	// ลบ reset token
-	return s.userRepo.ClearResetToken(ctx, user.ID)
+	if err := s.userRepo.ClearResetToken(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// ออกจากระบบทุกอุปกรณ์ ผู้ที่อาจถือ token เดิมต้องเข้าสู่ระบบด้วยรหัสผ่านใหม่
+	if _, err := s.sessionRepo.RevokeAll(ctx, user.ID); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUserTokens(ctx, user.ID)
+}
+
+// VerifyEmail ยืนยันอีเมลด้วย token จากอีเมลยืนยัน token ใช้ได้ครั้งเดียว
+func (s *authService) VerifyEmail(ctx context.Context, req *entities.VerifyEmailRequest) (*entities.User, error) {
+	user, err := s.userRepo.VerifyEmail(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return nil, entities.ErrInvalidVerificationToken
+	}
+	return user, nil
+}
+
+// ResendVerification ส่งอีเมลยืนยันใหม่ (token เดิมใช้ไม่ได้อีก)
+// ไม่แจ้งว่าไม่พบอีเมลหรือยืนยันแล้ว เพื่อไม่เปิดเผยว่ามีบัญชีนี้หรือไม่
+func (s *authService) ResendVerification(ctx context.Context, req *entities.ResendVerificationRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil || !user.Active || user.EmailVerified() {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// sendVerification สร้าง token ยืนยันอีเมลใหม่และส่งไปยังอีเมลของผู้ใช้
+func (s *authService) sendVerification(ctx context.Context, user *entities.User) error {
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.SetVerificationToken(ctx, user.ID, utils.HashToken(token), time.Now().Add(s.verification.TokenTTL)); err != nil {
+		return err
+	}
+	return s.notifications.SendEmailVerification(ctx, user, token, s.verification.TokenTTL)
 }
 
 func (s *authService) ValidateToken(ctx context.Context, token string) (*entities.User, error) {
 	// ตรวจสอบ JWT token
-	claims, err := utils.ValidateJWT(token)
+	claims, err := s.issuer.Verify(token)
 	if err != nil {
 		return nil, err
 	}
 
-	// แปลง userID เป็น UUID
-	userID, err := uuid.Parse(claims.UserID)
+	// ตรวจสอบว่า token ยังไม่ถูกยกเลิก
+	revoked, err := s.revocations.IsRevoked(ctx, *claims)
 	if err != nil {
-		return nil, errors.New("รูปแบบ user ID ไม่ถูกต้อง")
+		return nil, err
+	}
+	if revoked {
+		return nil, entities.ErrTokenRevoked
 	}
 
 	// ดึงข้อมูลผู้ใช้
-	user, err := s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, errors.New("ไม่พบผู้ใช้")
 	}
@@ -304,6 +462,10 @@ func (s *authService) ValidateToken(ctx context.Context, token string) (*entitie
 	return user, nil
 }
 
+func (s *authService) JWKS() entities.JWKS {
+	return s.issuer.JWKS()
+}
+
 func (s *authService) generateRefreshToken() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -318,4 +480,4 @@ func (s *authService) generateResetToken() (string, error) {
 		return "", err
 	}
 	return hex.EncodeToString(bytes), nil
-}
\ No newline at end of file
+}
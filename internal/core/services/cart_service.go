@@ -2,39 +2,282 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 )
 
 type cartService struct {
-	cartRepo repositories.CartRepository
+	cartRepo     repositories.CartRepository
+	couponRepo   repositories.CouponRepository
+	shippingRepo repositories.ShippingRepository
+	rates        gateways.ExchangeRateProvider
+	tax          entities.TaxSettings
+	holdTTL      time.Duration
 }
 
-func NewCartService(cartRepo repositories.CartRepository) services.CartService {
+// NewCartService holdTTL คือระยะเวลาที่จองสต็อกให้สินค้าในตะกร้า (0 คือไม่จอง)
+// tax ใช้หาภูมิภาคเริ่มต้นเมื่อขอค่าจัดส่งโดยไม่ระบุภูมิภาค
+func NewCartService(cartRepo repositories.CartRepository, couponRepo repositories.CouponRepository, shippingRepo repositories.ShippingRepository, rates gateways.ExchangeRateProvider, tax entities.TaxSettings, holdTTL time.Duration) services.CartService {
 	return &cartService{
-		cartRepo: cartRepo,
+		cartRepo:     cartRepo,
+		couponRepo:   couponRepo,
+		shippingRepo: shippingRepo,
+		rates:        rates,
+		tax:          tax,
+		holdTTL:      holdTTL,
 	}
 }
 
+// GetCart คืนตะกร้าพร้อมส่วนลดอัตโนมัติและส่วนลดจากคูปองที่กรอกไว้
 func (s *cartService) GetCart(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
-	return s.cartRepo.GetByUserID(ctx, userID)
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDiscounts(ctx, cart)
 }
 
+// AddToCart คิดราคาสินค้าในสกุลเงินของตะกร้า ด้วยอัตราแลกเปลี่ยน ณ เวลาที่เพิ่มสินค้า
 func (s *cartService) AddToCart(ctx context.Context, userID uuid.UUID, req *entities.AddToCartRequest) error {
-	return s.cartRepo.AddItem(ctx, userID, req)
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	pricing, err := quotePricing(ctx, s.rates, cart.Currency)
+	if err != nil {
+		return err
+	}
+
+	return s.cartRepo.AddItem(ctx, userID, req, pricing, s.holdUntil())
 }
 
-func (s *cartService) UpdateCartItem(ctx context.Context, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error {
-	return s.cartRepo.UpdateItem(ctx, cartItemID, req.Quantity)
+// UpdateCartItem แก้ไขได้เฉพาะสินค้าในตะกร้าของตัวเอง ส่วนบทบาทที่มีสิทธิ์ carts:manage แก้ไขได้ทุกตะกร้า
+func (s *cartService) UpdateCartItem(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error {
+	if err := s.ensureAccessibleCartItem(ctx, actor, cartItemID); err != nil {
+		return err
+	}
+
+	return s.cartRepo.UpdateItem(ctx, cartItemID, req.Quantity, s.holdUntil())
 }
 
-func (s *cartService) RemoveFromCart(ctx context.Context, cartItemID uuid.UUID) error {
+// RemoveFromCart ลบได้เฉพาะสินค้าในตะกร้าของตัวเอง ส่วนบทบาทที่มีสิทธิ์ carts:manage ลบได้ทุกตะกร้า
+func (s *cartService) RemoveFromCart(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID) error {
+	if err := s.ensureAccessibleCartItem(ctx, actor, cartItemID); err != nil {
+		return err
+	}
+
 	return s.cartRepo.RemoveItem(ctx, cartItemID)
 }
 
 func (s *cartService) ClearCart(ctx context.Context, userID uuid.UUID) error {
 	return s.cartRepo.ClearCart(ctx, userID)
-}
\ No newline at end of file
+}
+
+// SetCurrency เปลี่ยนสกุลเงินของตะกร้า คืน entities.ErrUnsupportedCurrency หากไม่มีอัตราแลกเปลี่ยน
+func (s *cartService) SetCurrency(ctx context.Context, userID uuid.UUID, req *entities.SetCartCurrencyRequest) (*entities.Cart, error) {
+	currency, err := entities.ParseCurrency(string(req.Currency))
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := quotePricing(ctx, s.rates, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepo.SetCurrency(ctx, userID, pricing)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDiscounts(ctx, cart)
+}
+
+// ApplyCoupon กรอกรหัสคูปองให้ตะกร้า คืน entities.ErrNotFound หากไม่มีรหัสนี้
+// และ entities.ErrCouponNotApplicable หรือ entities.ErrCouponUsageLimit หากใช้กับตะกร้านี้ไม่ได้
+func (s *cartService) ApplyCoupon(ctx context.Context, userID uuid.UUID, req *entities.ApplyCouponRequest) (*entities.Cart, error) {
+	coupon, err := s.couponRepo.GetByCode(ctx, entities.NormalizeCouponCode(req.Code))
+	if err != nil || coupon.Automatic {
+		// ส่วนลดอัตโนมัติใช้กับตะกร้าเองอยู่แล้ว ไม่ต้องกรอกรหัส
+		return nil, entities.ErrNotFound
+	}
+
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cart.CouponID = &coupon.ID
+	cart.CouponCode = coupon.Code
+
+	result, err := s.quoteDiscounts(ctx, cart, coupon)
+	if err != nil {
+		return nil, err
+	}
+	if result.CouponErr != nil {
+		return nil, result.CouponErr
+	}
+
+	if err := s.cartRepo.SetCoupon(ctx, userID, &coupon.ID); err != nil {
+		return nil, err
+	}
+
+	applyCartDiscounts(cart, result)
+	return cart, nil
+}
+
+func (s *cartService) RemoveCoupon(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
+	if err := s.cartRepo.SetCoupon(ctx, userID, nil); err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, userID)
+}
+
+// ShippingOptions ค่าจัดส่งของทุกวิธีจัดส่งที่เปิดใช้งานสำหรับตะกร้าปัจจุบันในสกุลเงินของตะกร้า
+// วิธีจัดส่งที่ไม่มีอัตราสำหรับภูมิภาคหรือน้ำหนักของตะกร้าจะไม่อยู่ในรายการ
+func (s *cartService) ShippingOptions(ctx context.Context, userID uuid.UUID, region string) ([]entities.ShippingQuote, error) {
+	cart, err := s.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := quotePricing(ctx, s.rates, cart.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := s.shippingRepo.GetMethods(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	items := shippingItems(cart.CartItems)
+	region = shippingRegion(s.tax, region)
+
+	options := []entities.ShippingQuote{}
+	for _, method := range methods {
+		quote, err := method.Quote(items, cart.TotalPrice, region, pricing)
+		if errors.Is(err, entities.ErrShippingUnavailable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, quote)
+	}
+
+	return options, nil
+}
+
+// withDiscounts คิดส่วนลดของตะกร้าในสกุลเงินของตะกร้า ณ เวลานี้
+// คูปองที่ใช้ไม่ได้แล้ว (หมดอายุ ใช้ครบ หรือถูกลบ) ยังคงอยู่ในตะกร้าพร้อม CouponError บอกเหตุผล
+func (s *cartService) withDiscounts(ctx context.Context, cart *entities.Cart) (*entities.Cart, error) {
+	var coupon *entities.Coupon
+	if cart.CouponID != nil {
+		found, err := s.couponRepo.GetByID(ctx, *cart.CouponID)
+		if err != nil {
+			cart.CouponError = entities.ErrCouponNotApplicable.Error()
+		} else {
+			coupon = found
+		}
+	}
+
+	result, err := s.quoteDiscounts(ctx, cart, coupon)
+	if err != nil {
+		return nil, err
+	}
+
+	applyCartDiscounts(cart, result)
+	return cart, nil
+}
+
+// quoteDiscounts คิดส่วนลดอัตโนมัติและคูปองกับรายการในตะกร้า
+func (s *cartService) quoteDiscounts(ctx context.Context, cart *entities.Cart, coupon *entities.Coupon) (entities.DiscountResult, error) {
+	automatic, err := s.couponRepo.GetAutomatic(ctx)
+	if err != nil {
+		return entities.DiscountResult{}, err
+	}
+
+	couponIDs := make([]uuid.UUID, 0, len(automatic)+1)
+	for _, candidate := range automatic {
+		couponIDs = append(couponIDs, candidate.ID)
+	}
+	if coupon != nil {
+		couponIDs = append(couponIDs, coupon.ID)
+	}
+	used, err := s.couponRepo.CountUserRedemptions(ctx, cart.UserID, couponIDs)
+	if err != nil {
+		return entities.DiscountResult{}, err
+	}
+
+	pricing, err := quotePricing(ctx, s.rates, cart.Currency)
+	if err != nil {
+		return entities.DiscountResult{}, err
+	}
+
+	lines := make([]entities.DiscountLine, 0, len(cart.CartItems))
+	for _, item := range cart.CartItems {
+		line := entities.DiscountLine{
+			ItemID:    item.ID,
+			ProductID: item.ProductID,
+			Total:     entities.LineTotal(item.Price, item.Quantity),
+		}
+		if item.Product != nil {
+			line.CategoryID = item.Product.CategoryID
+		}
+		lines = append(lines, line)
+	}
+
+	return entities.ApplyDiscounts(lines, automatic, coupon, used, pricing, time.Now()), nil
+}
+
+// applyCartDiscounts ใส่ส่วนลดลงในตะกร้า ยอดสุทธิคือยอดสินค้าหักส่วนลด
+func applyCartDiscounts(cart *entities.Cart, result entities.DiscountResult) {
+	cart.Discounts = result.Discounts
+	if cart.Discounts == nil {
+		cart.Discounts = []entities.AppliedDiscount{}
+	}
+	cart.DiscountTotal = result.Total
+	cart.TotalPrice = cart.Subtotal.Sub(result.Total)
+	if result.CouponErr != nil {
+		cart.CouponError = result.CouponErr.Error()
+	}
+}
+
+func (s *cartService) holdUntil() time.Time {
+	if s.holdTTL <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(s.holdTTL)
+}
+
+// ensureAccessibleCartItem ตรวจสอบว่ารายการอยู่ในตะกร้าของผู้ใช้เอง หรือผู้ใช้มีสิทธิ์ carts:manage
+// รายการในตะกร้าของผู้อื่นจะได้ ErrNotFound เหมือนไม่มีอยู่
+func (s *cartService) ensureAccessibleCartItem(ctx context.Context, actor entities.Actor, cartItemID uuid.UUID) error {
+	item, err := s.cartRepo.GetCartItem(ctx, cartItemID)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+	if actor.Can(entities.PermCartsManage) {
+		return nil
+	}
+
+	cart, err := s.cartRepo.GetByUserID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	if item.CartID != cart.ID {
+		return entities.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type couponService struct {
+	couponRepo repositories.CouponRepository
+}
+
+func NewCouponService(couponRepo repositories.CouponRepository) services.CouponService {
+	return &couponService{
+		couponRepo: couponRepo,
+	}
+}
+
+func (s *couponService) GetCoupons(ctx context.Context, page, limit int) ([]*entities.Coupon, *entities.PaginationResponse, error) {
+	coupons, total, err := s.couponRepo.GetAll(ctx, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return coupons, pagination, nil
+}
+
+func (s *couponService) GetCouponByID(ctx context.Context, id uuid.UUID) (*entities.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	return coupon, nil
+}
+
+// CreateCoupon รหัสคูปองเก็บเป็นตัวพิมพ์ใหญ่ คูปองใหม่เปิดใช้งานทันทีหากไม่ระบุ active
+func (s *couponService) CreateCoupon(ctx context.Context, req *entities.CreateCouponRequest) (*entities.Coupon, error) {
+	code := entities.NormalizeCouponCode(req.Code)
+	if _, err := s.couponRepo.GetByCode(ctx, code); err == nil {
+		return nil, errors.New("รหัสคูปองนี้ถูกใช้แล้ว")
+	}
+
+	coupon := &entities.Coupon{
+		Code:         code,
+		Description:  req.Description,
+		Type:         req.Type,
+		Percentage:   req.Percentage,
+		Amount:       req.Amount,
+		MaxDiscount:  req.MaxDiscount,
+		MinSpend:     req.MinSpend,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Active:       true,
+		Automatic:    req.Automatic,
+		ProductIDs:   req.ProductIDs,
+		CategoryIDs:  req.CategoryIDs,
+	}
+	if req.Active != nil {
+		coupon.Active = *req.Active
+	}
+
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return s.couponRepo.GetByID(ctx, coupon.ID)
+}
+
+// UpdateCoupon แก้ไขคูปอง รหัสและจำนวนครั้งที่ใช้ไปแล้วแก้ไขไม่ได้
+func (s *couponService) UpdateCoupon(ctx context.Context, id uuid.UUID, req *entities.UpdateCouponRequest) (*entities.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	if req.Description != nil {
+		coupon.Description = *req.Description
+	}
+	if req.Type != "" {
+		coupon.Type = req.Type
+	}
+	if req.Percentage != nil {
+		coupon.Percentage = *req.Percentage
+	}
+	if req.Amount != nil {
+		coupon.Amount = *req.Amount
+	}
+	if req.MaxDiscount != nil {
+		coupon.MaxDiscount = *req.MaxDiscount
+	}
+	if req.MinSpend != nil {
+		coupon.MinSpend = *req.MinSpend
+	}
+	if req.UsageLimit != nil {
+		coupon.UsageLimit = *req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.StartsAt != nil {
+		coupon.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		coupon.EndsAt = req.EndsAt
+	}
+	if req.Active != nil {
+		coupon.Active = *req.Active
+	}
+	if req.Automatic != nil {
+		coupon.Automatic = *req.Automatic
+	}
+	if req.ProductIDs != nil {
+		coupon.ProductIDs = *req.ProductIDs
+	}
+	if req.CategoryIDs != nil {
+		coupon.CategoryIDs = *req.CategoryIDs
+	}
+
+	if err := coupon.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Update(ctx, id, coupon); err != nil {
+		return nil, err
+	}
+
+	return s.couponRepo.GetByID(ctx, id)
+}
+
+func (s *couponService) DeleteCoupon(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.couponRepo.GetByID(ctx, id); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.couponRepo.Delete(ctx, id)
+}
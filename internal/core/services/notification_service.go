@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type notificationService struct {
+	mailer      gateways.Mailer
+	userRepo    repositories.UserRepository
+	orderRepo   repositories.OrderRepository
+	linkBaseURL string
+}
+
+// NewNotificationService linkBaseURL คือ URL ของหน้าเว็บร้าน ใช้สร้างลิงก์ในอีเมล เช่น <linkBaseURL>/reset-password?token=...
+func NewNotificationService(mailer gateways.Mailer, userRepo repositories.UserRepository, orderRepo repositories.OrderRepository, linkBaseURL string) services.NotificationService {
+	return &notificationService{
+		mailer:      mailer,
+		userRepo:    userRepo,
+		orderRepo:   orderRepo,
+		linkBaseURL: strings.TrimRight(linkBaseURL, "/"),
+	}
+}
+
+func (s *notificationService) SendPasswordReset(ctx context.Context, user *entities.User, token string, ttl time.Duration) error {
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailPasswordReset,
+		Data: entities.PasswordResetEmail{
+			Name:           displayName(user),
+			ResetURL:       s.link("/reset-password?token=" + url.QueryEscape(token)),
+			ExpiresInHours: hours(ttl),
+		},
+	})
+}
+
+func (s *notificationService) SendEmailVerification(ctx context.Context, user *entities.User, token string, ttl time.Duration) error {
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailVerification,
+		Data: entities.VerificationEmail{
+			Name:           displayName(user),
+			Email:          user.Email,
+			VerifyURL:      s.link("/verify-email?token=" + url.QueryEscape(token)),
+			ExpiresInHours: hours(ttl),
+		},
+	})
+}
+
+// SendEmailChange ส่งลิงก์ยืนยันไปยังอีเมลใหม่ และแจ้งเตือนไปยังอีเมลเดิม
+func (s *notificationService) SendEmailChange(ctx context.Context, user *entities.User, newEmail, token string, ttl time.Duration) error {
+	if err := s.mailer.Send(ctx, entities.Email{
+		To:       newEmail,
+		Locale:   user.Locale,
+		Template: entities.EmailVerification,
+		Data: entities.VerificationEmail{
+			Name:           displayName(user),
+			Email:          newEmail,
+			VerifyURL:      s.link("/confirm-email-change?token=" + url.QueryEscape(token)),
+			ExpiresInHours: hours(ttl),
+			EmailChange:    true,
+		},
+	}); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailChangeNotice,
+		Data: entities.EmailChangeNoticeEmail{
+			Name:     displayName(user),
+			NewEmail: newEmail,
+		},
+	})
+}
+
+// HandleEvent ข้อผิดพลาดจะบันทึก log และคืน nil เสมอ เพื่อไม่ให้ dispatcher ส่ง event ซ้ำไปยัง sink อื่น (เช่น webhook)
+func (s *notificationService) HandleEvent(ctx context.Context, event entities.DomainEvent) error {
+	var err error
+	switch event.Type {
+	case entities.EventOrderCreated:
+		err = s.sendOrderConfirmation(ctx, event)
+	case entities.EventOrderShippingStatusChanged:
+		err = s.sendShippingUpdate(ctx, event)
+	case entities.EventPaymentCompleted:
+		err = s.sendPaymentReceipt(ctx, event)
+	case entities.EventProductBackInStock:
+		err = s.sendBackInStock(ctx, event)
+	}
+	if err != nil {
+		log.Printf("Notification for event %s (%s) failed: %v", event.ID, event.Type, err)
+	}
+	return nil
+}
+
+func (s *notificationService) sendOrderConfirmation(ctx context.Context, event entities.DomainEvent) error {
+	var payload entities.OrderCreatedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	order, user, err := s.orderRecipient(ctx, payload.OrderID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailOrderConfirmation,
+		Data: entities.OrderEmail{
+			Name:     displayName(user),
+			Order:    order,
+			OrderURL: s.orderLink(order.ID),
+		},
+	})
+}
+
+// sendShippingUpdate แจ้งเฉพาะสถานะที่ลูกค้าต้องรู้ (ส่งออกแล้ว อยู่ระหว่างขนส่ง ส่งถึงแล้ว)
+func (s *notificationService) sendShippingUpdate(ctx context.Context, event entities.DomainEvent) error {
+	var payload entities.OrderStatusChangedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	switch payload.To {
+	case entities.ShippingStatusPartiallyShipped, entities.ShippingStatusShipped,
+		entities.ShippingStatusInTransit, entities.ShippingStatusDelivered:
+	default:
+		return nil
+	}
+
+	order, user, err := s.orderRecipient(ctx, payload.OrderID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailShippingUpdate,
+		Data: entities.ShippingUpdateEmail{
+			Name:     displayName(user),
+			Order:    order,
+			Status:   payload.To,
+			OrderURL: s.orderLink(order.ID),
+		},
+	})
+}
+
+func (s *notificationService) sendPaymentReceipt(ctx context.Context, event entities.DomainEvent) error {
+	var payload entities.PaymentEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	order, user, err := s.orderRecipient(ctx, payload.OrderID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, entities.Email{
+		To:       user.Email,
+		Locale:   user.Locale,
+		Template: entities.EmailPaymentReceipt,
+		Data: entities.PaymentReceiptEmail{
+			Name:     displayName(user),
+			Order:    order,
+			Payment:  payload,
+			OrderURL: s.orderLink(order.ID),
+			PaidAt:   event.OccurredAt,
+		},
+	})
+}
+
+func (s *notificationService) sendBackInStock(ctx context.Context, event entities.DomainEvent) error {
+	var payload entities.ProductBackInStockPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	for _, userID := range payload.UserIDs {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil || !user.Active {
+			continue
+		}
+		if err := s.mailer.Send(ctx, entities.Email{
+			To:       user.Email,
+			Locale:   user.Locale,
+			Template: entities.EmailBackInStock,
+			Data: entities.BackInStockEmail{
+				Name:        displayName(user),
+				ProductName: payload.Name,
+				ProductURL:  s.link("/products/" + payload.ProductID.String()),
+			},
+		}); err != nil {
+			log.Printf("Back-in-stock mail to user %s failed: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// orderRecipient คืนคำสั่งซื้อและเจ้าของ user เป็น nil หากบัญชีถูกปิดหรือถูกลบไปแล้ว
+func (s *notificationService) orderRecipient(ctx context.Context, orderID uuid.UUID) (*entities.Order, *entities.User, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := order.User
+	if user == nil {
+		if user, err = s.userRepo.GetByID(ctx, order.UserID); err != nil {
+			return order, nil, nil
+		}
+	}
+	if !user.Active {
+		return order, nil, nil
+	}
+	return order, user, nil
+}
+
+func (s *notificationService) link(path string) string {
+	return s.linkBaseURL + path
+}
+
+func (s *notificationService) orderLink(orderID uuid.UUID) string {
+	return s.link("/orders/" + orderID.String())
+}
+
+func displayName(user *entities.User) string {
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
+
+// hours จำนวนชั่วโมงของ ttl ปัดขึ้น อย่างน้อย 1 ชั่วโมง
+func hours(ttl time.Duration) int {
+	h := int((ttl + time.Hour - 1) / time.Hour)
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
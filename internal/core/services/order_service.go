@@ -3,25 +3,102 @@ package services
 import (
 	"context"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 )
 
 type orderService struct {
-	orderRepo repositories.OrderRepository
+	orderRepo    repositories.OrderRepository
+	cartRepo     repositories.CartRepository
+	taxRepo      repositories.TaxRepository
+	shippingRepo repositories.ShippingRepository
+	addressRepo  repositories.AddressRepository
+	userRepo     repositories.UserRepository
+	rates        gateways.ExchangeRateProvider
+	tax          entities.TaxSettings
+	verification entities.EmailVerificationSettings
+	payment      entities.PaymentDeadlineSettings
 }
 
-func NewOrderService(orderRepo repositories.OrderRepository) services.OrderService {
+// NewOrderService payment กำหนดเวลาที่คำสั่งซื้อถือสต็อกไว้รอชำระเงินก่อนถูกยกเลิกอัตโนมัติ ยกเว้นวิธีชำระเงินแบบชำระภายหลัง
+func NewOrderService(orderRepo repositories.OrderRepository, cartRepo repositories.CartRepository, taxRepo repositories.TaxRepository, shippingRepo repositories.ShippingRepository, addressRepo repositories.AddressRepository, userRepo repositories.UserRepository, rates gateways.ExchangeRateProvider, tax entities.TaxSettings, verification entities.EmailVerificationSettings, payment entities.PaymentDeadlineSettings) services.OrderService {
 	return &orderService{
-		orderRepo: orderRepo,
+		orderRepo:    orderRepo,
+		cartRepo:     cartRepo,
+		taxRepo:      taxRepo,
+		shippingRepo: shippingRepo,
+		addressRepo:  addressRepo,
+		userRepo:     userRepo,
+		rates:        rates,
+		tax:          tax,
+		verification: verification,
+		payment:      payment,
 	}
 }
 
+// CreateOrder ตรึงสกุลเงินของตะกร้า อัตราแลกเปลี่ยน อัตราภาษี และค่าจัดส่งของภูมิภาคที่จัดส่ง ณ เวลาสั่งซื้อไว้กับคำสั่งซื้อ
+// คืน entities.ErrShippingMethodUnavailable หากไม่มีวิธีจัดส่งที่เลือกหรือถูกปิดใช้งาน
+// ที่อยู่จัดส่ง/ออกใบเสร็จจากสมุดที่อยู่ถูกบันทึกเป็นสำเนาไว้กับคำสั่งซื้อ และภูมิภาคของที่อยู่จัดส่งใช้เมื่อไม่ระบุ ShippingRegion
+// คืน entities.ErrEmailNotVerified หากบังคับยืนยันอีเมลก่อนสั่งซื้อและผู้ใช้ยังไม่ได้ยืนยัน
 func (s *orderService) CreateOrder(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (*entities.Order, error) {
-	return s.orderRepo.Create(ctx, userID, req)
+	if s.verification.BlocksCheckout() {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.EmailVerified() {
+			return nil, entities.ErrEmailNotVerified
+		}
+	}
+
+	shipping, err := s.shippingRepo.GetMethodByCode(ctx, entities.NormalizeShippingCode(req.ShippingMethod))
+	if err != nil || !shipping.Active {
+		return nil, entities.ErrShippingMethodUnavailable
+	}
+
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := quotePricing(ctx, s.rates, cart.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	shippingAddress, billingAddress, err := s.resolveAddresses(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	region := req.ShippingRegion
+	if strings.TrimSpace(region) == "" && shippingAddress != nil {
+		region = shippingAddress.Region()
+	}
+
+	tax, err := quoteTax(ctx, s.taxRepo, s.tax, region)
+	if err != nil {
+		return nil, err
+	}
+
+	checkout := &entities.Checkout{
+		Pricing:         pricing,
+		Tax:             tax,
+		Shipping:        shipping,
+		ShippingRegion:  shippingRegion(s.tax, region),
+		ShippingAddress: shippingAddress,
+		BillingAddress:  billingAddress,
+		// คำสั่งซื้อแบบเก็บเงินปลายทางไม่มีกำหนดเวลาชำระเงิน จึงไม่ถูก sweeper ยกเลิก
+		PaymentDueAt: s.payment.DueAt(req.PaymentMethod, time.Now()),
+	}
+
+	return s.orderRepo.Create(ctx, userID, req, checkout)
 }
 
 func (s *orderService) GetOrders(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error) {
@@ -42,12 +119,22 @@ func (s *orderService) GetOrders(ctx context.Context, userID uuid.UUID, page, li
 	return orders, pagination, nil
 }
 
-func (s *orderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
-	return s.orderRepo.GetByID(ctx, id)
+// GetOrderByID ผู้ใช้ทั่วไปดูได้เฉพาะคำสั่งซื้อของตัวเอง ส่วนบทบาทที่มีสิทธิ์ orders:read ดูได้ทั้งหมด
+func (s *orderService) GetOrderByID(ctx context.Context, actor entities.Actor, id uuid.UUID) (*entities.Order, error) {
+	return s.getAccessibleOrder(ctx, actor, id)
 }
 
-func (s *orderService) CancelOrder(ctx context.Context, id uuid.UUID) error {
-	return s.orderRepo.Cancel(ctx, id)
+// CancelOrder ยกเลิกคำสั่งซื้อของผู้อื่นได้เฉพาะบทบาทที่มีสิทธิ์ orders:update (ดูได้แต่ไม่มีสิทธิ์จะได้ ErrForbidden)
+func (s *orderService) CancelOrder(ctx context.Context, actor entities.Actor, id uuid.UUID) error {
+	order, err := s.getAccessibleOrder(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if order.UserID != actor.UserID && !actor.Can(entities.PermOrdersUpdate) {
+		return entities.ErrForbidden
+	}
+
+	return s.orderRepo.Cancel(ctx, id, &actor.UserID)
 }
 
 func (s *orderService) GetAllOrders(ctx context.Context, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error) {
@@ -68,14 +155,68 @@ func (s *orderService) GetAllOrders(ctx context.Context, page, limit int) ([]*en
 	return orders, pagination, nil
 }
 
-func (s *orderService) UpdateOrderStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateOrderStatusRequest) error {
-	return s.orderRepo.UpdateStatus(ctx, id, req.Status)
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdateOrderStatusRequest) error {
+	return s.orderRepo.UpdateStatus(ctx, id, req.Status, &changedBy, req.Note)
+}
+
+func (s *orderService) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdatePaymentStatusRequest) error {
+	return s.orderRepo.UpdatePaymentStatus(ctx, id, req.PaymentStatus, &changedBy, req.Note)
+}
+
+func (s *orderService) UpdateShippingStatus(ctx context.Context, id uuid.UUID, changedBy uuid.UUID, req *entities.UpdateShippingStatusRequest) error {
+	return s.orderRepo.UpdateShippingStatus(ctx, id, req, &changedBy)
+}
+
+func (s *orderService) CreateShipment(ctx context.Context, orderID uuid.UUID, changedBy uuid.UUID, req *entities.CreateShipmentRequest) (*entities.Shipment, error) {
+	return s.orderRepo.CreateShipment(ctx, orderID, req, &changedBy)
 }
 
-func (s *orderService) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, req *entities.UpdatePaymentStatusRequest) error {
-	return s.orderRepo.UpdatePaymentStatus(ctx, id, req.PaymentStatus)
+func (s *orderService) UpdateShipment(ctx context.Context, orderID, shipmentID uuid.UUID, changedBy uuid.UUID, req *entities.UpdateShipmentRequest) (*entities.Shipment, error) {
+	return s.orderRepo.UpdateShipment(ctx, orderID, shipmentID, req, &changedBy)
 }
 
-func (s *orderService) UpdateShippingStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingStatusRequest) error {
-	return s.orderRepo.UpdateShippingStatus(ctx, id, req.ShippingStatus, req.TrackingNumber)
-}
\ No newline at end of file
+// resolveAddresses หาที่อยู่จัดส่งและออกใบเสร็จจากสมุดที่อยู่ของผู้ใช้
+// ที่อยู่จัดส่ง: shipping_address_id > ข้อความ shipping_address (ไม่มีสำเนาแบบมีโครงสร้าง) > ที่อยู่จัดส่งเริ่มต้น
+// ที่อยู่ออกใบเสร็จ: billing_address_id > ที่อยู่ออกใบเสร็จเริ่มต้น > ที่อยู่จัดส่ง
+func (s *orderService) resolveAddresses(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (shipping, billing *entities.Address, err error) {
+	addresses, err := s.addressRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case req.ShippingAddressID != nil:
+		if shipping, err = findAddress(addresses, *req.ShippingAddressID); err != nil {
+			return nil, nil, err
+		}
+	case strings.TrimSpace(req.ShippingAddress) == "":
+		if shipping = defaultAddress(addresses, entities.AddressKindShipping); shipping == nil {
+			return nil, nil, entities.ErrShippingAddressRequired
+		}
+	}
+
+	if req.BillingAddressID != nil {
+		if billing, err = findAddress(addresses, *req.BillingAddressID); err != nil {
+			return nil, nil, err
+		}
+	} else if billing = defaultAddress(addresses, entities.AddressKindBilling); billing == nil {
+		billing = shipping
+	}
+
+	return shipping, billing, nil
+}
+
+// getAccessibleOrder ดึงคำสั่งซื้อที่ผู้ใช้มีสิทธิ์เข้าถึง
+// คำสั่งซื้อของผู้อื่นจะได้ ErrNotFound เหมือนไม่มีอยู่ เพื่อไม่ให้เดา ID ได้
+func (s *orderService) getAccessibleOrder(ctx context.Context, actor entities.Actor, id uuid.UUID) (*entities.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	if !actor.CanAccess(order.UserID, entities.PermOrdersRead) {
+		return nil, entities.ErrNotFound
+	}
+
+	return order, nil
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+)
+
+// ผู้ใช้สามแบบของทุกกรณี: เจ้าของข้อมูล ผู้ใช้อื่น และ admin ที่ไม่ใช่เจ้าของ
+var (
+	ownerID = uuid.New()
+	otherID = uuid.New()
+	adminID = uuid.New()
+)
+
+type accessCase struct {
+	name    string
+	actor   entities.Actor
+	wantErr error
+}
+
+func accessCases() []accessCase {
+	return []accessCase{
+		{name: "owner", actor: entities.Actor{UserID: ownerID, Role: entities.RoleUser}},
+		{name: "other user", actor: entities.Actor{UserID: otherID, Role: entities.RoleUser}, wantErr: entities.ErrNotFound},
+		{name: "admin", actor: entities.Actor{UserID: adminID, Role: entities.RoleAdmin}},
+	}
+}
+
+func checkAccess(t *testing.T, err, wantErr error) {
+	t.Helper()
+	if wantErr == nil && err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wantErr != nil && !errors.Is(err, wantErr) {
+		t.Fatalf("error = %v, want %v", err, wantErr)
+	}
+}
+
+// fakeOrderRepository คืนคำสั่งซื้อเดียวของ ownerID ส่วน method อื่นของ interface ไม่ถูกเรียก
+type fakeOrderRepository struct {
+	repositories.OrderRepository
+	order     *entities.Order
+	cancelled bool
+}
+
+func (r *fakeOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	if id != r.order.ID {
+		return nil, errors.New("record not found")
+	}
+	return r.order, nil
+}
+
+func (r *fakeOrderRepository) Cancel(ctx context.Context, id uuid.UUID, changedBy *uuid.UUID) error {
+	r.cancelled = true
+	return nil
+}
+
+func newFakeOrderRepository() *fakeOrderRepository {
+	return &fakeOrderRepository{order: &entities.Order{
+		ID:            uuid.New(),
+		UserID:        ownerID,
+		Status:        entities.OrderStatusPending,
+		PaymentStatus: entities.PaymentStatusPending,
+	}}
+}
+
+func TestOrderServiceGetOrderByIDOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			orders := newFakeOrderRepository()
+			service := &orderService{orderRepo: orders}
+
+			order, err := service.GetOrderByID(context.Background(), tc.actor, orders.order.ID)
+			checkAccess(t, err, tc.wantErr)
+			if tc.wantErr == nil && order.ID != orders.order.ID {
+				t.Fatalf("order = %s, want %s", order.ID, orders.order.ID)
+			}
+		})
+	}
+}
+
+func TestOrderServiceCancelOrderOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			orders := newFakeOrderRepository()
+			service := &orderService{orderRepo: orders}
+
+			err := service.CancelOrder(context.Background(), tc.actor, orders.order.ID)
+			checkAccess(t, err, tc.wantErr)
+			if orders.cancelled != (tc.wantErr == nil) {
+				t.Fatalf("cancelled = %v", orders.cancelled)
+			}
+		})
+	}
+}
+
+// fakeCartRepository ตะกร้าหนึ่งใบต่อผู้ใช้ และรายการสินค้าหนึ่งรายการในตะกร้าของ ownerID
+type fakeCartRepository struct {
+	repositories.CartRepository
+	item    *entities.CartItem
+	carts   map[uuid.UUID]*entities.Cart
+	updated bool
+	removed bool
+}
+
+func newFakeCartRepository() *fakeCartRepository {
+	carts := map[uuid.UUID]*entities.Cart{}
+	for _, userID := range []uuid.UUID{ownerID, otherID, adminID} {
+		carts[userID] = &entities.Cart{ID: uuid.New(), UserID: userID}
+	}
+	return &fakeCartRepository{
+		item:  &entities.CartItem{ID: uuid.New(), CartID: carts[ownerID].ID},
+		carts: carts,
+	}
+}
+
+func (r *fakeCartRepository) GetCartItem(ctx context.Context, cartItemID uuid.UUID) (*entities.CartItem, error) {
+	if cartItemID != r.item.ID {
+		return nil, errors.New("record not found")
+	}
+	return r.item, nil
+}
+
+func (r *fakeCartRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
+	return r.carts[userID], nil
+}
+
+func (r *fakeCartRepository) UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int, holdUntil time.Time) error {
+	r.updated = true
+	return nil
+}
+
+func (r *fakeCartRepository) RemoveItem(ctx context.Context, cartItemID uuid.UUID) error {
+	r.removed = true
+	return nil
+}
+
+func TestCartServiceUpdateCartItemOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			carts := newFakeCartRepository()
+			service := &cartService{cartRepo: carts}
+
+			err := service.UpdateCartItem(context.Background(), tc.actor, carts.item.ID, &entities.UpdateCartItemRequest{Quantity: 2})
+			checkAccess(t, err, tc.wantErr)
+			if carts.updated != (tc.wantErr == nil) {
+				t.Fatalf("updated = %v", carts.updated)
+			}
+		})
+	}
+}
+
+func TestCartServiceRemoveFromCartOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			carts := newFakeCartRepository()
+			service := &cartService{cartRepo: carts}
+
+			err := service.RemoveFromCart(context.Background(), tc.actor, carts.item.ID)
+			checkAccess(t, err, tc.wantErr)
+			if carts.removed != (tc.wantErr == nil) {
+				t.Fatalf("removed = %v", carts.removed)
+			}
+		})
+	}
+}
+
+// fakeTransactionRepository การชำระเงินหนึ่งรายการของคำสั่งซื้อใน fakeOrderRepository
+type fakeTransactionRepository struct {
+	repositories.TransactionRepository
+	transaction *entities.Transaction
+	created     bool
+	cancelled   bool
+}
+
+func newFakeTransactionRepository(order *entities.Order) *fakeTransactionRepository {
+	return &fakeTransactionRepository{
+		transaction: &entities.Transaction{ID: uuid.New(), OrderID: order.ID, TransactionID: "txn_1"},
+	}
+}
+
+func (r *fakeTransactionRepository) Create(ctx context.Context, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
+	r.created = true
+	return &entities.Transaction{ID: uuid.New(), OrderID: req.OrderID, PaymentMethod: req.PaymentMethod}, nil
+}
+
+func (r *fakeTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
+	if id != r.transaction.ID {
+		return nil, errors.New("record not found")
+	}
+	return r.transaction, nil
+}
+
+func (r *fakeTransactionRepository) SetProviderRef(ctx context.Context, id uuid.UUID, provider, providerRef string) error {
+	return nil
+}
+
+func (r *fakeTransactionRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+	r.cancelled = true
+	return nil
+}
+
+type fakePaymentGateway struct {
+	gateways.PaymentGateway
+}
+
+func (g fakePaymentGateway) Name() string {
+	return "fake"
+}
+
+func (g fakePaymentGateway) CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error) {
+	return &entities.PaymentIntent{ProviderRef: "pi_1", ClientSecret: "secret"}, nil
+}
+
+func newTestPaymentService() (*paymentService, *fakeOrderRepository, *fakeTransactionRepository) {
+	orders := newFakeOrderRepository()
+	transactions := newFakeTransactionRepository(orders.order)
+	service := NewPaymentService(transactions, orders, "fake", fakePaymentGateway{}).(*paymentService)
+	return service, orders, transactions
+}
+
+func TestPaymentServiceCreatePaymentOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			service, orders, transactions := newTestPaymentService()
+
+			transaction, err := service.CreatePayment(context.Background(), tc.actor, &entities.CreatePaymentRequest{
+				OrderID:       orders.order.ID,
+				PaymentMethod: "credit_card",
+			})
+			checkAccess(t, err, tc.wantErr)
+			if transactions.created != (tc.wantErr == nil) {
+				t.Fatalf("created = %v", transactions.created)
+			}
+			if tc.wantErr == nil && transaction.ProviderRef != "pi_1" {
+				t.Fatalf("provider ref = %q", transaction.ProviderRef)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceCreatePaymentReadOnlyStaffForbidden(t *testing.T) {
+	service, orders, transactions := newTestPaymentService()
+	support := entities.Actor{UserID: otherID, Role: "support", Permissions: []string{entities.PermPaymentsRead}}
+
+	_, err := service.CreatePayment(context.Background(), support, &entities.CreatePaymentRequest{
+		OrderID:       orders.order.ID,
+		PaymentMethod: "credit_card",
+	})
+	checkAccess(t, err, entities.ErrForbidden)
+	if transactions.created {
+		t.Fatal("payment created without orders:update")
+	}
+}
+
+func TestPaymentServiceCreatePaymentNotPayable(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		status        string
+		paymentStatus string
+	}{
+		{name: "cancelled", status: entities.OrderStatusCancelled, paymentStatus: entities.PaymentStatusCancelled},
+		{name: "paid", status: entities.OrderStatusConfirmed, paymentStatus: entities.PaymentStatusPaid},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service, orders, transactions := newTestPaymentService()
+			orders.order.Status = tc.status
+			orders.order.PaymentStatus = tc.paymentStatus
+
+			_, err := service.CreatePayment(context.Background(), entities.Actor{UserID: ownerID, Role: entities.RoleUser}, &entities.CreatePaymentRequest{
+				OrderID:       orders.order.ID,
+				PaymentMethod: "credit_card",
+			})
+			checkAccess(t, err, entities.ErrOrderNotPayable)
+			if transactions.created {
+				t.Fatal("payment created for an order that is not payable")
+			}
+		})
+	}
+}
+
+func TestPaymentServiceVerifyPaymentOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _, transactions := newTestPaymentService()
+
+			transaction, err := service.VerifyPayment(context.Background(), tc.actor, transactions.transaction.ID, &entities.VerifyPaymentRequest{
+				TransactionID: "txn_1",
+			})
+			checkAccess(t, err, tc.wantErr)
+			if tc.wantErr == nil && transaction.ID != transactions.transaction.ID {
+				t.Fatalf("transaction = %s, want %s", transaction.ID, transactions.transaction.ID)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceCancelPaymentOwnership(t *testing.T) {
+	for _, tc := range accessCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _, transactions := newTestPaymentService()
+
+			err := service.CancelPayment(context.Background(), tc.actor, transactions.transaction.ID)
+			checkAccess(t, err, tc.wantErr)
+			if transactions.cancelled != (tc.wantErr == nil) {
+				t.Fatalf("cancelled = %v", transactions.cancelled)
+			}
+		})
+	}
+}
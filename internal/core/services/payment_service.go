@@ -2,49 +2,222 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
 )
 
 type paymentService struct {
 	transactionRepo repositories.TransactionRepository
+	orderRepo       repositories.OrderRepository
+	gateways        map[string]gateways.PaymentGateway
+	defaultProvider string
 }
 
-func NewPaymentService(transactionRepo repositories.TransactionRepository) services.PaymentService {
+// NewPaymentService รับ gateway ได้หลายตัว โดย defaultProvider ใช้สำหรับสร้างการชำระเงินใหม่
+func NewPaymentService(transactionRepo repositories.TransactionRepository, orderRepo repositories.OrderRepository, defaultProvider string, paymentGateways ...gateways.PaymentGateway) services.PaymentService {
+	registry := make(map[string]gateways.PaymentGateway, len(paymentGateways))
+	for _, gateway := range paymentGateways {
+		registry[gateway.Name()] = gateway
+	}
+
 	return &paymentService{
 		transactionRepo: transactionRepo,
+		orderRepo:       orderRepo,
+		gateways:        registry,
+		defaultProvider: defaultProvider,
 	}
 }
 
-func (s *paymentService) CreatePayment(ctx context.Context, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
-	return s.transactionRepo.Create(ctx, req)
+// CreatePayment ชำระเงินคำสั่งซื้อของผู้อื่นได้เฉพาะบทบาทที่มีสิทธิ์ orders:update
+// (มีเพียงสิทธิ์ payments:read จะมองเห็นได้แต่ชำระเงินแทนไม่ได้ ErrForbidden)
+// คำสั่งซื้อที่ถูกยกเลิก หมดเวลาชำระ หรือชำระแล้วได้ ErrOrderNotPayable
+func (s *paymentService) CreatePayment(ctx context.Context, actor entities.Actor, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
+	order, err := s.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil || !actor.CanAccess(order.UserID, entities.PermPaymentsRead) {
+		return nil, entities.ErrNotFound
+	}
+	if order.UserID != actor.UserID && !actor.Can(entities.PermOrdersUpdate) {
+		return nil, entities.ErrForbidden
+	}
+	if !order.Payable() {
+		return nil, entities.ErrOrderNotPayable
+	}
+
+	gateway, ok := s.gateways[s.defaultProvider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", gateways.ErrUnknownProvider, s.defaultProvider)
+	}
+
+	transaction, err := s.transactionRepo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := gateway.CreateIntent(ctx, &entities.PaymentIntentRequest{
+		PaymentID:     transaction.ID,
+		OrderID:       transaction.OrderID,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		PaymentMethod: transaction.PaymentMethod,
+	})
+	if err != nil {
+		if updateErr := s.transactionRepo.UpdateStatus(ctx, transaction.ID, "failed"); updateErr != nil {
+			log.Printf("Failed to mark payment %s as failed: %v", transaction.ID, updateErr)
+		}
+		return nil, err
+	}
+
+	if err := s.transactionRepo.SetProviderRef(ctx, transaction.ID, gateway.Name(), intent.ProviderRef); err != nil {
+		return nil, err
+	}
+
+	transaction.Provider = gateway.Name()
+	transaction.ProviderRef = intent.ProviderRef
+	// client secret ส่งให้ผู้ใช้เพียงครั้งเดียวเพื่อยืนยันการชำระเงินกับ gateway
+	transaction.ClientSecret = intent.ClientSecret
+
+	return transaction, nil
 }
 
 func (s *paymentService) GetPaymentByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
 	return s.transactionRepo.GetByID(ctx, id)
 }
 
-func (s *paymentService) VerifyPayment(ctx context.Context, id uuid.UUID, req *entities.VerifyPaymentRequest) error {
-	// ตรวจสอบข้อมูลการชำระเงิน
-	transaction, err := s.transactionRepo.GetByID(ctx, id)
+// VerifyPayment ตรวจสอบสถานะการชำระเงินเท่านั้น
+// สถานะ completed จะเปลี่ยนได้จาก callback ของ gateway ที่ผ่านการตรวจลายเซ็นแล้ว (HandleWebhook)
+func (s *paymentService) VerifyPayment(ctx context.Context, actor entities.Actor, id uuid.UUID, req *entities.VerifyPaymentRequest) (*entities.Transaction, error) {
+	transaction, _, err := s.getAccessibleTransaction(ctx, actor, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// ในการใช้งานจริง ควรมีการตรวจสอบกับ payment gateway
-	// ที่นี่เป็นการจำลองการตรวจสอบ
-	if transaction.TransactionID == req.TransactionID {
-		// อัพเดทสถานะเป็น completed
-		return s.transactionRepo.UpdateStatus(ctx, id, "completed")
+	if transaction.TransactionID != req.TransactionID {
+		return nil, errors.New("รหัสธุรกรรมไม่ถูกต้อง")
 	}
 
-	// หากการตรวจสอบไม่ผ่าน อัพเดทสถานะเป็น failed
-	return s.transactionRepo.UpdateStatus(ctx, id, "failed")
+	return transaction, nil
 }
 
-func (s *paymentService) CancelPayment(ctx context.Context, id uuid.UUID) error {
+// CancelPayment ยกเลิกการชำระเงินของผู้อื่นได้เฉพาะบทบาทที่มีสิทธิ์ orders:update
+func (s *paymentService) CancelPayment(ctx context.Context, actor entities.Actor, id uuid.UUID) error {
+	_, order, err := s.getAccessibleTransaction(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if order.UserID != actor.UserID && !actor.Can(entities.PermOrdersUpdate) {
+		return entities.ErrForbidden
+	}
+
 	return s.transactionRepo.Cancel(ctx, id)
-}
\ No newline at end of file
+}
+
+// HandleWebhook ตรวจลายเซ็น callback แล้วอัพเดทสถานะการชำระเงิน
+// callback ซ้ำหรือมาถึงหลังจากสถานะสิ้นสุดแล้วจะถูกข้ามไป
+func (s *paymentService) HandleWebhook(ctx context.Context, provider string, headers http.Header, body []byte) error {
+	gateway, ok := s.gateways[provider]
+	if !ok {
+		return fmt.Errorf("%w: %s", gateways.ErrUnknownProvider, provider)
+	}
+
+	event, err := gateway.ParseWebhook(ctx, headers, body)
+	if err != nil {
+		return err
+	}
+
+	var status string
+	switch event.Status {
+	case entities.PaymentWebhookSucceeded:
+		status = "completed"
+	case entities.PaymentWebhookFailed:
+		status = "failed"
+	default:
+		// event ชนิดอื่นยังไม่ต้องจัดการ
+		return nil
+	}
+
+	transaction, err := s.transactionRepo.GetByProviderRef(ctx, gateway.Name(), event.ProviderRef)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Status != "pending" {
+		log.Printf("Ignoring %s callback %s for payment %s in status %s", provider, event.EventID, transaction.ID, transaction.Status)
+		return nil
+	}
+
+	expected := entities.NewAmount(transaction.Amount, transaction.Currency)
+	received := entities.NewAmount(event.Amount, event.Currency)
+	if status == "completed" && !received.Equal(expected) {
+		return fmt.Errorf("amount mismatch for payment %s: expected %s, got %s", transaction.ID, expected, received)
+	}
+
+	// การชำระเงินอาจถูกปิดระหว่างนี้ (เช่น คำสั่งซื้อถูกยกเลิก) repository จะเปลี่ยนเฉพาะแถวที่ยังรอชำระ
+	if err := s.transactionRepo.UpdateStatus(ctx, transaction.ID, status); err != nil {
+		if errors.Is(err, entities.ErrPaymentNotPending) {
+			log.Printf("Ignoring %s callback %s for payment %s closed concurrently", provider, event.EventID, transaction.ID)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// RefundPayment คืนเงินเต็มจำนวนหรือบางส่วน
+// จองยอดไว้ก่อนเรียก gateway เพื่อไม่ให้คำขอที่มาพร้อมกันคืนเงินเกินยอดที่ชำระ
+func (s *paymentService) RefundPayment(ctx context.Context, id uuid.UUID, adminID uuid.UUID, req *entities.CreateRefundRequest) (*entities.Refund, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("ไม่พบการชำระเงิน")
+	}
+
+	// การชำระเงินที่สร้างก่อนมี gateway จะคืนเงินแบบบันทึกด้วยมือ
+	var gateway gateways.PaymentGateway
+	if transaction.Provider != "" {
+		var ok bool
+		if gateway, ok = s.gateways[transaction.Provider]; !ok {
+			return nil, fmt.Errorf("%w: %s", gateways.ErrUnknownProvider, transaction.Provider)
+		}
+	}
+
+	refund, err := s.transactionRepo.CreateRefund(ctx, id, req, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	var providerRef string
+	if gateway != nil {
+		result, err := gateway.Refund(ctx, transaction.ProviderRef, refund.Amount, req.Reason)
+		if err != nil {
+			if failErr := s.transactionRepo.FailRefund(ctx, refund.ID); failErr != nil {
+				log.Printf("Failed to mark refund %s as failed: %v", refund.ID, failErr)
+			}
+			return nil, fmt.Errorf("payment gateway ปฏิเสธการคืนเงิน: %v", err)
+		}
+		providerRef = result.RefundRef
+	}
+
+	return s.transactionRepo.CompleteRefund(ctx, refund.ID, providerRef)
+}
+
+// getAccessibleTransaction ดึงการชำระเงินและคำสั่งซื้อที่ผู้ใช้มีสิทธิ์เข้าถึงผ่านเจ้าของคำสั่งซื้อหรือสิทธิ์ payments:read
+func (s *paymentService) getAccessibleTransaction(ctx context.Context, actor entities.Actor, id uuid.UUID) (*entities.Transaction, *entities.Order, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, entities.ErrNotFound
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, transaction.OrderID)
+	if err != nil || !actor.CanAccess(order.UserID, entities.PermPaymentsRead) {
+		return nil, nil, entities.ErrNotFound
+	}
+
+	return transaction, order, nil
+}
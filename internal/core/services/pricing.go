@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// quotePricing หาอัตราแลกเปลี่ยนจากสกุลเงินหลักของร้านเป็น currency ณ เวลานี้
+func quotePricing(ctx context.Context, rates gateways.ExchangeRateProvider, currency entities.Currency) (entities.PriceContext, error) {
+	if currency == "" || currency == entities.DefaultCurrency {
+		return entities.BasePriceContext(), nil
+	}
+
+	rate, err := rates.Rate(ctx, entities.DefaultCurrency, currency)
+	if err != nil {
+		return entities.PriceContext{}, err
+	}
+
+	return entities.PriceContext{
+		Currency:     currency,
+		BaseCurrency: entities.DefaultCurrency,
+		ExchangeRate: rate,
+	}, nil
+}
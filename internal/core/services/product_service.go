@@ -11,16 +11,22 @@ import (
 )
 
 type productService struct {
-	productRepo repositories.ProductRepository
+	productRepo   repositories.ProductRepository
+	inventoryRepo repositories.InventoryRepository
 }
 
-func NewProductService(productRepo repositories.ProductRepository) services.ProductService {
+func NewProductService(productRepo repositories.ProductRepository, inventoryRepo repositories.InventoryRepository) services.ProductService {
 	return &productService{
-		productRepo: productRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
 	}
 }
 
 func (s *productService) CreateProduct(ctx context.Context, req *entities.CreateProductRequest) (*entities.Product, error) {
+	if err := entities.NormalizePrices(req.Prices); err != nil {
+		return nil, err
+	}
+
 	return s.productRepo.Create(ctx, req)
 }
 
@@ -42,8 +48,20 @@ func (s *productService) GetProducts(ctx context.Context, page, limit int) ([]*e
 	return products, pagination, nil
 }
 
+// GetProductByID แสดงสต็อกแยกเป็น on-hand และ sellable ตามการจองของตะกร้าและคำสั่งซื้อ
 func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
-	return s.productRepo.GetByID(ctx, id)
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	availability, err := s.inventoryRepo.GetStockLevel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	product.Availability = availability
+
+	return product, nil
 }
 
 func (s *productService) GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, *entities.PaginationResponse, error) {
@@ -92,9 +110,15 @@ func (s *productService) SearchProducts(ctx context.Context, req *entities.Produ
 }
 
 func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, req *entities.UpdateProductRequest) error {
+	if req.Prices != nil {
+		if err := entities.NormalizePrices(*req.Prices); err != nil {
+			return err
+		}
+	}
+
 	return s.productRepo.Update(ctx, id, req)
 }
 
 func (s *productService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
 	return s.productRepo.Delete(ctx, id)
-}
\ No newline at end of file
+}
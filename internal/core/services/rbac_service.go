@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type rbacService struct {
+	roleRepo       repositories.RoleRepository
+	permissionRepo repositories.PermissionRepository
+	userRepo       repositories.UserRepository
+	revocations    gateways.TokenRevocationStore
+	cacheTTL       time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]cachedPermissions
+}
+
+// cachedPermissions สิทธิ์ของบทบาทหนึ่งที่โหลดจากฐานข้อมูลแล้ว
+type cachedPermissions struct {
+	names    map[string]struct{}
+	loadedAt time.Time
+}
+
+// NewRBACService cacheTTL กำหนดอายุของ cache สิทธิ์ต่อบทบาท
+// การแก้ไขผ่าน service นี้จะล้าง cache ทันที ส่วน TTL ใช้รองรับการแก้ไขจาก instance อื่น
+func NewRBACService(roleRepo repositories.RoleRepository, permissionRepo repositories.PermissionRepository, userRepo repositories.UserRepository, revocations gateways.TokenRevocationStore, cacheTTL time.Duration) services.RBACService {
+	if cacheTTL <= 0 {
+		cacheTTL = 5 * time.Minute
+	}
+
+	return &rbacService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		userRepo:       userRepo,
+		revocations:    revocations,
+		cacheTTL:       cacheTTL,
+		cache:          make(map[string]cachedPermissions),
+	}
+}
+
+// HasPermission ตรวจสอบว่าบทบาทมีสิทธิ์ที่กำหนดหรือไม่ โดยบทบาท admin มีทุกสิทธิ์เสมอ
+func (s *rbacService) HasPermission(ctx context.Context, roleName, permission string) (bool, error) {
+	if roleName == entities.RoleAdmin {
+		return true, nil
+	}
+
+	cached, err := s.loadPermissions(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+
+	_, allowed := cached.names[permission]
+	return allowed, nil
+}
+
+// RolePermissions สิทธิ์ทั้งหมดของบทบาทจาก cache เดียวกับ HasPermission
+// บทบาท admin คืนค่าว่างเพราะมีทุกสิทธิ์อยู่แล้ว (ดู entities.Actor.Can)
+func (s *rbacService) RolePermissions(ctx context.Context, roleName string) ([]string, error) {
+	if roleName == entities.RoleAdmin {
+		return nil, nil
+	}
+
+	cached, err := s.loadPermissions(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cached.names))
+	for name := range cached.names {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// loadPermissions โหลดสิทธิ์ของบทบาทจากฐานข้อมูลเมื่อไม่มีใน cache หรือ cache หมดอายุ
+func (s *rbacService) loadPermissions(ctx context.Context, roleName string) (cachedPermissions, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[roleName]
+	s.mu.RUnlock()
+
+	if ok && time.Since(cached.loadedAt) <= s.cacheTTL {
+		return cached, nil
+	}
+
+	names, err := s.roleRepo.GetPermissionNames(ctx, roleName)
+	if err != nil {
+		return cachedPermissions{}, err
+	}
+
+	cached = cachedPermissions{
+		names:    make(map[string]struct{}, len(names)),
+		loadedAt: time.Now(),
+	}
+	for _, name := range names {
+		cached.names[name] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.cache[roleName] = cached
+	s.mu.Unlock()
+
+	return cached, nil
+}
+
+func (s *rbacService) InvalidateCache() {
+	s.mu.Lock()
+	s.cache = make(map[string]cachedPermissions)
+	s.mu.Unlock()
+}
+
+func (s *rbacService) GetRoles(ctx context.Context) ([]*entities.Role, error) {
+	return s.roleRepo.GetAll(ctx)
+}
+
+func (s *rbacService) GetRoleByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	return role, nil
+}
+
+func (s *rbacService) CreateRole(ctx context.Context, req *entities.CreateRoleRequest) (*entities.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if _, err := s.roleRepo.GetByName(ctx, name); err == nil {
+		return nil, errors.New("ชื่อบทบาทนี้ถูกใช้แล้ว")
+	}
+
+	role := &entities.Role{
+		Name:        name,
+		Description: req.Description,
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// UpdateRole แก้ไขชื่อและคำอธิบายของบทบาท access token มีชื่อบทบาทอยู่ใน claims
+// การเปลี่ยนชื่อจึงยกเลิก token เดิมของผู้ใช้ทุกคนในบทบาท เหมือน AssignUserRole
+func (s *rbacService) UpdateRole(ctx context.Context, id uuid.UUID, req *entities.UpdateRoleRequest) error {
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	name := strings.TrimSpace(req.Name)
+	renamed := name != "" && name != role.Name
+	if renamed {
+		if isSystemRole(role.Name) {
+			return errors.New("ไม่สามารถเปลี่ยนชื่อบทบาทเริ่มต้นของระบบได้")
+		}
+		if _, err := s.roleRepo.GetByName(ctx, name); err == nil {
+			return errors.New("ชื่อบทบาทนี้ถูกใช้แล้ว")
+		}
+		role.Name = name
+	}
+	role.Description = req.Description
+
+	if err := s.roleRepo.Update(ctx, id, role); err != nil {
+		return err
+	}
+
+	s.InvalidateCache()
+	if !renamed {
+		return nil
+	}
+
+	userIDs, err := s.roleRepo.GetUserIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := s.revocations.RevokeUserTokens(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := s.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	if isSystemRole(role.Name) {
+		return errors.New("ไม่สามารถลบบทบาทเริ่มต้นของระบบได้")
+	}
+
+	users, err := s.roleRepo.CountUsers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return fmt.Errorf("ยังมีผู้ใช้ %d คนในบทบาทนี้ กรุณาย้ายผู้ใช้ไปบทบาทอื่นก่อน", users)
+	}
+
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.InvalidateCache()
+	return nil
+}
+
+// SetRolePermissions แทนที่สิทธิ์ทั้งหมดของบทบาทด้วยรายชื่อสิทธิ์ที่ส่งมา
+func (s *rbacService) SetRolePermissions(ctx context.Context, id uuid.UUID, req *entities.SetRolePermissionsRequest) (*entities.Role, error) {
+	if _, err := s.roleRepo.GetByID(ctx, id); err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	permissions, err := s.permissionRepo.GetByNames(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]uuid.UUID, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = permission.ID
+	}
+
+	permissionIDs := make([]uuid.UUID, 0, len(found))
+	for _, name := range req.Permissions {
+		permissionID, ok := found[name]
+		if !ok {
+			return nil, fmt.Errorf("ไม่พบสิทธิ์ %s", name)
+		}
+		permissionIDs = append(permissionIDs, permissionID)
+	}
+
+	if err := s.roleRepo.SetPermissions(ctx, id, permissionIDs); err != nil {
+		return nil, err
+	}
+
+	s.InvalidateCache()
+	return s.roleRepo.GetByID(ctx, id)
+}
+
+func (s *rbacService) GetPermissions(ctx context.Context) ([]*entities.Permission, error) {
+	return s.permissionRepo.GetAll(ctx)
+}
+
+func (s *rbacService) CreatePermission(ctx context.Context, req *entities.CreatePermissionRequest) (*entities.Permission, error) {
+	name := strings.TrimSpace(req.Name)
+	if !strings.Contains(name, ":") {
+		return nil, errors.New("ชื่อสิทธิ์ต้องอยู่ในรูปแบบ resource:action")
+	}
+
+	if _, err := s.permissionRepo.GetByName(ctx, name); err == nil {
+		return nil, errors.New("ชื่อสิทธิ์นี้ถูกใช้แล้ว")
+	}
+
+	permission := &entities.Permission{
+		Name:        name,
+		Description: req.Description,
+	}
+	if err := s.permissionRepo.Create(ctx, permission); err != nil {
+		return nil, err
+	}
+
+	return permission, nil
+}
+
+func (s *rbacService) UpdatePermission(ctx context.Context, id uuid.UUID, req *entities.UpdatePermissionRequest) error {
+	if _, err := s.permissionRepo.GetByID(ctx, id); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.permissionRepo.Update(ctx, id, &entities.Permission{Description: req.Description})
+}
+
+func (s *rbacService) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	permission, err := s.permissionRepo.GetByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	// สิทธิ์ใน catalogue ถูกอ้างอิงจาก route จึงลบไม่ได้
+	for _, builtin := range entities.PermissionCatalogue {
+		if builtin.Name == permission.Name {
+			return errors.New("ไม่สามารถลบสิทธิ์ที่ระบบใช้งานอยู่ได้")
+		}
+	}
+
+	if err := s.permissionRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.InvalidateCache()
+	return nil
+}
+
+// AssignUserRole เปลี่ยนบทบาทของผู้ใช้ access token เดิมที่มีบทบาทเก่าถูกยกเลิก ผู้ใช้ต้องรีเฟรช token เพื่อรับบทบาทใหม่
+func (s *rbacService) AssignUserRole(ctx context.Context, userID uuid.UUID, req *entities.AssignRoleRequest) error {
+	if _, err := s.roleRepo.GetByID(ctx, req.RoleID); err != nil {
+		return errors.New("ไม่พบบทบาทที่ระบุ")
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return entities.ErrNotFound
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, req.RoleID); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUserTokens(ctx, userID)
+}
+
+func isSystemRole(name string) bool {
+	return name == entities.RoleAdmin || name == entities.RoleUser
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type shippingService struct {
+	shippingRepo repositories.ShippingRepository
+}
+
+func NewShippingService(shippingRepo repositories.ShippingRepository) services.ShippingService {
+	return &shippingService{
+		shippingRepo: shippingRepo,
+	}
+}
+
+func (s *shippingService) GetMethods(ctx context.Context) ([]*entities.ShippingMethod, error) {
+	return s.shippingRepo.GetMethods(ctx, false)
+}
+
+func (s *shippingService) GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error) {
+	method, err := s.shippingRepo.GetMethodByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	return method, nil
+}
+
+func (s *shippingService) CreateMethod(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error) {
+	code := entities.NormalizeShippingCode(req.Code)
+	if _, err := s.shippingRepo.GetMethodByCode(ctx, code); err == nil {
+		return nil, errors.New("รหัสวิธีจัดส่งนี้ถูกใช้แล้ว")
+	}
+
+	method := &entities.ShippingMethod{
+		Code:              code,
+		Name:              strings.TrimSpace(req.Name),
+		Description:       req.Description,
+		Active:            true,
+		VolumetricDivisor: req.VolumetricDivisor,
+		SortOrder:         req.SortOrder,
+	}
+	if req.Active != nil {
+		method.Active = *req.Active
+	}
+
+	if err := s.shippingRepo.CreateMethod(ctx, method); err != nil {
+		return nil, err
+	}
+
+	return method, nil
+}
+
+func (s *shippingService) UpdateMethod(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) error {
+	method, err := s.shippingRepo.GetMethodByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		method.Name = name
+	}
+	if req.Description != "" {
+		method.Description = req.Description
+	}
+	if req.Active != nil {
+		method.Active = *req.Active
+	}
+	if req.VolumetricDivisor != nil {
+		method.VolumetricDivisor = *req.VolumetricDivisor
+	}
+	if req.SortOrder != nil {
+		method.SortOrder = *req.SortOrder
+	}
+
+	return s.shippingRepo.UpdateMethod(ctx, id, method)
+}
+
+func (s *shippingService) DeleteMethod(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.shippingRepo.GetMethodByID(ctx, id); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.shippingRepo.DeleteMethod(ctx, id)
+}
+
+func (s *shippingService) CreateRate(ctx context.Context, req *entities.CreateShippingRateRequest) (*entities.ShippingRate, error) {
+	method, err := s.shippingRepo.GetMethodByID(ctx, req.ShippingMethodID)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	rate := &entities.ShippingRate{
+		ShippingMethodID: method.ID,
+		Zone:             entities.NormalizeRegion(req.Zone),
+		Type:             req.Type,
+		MinWeightGrams:   req.MinWeightGrams,
+		MaxWeightGrams:   req.MaxWeightGrams,
+		Amount:           req.Amount,
+		PerKg:            req.PerKg,
+		FreeOver:         req.FreeOver,
+	}
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.shippingRepo.CreateRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *shippingService) UpdateRate(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingRateRequest) error {
+	rate, err := s.shippingRepo.GetRateByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	if req.Zone != "" {
+		rate.Zone = entities.NormalizeRegion(req.Zone)
+	}
+	if req.Type != "" {
+		rate.Type = req.Type
+	}
+	if req.MinWeightGrams != nil {
+		rate.MinWeightGrams = *req.MinWeightGrams
+	}
+	if req.MaxWeightGrams != nil {
+		rate.MaxWeightGrams = *req.MaxWeightGrams
+	}
+	if req.Amount != nil {
+		rate.Amount = *req.Amount
+	}
+	if req.PerKg != nil {
+		rate.PerKg = *req.PerKg
+	}
+	if req.FreeOver != nil {
+		rate.FreeOver = *req.FreeOver
+	}
+	if err := rate.Validate(); err != nil {
+		return err
+	}
+
+	return s.shippingRepo.UpdateRate(ctx, id, rate)
+}
+
+func (s *shippingService) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.shippingRepo.GetRateByID(ctx, id); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.shippingRepo.DeleteRate(ctx, id)
+}
+
+// shippingItems น้ำหนักและขนาดของสินค้าในตะกร้าสำหรับคิดค่าจัดส่ง
+func shippingItems(items []entities.CartItem) []entities.ShippingItem {
+	var result []entities.ShippingItem
+	for _, item := range items {
+		if item.Product == nil {
+			continue
+		}
+		result = append(result, entities.ShippingItem{
+			WeightGrams: item.Product.WeightGrams,
+			LengthCm:    item.Product.LengthCm,
+			WidthCm:     item.Product.WidthCm,
+			HeightCm:    item.Product.HeightCm,
+			Quantity:    item.Quantity,
+		})
+	}
+	return result
+}
+
+// shippingRegion ภูมิภาคที่ใช้คิดค่าจัดส่ง หากไม่ระบุใช้ภูมิภาคเริ่มต้นของร้าน
+func shippingRegion(settings entities.TaxSettings, region string) string {
+	if strings.TrimSpace(region) == "" {
+		return settings.DefaultRegion
+	}
+	return region
+}
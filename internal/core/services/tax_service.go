@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type taxService struct {
+	taxRepo repositories.TaxRepository
+}
+
+func NewTaxService(taxRepo repositories.TaxRepository) services.TaxService {
+	return &taxService{
+		taxRepo: taxRepo,
+	}
+}
+
+func (s *taxService) GetClasses(ctx context.Context) ([]*entities.TaxClass, error) {
+	return s.taxRepo.GetClasses(ctx)
+}
+
+func (s *taxService) GetClassByID(ctx context.Context, id uuid.UUID) (*entities.TaxClass, error) {
+	class, err := s.taxRepo.GetClassByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	return class, nil
+}
+
+func (s *taxService) CreateClass(ctx context.Context, req *entities.CreateTaxClassRequest) (*entities.TaxClass, error) {
+	name := strings.TrimSpace(req.Name)
+	if _, err := s.taxRepo.GetClassByName(ctx, name); err == nil {
+		return nil, errors.New("ชื่อประเภทภาษีนี้ถูกใช้แล้ว")
+	}
+
+	class := &entities.TaxClass{
+		Name:        name,
+		Description: req.Description,
+		IsDefault:   req.IsDefault,
+	}
+	if err := s.taxRepo.CreateClass(ctx, class); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+func (s *taxService) UpdateClass(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxClassRequest) error {
+	class, err := s.taxRepo.GetClassByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name != "" && name != class.Name {
+		if _, err := s.taxRepo.GetClassByName(ctx, name); err == nil {
+			return errors.New("ชื่อประเภทภาษีนี้ถูกใช้แล้ว")
+		}
+		class.Name = name
+	}
+	if req.Description != "" {
+		class.Description = req.Description
+	}
+	if req.IsDefault != nil {
+		class.IsDefault = *req.IsDefault
+	}
+
+	return s.taxRepo.UpdateClass(ctx, id, class)
+}
+
+func (s *taxService) DeleteClass(ctx context.Context, id uuid.UUID) error {
+	class, err := s.taxRepo.GetClassByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	if class.IsDefault {
+		return errors.New("ไม่สามารถลบประเภทภาษีเริ่มต้นได้ กรุณากำหนดประเภทเริ่มต้นใหม่ก่อน")
+	}
+
+	usage, err := s.taxRepo.CountClassUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if usage > 0 {
+		return fmt.Errorf("%w (%d รายการ)", entities.ErrTaxClassInUse, usage)
+	}
+
+	return s.taxRepo.DeleteClass(ctx, id)
+}
+
+func (s *taxService) CreateRate(ctx context.Context, req *entities.CreateTaxRateRequest) (*entities.TaxRate, error) {
+	class, err := s.taxRepo.GetClassByID(ctx, req.TaxClassID)
+	if err != nil {
+		return nil, entities.ErrNotFound
+	}
+
+	rate := &entities.TaxRate{
+		TaxClassID: class.ID,
+		Region:     entities.NormalizeRegion(req.Region),
+		Name:       strings.TrimSpace(req.Name),
+		Rate:       req.Rate,
+	}
+	if hasRegion(class.Rates, rate.Region, uuid.Nil) {
+		return nil, fmt.Errorf("%w: ประเภทภาษีนี้มีอัตราของภูมิภาค %s แล้ว", entities.ErrInvalidTaxRate, rate.Region)
+	}
+
+	if err := s.taxRepo.CreateRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *taxService) UpdateRate(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRateRequest) error {
+	rate, err := s.taxRepo.GetRateByID(ctx, id)
+	if err != nil {
+		return entities.ErrNotFound
+	}
+
+	if req.Region != "" {
+		rate.Region = entities.NormalizeRegion(req.Region)
+		class, err := s.taxRepo.GetClassByID(ctx, rate.TaxClassID)
+		if err != nil {
+			return err
+		}
+		if hasRegion(class.Rates, rate.Region, rate.ID) {
+			return fmt.Errorf("%w: ประเภทภาษีนี้มีอัตราของภูมิภาค %s แล้ว", entities.ErrInvalidTaxRate, rate.Region)
+		}
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		rate.Name = name
+	}
+	if req.Rate != nil {
+		rate.Rate = *req.Rate
+	}
+
+	return s.taxRepo.UpdateRate(ctx, id, rate)
+}
+
+func (s *taxService) DeleteRate(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.taxRepo.GetRateByID(ctx, id); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.taxRepo.DeleteRate(ctx, id)
+}
+
+// hasRegion ตรวจว่ามีอัตราของภูมิภาคนี้อยู่แล้วหรือไม่ (ไม่นับอัตรา exceptID)
+func hasRegion(rates []entities.TaxRate, region string, exceptID uuid.UUID) bool {
+	for _, rate := range rates {
+		if rate.Region == region && rate.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteTax หาอัตราภาษีของภูมิภาคที่จัดส่ง หากไม่ระบุภูมิภาคจะใช้ภูมิภาคเริ่มต้นของร้าน
+func quoteTax(ctx context.Context, taxRepo repositories.TaxRepository, settings entities.TaxSettings, region string) (entities.TaxPolicy, error) {
+	if strings.TrimSpace(region) == "" {
+		region = settings.DefaultRegion
+	}
+
+	classes, err := taxRepo.GetClasses(ctx)
+	if err != nil {
+		return entities.TaxPolicy{}, err
+	}
+
+	return entities.BuildTaxPolicy(classes, region, settings.PricesIncludeTax), nil
+}
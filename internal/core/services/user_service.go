@@ -1,22 +1,45 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
 	"math"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/whatup1359/fiber-ecommerce-api/pkg/utils"
 )
 
 type userService struct {
-	userRepo repositories.UserRepository
+	userRepo       repositories.UserRepository
+	storage        gateways.FileStorage
+	revocations    gateways.TokenRevocationStore
+	avatarMaxBytes int64
+	emailChangeTTL time.Duration
+	notifications  services.NotificationService
 }
 
-func NewUserService(userRepo repositories.UserRepository) services.UserService {
+func NewUserService(userRepo repositories.UserRepository, storage gateways.FileStorage, revocations gateways.TokenRevocationStore, notifications services.NotificationService, avatarMaxBytes int64, emailChangeTTL time.Duration) services.UserService {
+	if avatarMaxBytes <= 0 {
+		avatarMaxBytes = entities.DefaultAvatarMaxBytes
+	}
 	return &userService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		storage:        storage,
+		revocations:    revocations,
+		avatarMaxBytes: avatarMaxBytes,
+		emailChangeTTL: emailChangeTTL,
+		notifications:  notifications,
 	}
 }
 
@@ -42,10 +65,183 @@ func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.
 	return s.userRepo.GetByID(ctx, id)
 }
 
+// UpdateUser การปิดบัญชี (active=false) ยกเลิก access token ที่ออกไปแล้วทั้งหมดของผู้ใช้ทันที
 func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, req *entities.UpdateUserRequest) error {
-	return s.userRepo.Update(ctx, id, req)
+	if err := s.userRepo.Update(ctx, id, req); err != nil {
+		return err
+	}
+	if req.Active != nil && !*req.Active {
+		return s.revocations.RevokeUserTokens(ctx, id)
+	}
+	return nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	return s.userRepo.Delete(ctx, id)
-}
\ No newline at end of file
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.revocations.RevokeUserTokens(ctx, id)
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, id uuid.UUID, req *entities.UpdateProfileRequest) (*entities.User, error) {
+	for _, field := range []*string{req.FirstName, req.LastName, req.Phone, req.Address} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	// ชื่อและนามสกุลล้างให้ว่างไม่ได้ ส่วนเบอร์โทรและที่อยู่ล้างได้
+	if req.FirstName != nil && *req.FirstName == "" {
+		req.FirstName = nil
+	}
+	if req.LastName != nil && *req.LastName == "" {
+		req.LastName = nil
+	}
+
+	if err := s.userRepo.UpdateProfile(ctx, id, req); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// UploadAvatar ตรวจชนิดไฟล์จากเนื้อหาจริง (ไม่เชื่อ Content-Type ของ client) แล้วแทนที่รูปเดิม
+func (s *userService) UploadAvatar(ctx context.Context, id uuid.UUID, upload *entities.AvatarUpload) (*entities.User, error) {
+	if upload.Size > s.avatarMaxBytes {
+		return nil, entities.ErrAvatarTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload.Reader, s.avatarMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.avatarMaxBytes {
+		return nil, entities.ErrAvatarTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := entities.AvatarContentTypes[contentType]
+	if !ok {
+		return nil, entities.ErrInvalidAvatar
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "avatars/" + id.String() + "/" + uuid.NewString() + ext
+	url, err := s.storage.Save(ctx, key, contentType, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetAvatar(ctx, id, url); err != nil {
+		_ = s.storage.Delete(ctx, url)
+		return nil, err
+	}
+	s.deleteAvatar(ctx, user.Avatar)
+
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// RequestEmailChange อีเมลเดิมยังใช้เข้าสู่ระบบได้จนกว่าจะยืนยัน token ที่ส่งไปยังอีเมลใหม่
+func (s *userService) RequestEmailChange(ctx context.Context, id uuid.UUID, req *entities.ChangeEmailRequest) error {
+	if err := s.checkPassword(ctx, id, req.Password); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if _, err := s.userRepo.GetByEmail(ctx, newEmail); err == nil || strings.EqualFold(newEmail, user.Email) {
+		return entities.ErrEmailTaken
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SetEmailChange(ctx, id, newEmail, utils.HashToken(token), time.Now().Add(s.emailChangeTTL)); err != nil {
+		return err
+	}
+
+	// ส่ง token ยืนยันไปยังอีเมลใหม่ และแจ้งเตือนไปยังอีเมลเดิม
+	return s.notifications.SendEmailChange(ctx, user, newEmail, token, s.emailChangeTTL)
+}
+
+// ConfirmEmailChange token ต้องเป็นของผู้ใช้ที่เข้าสู่ระบบอยู่ หลังเปลี่ยนอีเมลแล้วทุก session เดิมจะถูกยกเลิก
+func (s *userService) ConfirmEmailChange(ctx context.Context, id uuid.UUID, req *entities.ConfirmEmailChangeRequest) (*entities.User, error) {
+	user, pendingEmail, err := s.userRepo.GetByEmailChangeToken(ctx, utils.HashToken(req.Token))
+	if err != nil || user.ID != id || pendingEmail == "" {
+		return nil, entities.ErrInvalidEmailChangeToken
+	}
+
+	// อีเมลอาจถูกบัญชีอื่นใช้ไประหว่างรอยืนยัน
+	if existing, err := s.userRepo.GetByEmail(ctx, pendingEmail); err == nil && existing.ID != id {
+		return nil, entities.ErrEmailTaken
+	}
+
+	// repository ยกเลิกทุก session ไปพร้อมกัน access token ที่ออกด้วยอีเมลเดิมก็ต้องใช้ไม่ได้เช่นกัน
+	if err := s.userRepo.ConfirmEmailChange(ctx, id, pendingEmail); err != nil {
+		return nil, err
+	}
+	if err := s.revocations.RevokeUserTokens(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// DeleteAccount ลบบัญชีของตัวเองแบบลบข้อมูลส่วนตัว (anonymise) ไม่สามารถกู้คืนได้
+func (s *userService) DeleteAccount(ctx context.Context, id uuid.UUID, req *entities.DeleteAccountRequest) error {
+	if err := s.checkPassword(ctx, id, req.Password); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Anonymize(ctx, id); err != nil {
+		return err
+	}
+	s.deleteAvatar(ctx, user.Avatar)
+
+	return s.revocations.RevokeUserTokens(ctx, id)
+}
+
+// checkPassword ยืนยันรหัสผ่านปัจจุบันก่อนทำรายการที่กระทบบัญชี
+func (s *userService) checkPassword(ctx context.Context, id uuid.UUID, password string) error {
+	hashedPassword, err := s.userRepo.GetPasswordHash(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPassword(password, hashedPassword) {
+		return entities.ErrIncorrectPassword
+	}
+	return nil
+}
+
+// deleteAvatar ลบไฟล์รูปเดิมแบบ best effort ไฟล์ที่ลบไม่สำเร็จไม่ควรทำให้รายการหลักล้มเหลว
+func (s *userService) deleteAvatar(ctx context.Context, url string) {
+	if url == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, url); err != nil {
+		log.Printf("Failed to delete avatar %s: %v", url, err)
+	}
+}
+
+// generateToken token สุ่มแบบ hex สำหรับลิงก์ยืนยันทางอีเมล
+func generateToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepository) services.WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (s *webhookService) CreateEndpoint(ctx context.Context, req *entities.CreateWebhookEndpointRequest) (*entities.WebhookEndpoint, error) {
+	if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &entities.WebhookEndpoint{
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		Secret:      secret,
+	}
+
+	if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	// secret จะแสดงเพียงครั้งเดียวตอนสร้าง
+	return endpoint, nil
+}
+
+func (s *webhookService) GetEndpoints(ctx context.Context, page, limit int) ([]*entities.WebhookEndpoint, *entities.PaginationResponse, error) {
+	endpoints, total, err := s.webhookRepo.GetEndpoints(ctx, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return endpoints, pagination, nil
+}
+
+func (s *webhookService) GetEndpointByID(ctx context.Context, id uuid.UUID) (*entities.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+func (s *webhookService) UpdateEndpoint(ctx context.Context, id uuid.UUID, req *entities.UpdateWebhookEndpointRequest) error {
+	if len(req.EventTypes) > 0 {
+		if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+			return err
+		}
+	}
+
+	return s.webhookRepo.UpdateEndpoint(ctx, id, req)
+}
+
+func (s *webhookService) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	return s.webhookRepo.DeleteEndpoint(ctx, id)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, endpointID uuid.UUID, page, limit int) ([]*entities.WebhookDelivery, *entities.PaginationResponse, error) {
+	deliveries, total, err := s.webhookRepo.GetDeliveriesByEndpoint(ctx, endpointID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return deliveries, pagination, nil
+}
+
+// Redeliver สร้างรายการส่งใหม่จาก delivery เดิม โดยเก็บประวัติเดิมไว้
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entities.WebhookDelivery, error) {
+	original, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.webhookRepo.GetEndpointByID(ctx, original.EndpointID); err != nil {
+		return nil, errors.New("ไม่พบ webhook endpoint ของรายการนี้")
+	}
+
+	delivery := &entities.WebhookDelivery{
+		EndpointID:   original.EndpointID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+
+	if err := s.webhookRepo.CreateDeliveries(ctx, []*entities.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (s *webhookService) generateSecret() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(bytes), nil
+}
+
+// validateWebhookEventTypes ตรวจสอบว่าชนิด event ที่ขอสมัครรองรับหรือไม่
+func validateWebhookEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if eventType == "*" {
+			continue
+		}
+
+		supported := false
+		for _, t := range entities.WebhookEventTypes {
+			if t == eventType {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("ไม่รองรับ event ชนิด %s", eventType)
+		}
+	}
+
+	return nil
+}
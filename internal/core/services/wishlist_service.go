@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/whatup1359/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type wishlistService struct {
+	wishlistRepo repositories.WishlistRepository
+	productRepo  repositories.ProductRepository
+	cartService  services.CartService
+}
+
+// NewWishlistService การย้ายลงตะกร้าใช้ CartService เพื่อให้ราคา สกุลเงิน และการจองสต็อกเหมือนการเพิ่มสินค้าปกติ
+func NewWishlistService(wishlistRepo repositories.WishlistRepository, productRepo repositories.ProductRepository, cartService services.CartService) services.WishlistService {
+	return &wishlistService{
+		wishlistRepo: wishlistRepo,
+		productRepo:  productRepo,
+		cartService:  cartService,
+	}
+}
+
+func (s *wishlistService) GetWishlist(ctx context.Context, userID uuid.UUID) ([]*entities.WishlistItem, error) {
+	return s.wishlistRepo.GetByUserID(ctx, userID)
+}
+
+// AddToWishlist สินค้าที่หมดสต็อกเพิ่มได้ ผู้ใช้จะได้รับแจ้งเตือนเมื่อสินค้ากลับมามีสต็อก
+func (s *wishlistService) AddToWishlist(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) error {
+	if _, err := s.productRepo.GetByID(ctx, req.ProductID); err != nil {
+		return entities.ErrNotFound
+	}
+
+	return s.wishlistRepo.Add(ctx, userID, req.ProductID)
+}
+
+func (s *wishlistService) RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error {
+	if err := s.wishlistRepo.Remove(ctx, userID, productID); err != nil {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+// MoveToCart เพิ่มสินค้าลงตะกร้าแล้วนำออกจากรายการโปรด หากเพิ่มลงตะกร้าไม่ได้ (เช่น สต็อกไม่พอ) สินค้ายังอยู่ในรายการโปรด
+func (s *wishlistService) MoveToCart(ctx context.Context, userID, productID uuid.UUID, req *entities.MoveToCartRequest) error {
+	exists, err := s.wishlistRepo.Exists(ctx, userID, productID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return entities.ErrNotFound
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	if err := s.cartService.AddToCart(ctx, userID, &entities.AddToCartRequest{
+		ProductID: productID,
+		Quantity:  quantity,
+	}); err != nil {
+		return err
+	}
+
+	return s.wishlistRepo.Remove(ctx, userID, productID)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ComputeHMACSHA256 คำนวณลายเซ็น HMAC-SHA256 ของข้อความ (hex)
+func ComputeHMACSHA256(secret string, message []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMACSHA256 ตรวจสอบลายเซ็นแบบ constant time
+func VerifyHMACSHA256(secret string, message []byte, signature string) bool {
+	expected := ComputeHMACSHA256(secret, message)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignWebhookPayload สร้าง header ลายเซ็นรูปแบบ "t=<unix>,v1=<hmac>"
+// โดยเซ็นข้อความ "<unix>.<body>" เพื่อป้องกันการนำ payload เดิมมาใช้ซ้ำ
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	message := append([]byte(ts+"."), body...)
+	return fmt.Sprintf("t=%s,v1=%s", ts, ComputeHMACSHA256(secret, message))
+}
+
+// VerifyWebhookSignature ตรวจสอบ header ลายเซ็นและอายุของ timestamp
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	if ts == "" || sig == "" {
+		return fmt.Errorf("invalid signature header")
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp")
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return fmt.Errorf("signature timestamp outside tolerance")
+		}
+	}
+
+	message := append([]byte(ts+"."), body...)
+	if !VerifyHMACSHA256(secret, message, sig) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
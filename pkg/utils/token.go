@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// HashToken คืน SHA-256 ของ token (hex) สำหรับเก็บในฐานข้อมูลแทน token จริง
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenHashEqual เปรียบเทียบ hash ของ token แบบ constant time
+func TokenHashEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}